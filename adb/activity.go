@@ -0,0 +1,441 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Activity 表示应用中声明了 intent-filter 的 Activity。
+type Activity struct {
+	Name     string // 完整类名，例如 com.example.app.MainActivity
+	Launcher bool   // 是否为启动入口（MAIN + LAUNCHER）
+}
+
+// resolverEntryRe 匹配 Activity Resolver Table 中的组件行，例如：
+// "        5d2a3f1 com.example.app/.MainActivity filter 8a9b0c"
+var resolverEntryRe = regexp.MustCompile(`^\s+[0-9a-f]+ ([\w.]+)/([\w.$]+)(?: filter [0-9a-f]+)?\s*$`)
+
+// ListActivities 列出应用中声明了 intent-filter 的 Activity，并标记启动入口。
+// 解析 'dumpsys package <包名>' 的 Activity Resolver Table，可用于深度链接测试。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//
+// 返回值：
+//   - []Activity: 按出现顺序排列、去重后的 Activity；没有任何 intent-filter 时返回空切片
+//   - error: 如果应用未安装（错误包装了 ErrNotInstalled）或命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - 只能列出声明了 intent-filter 的 Activity，没有 intent-filter 的内部页面不会出现在 dumpsys 中
+//   - 短类名（".MainActivity"）会展开为完整类名
+//
+// 示例：
+//
+//	activities, err := device.ListActivities("com.example.app")
+//	for _, a := range activities {
+//	    fmt.Println(a.Name, a.Launcher)
+//	}
+func (d *Device) ListActivities(pkg string) ([]Activity, error) {
+	pkg = d.packageOr(pkg)
+	output, err := d.Shellf("dumpsys package %s", pkg)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(output, "Package ["+pkg+"]") {
+		return nil, fmt.Errorf("app %s: %w", pkg, ErrNotInstalled)
+	}
+	return parseActivityResolver(output, pkg), nil
+}
+
+// parseActivityResolver 从 dumpsys package 的输出中解析 Activity Resolver Table。
+func parseActivityResolver(output, pkg string) []Activity {
+	activities := []Activity{}
+	index := map[string]int{}
+	inTable := false
+	current := -1
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "Activity Resolver Table:") {
+			inTable = true
+			continue
+		}
+		if !inTable {
+			continue
+		}
+		// 下一个顶层段落（没有缩进的行）表示表格结束
+		if line != "" && line[0] != ' ' {
+			break
+		}
+		if m := resolverEntryRe.FindStringSubmatch(line); m != nil && m[1] == pkg {
+			name := m[2]
+			if strings.HasPrefix(name, ".") {
+				name = pkg + name
+			}
+			i, ok := index[name]
+			if !ok {
+				i = len(activities)
+				index[name] = i
+				activities = append(activities, Activity{Name: name})
+			}
+			current = i
+			continue
+		}
+		if current >= 0 && strings.Contains(line, `Category: "android.intent.category.LAUNCHER"`) {
+			activities[current].Launcher = true
+		}
+	}
+	return activities
+}
+
+// LauncherActivity 返回应用的启动入口 Activity 的完整类名，
+// 可直接传给 StartActivity，调用方不需要知道应用的主 Activity。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//
+// 返回值：
+//   - string: 完整类名，例如 "com.example.app.MainActivity"
+//   - error: 如果应用未安装（ErrNotInstalled）或没有启动入口（ErrNotFound），返回 error 对象
+//
+// 工作原理：
+//   - Android 7.0+ 使用 'cmd package resolve-activity' 直接解析 MAIN + LAUNCHER
+//   - 解析失败或更早的版本时使用 ListActivities 中标记为 Launcher 的第一个 Activity
+//
+// 示例：
+//
+//	activity, err := device.LauncherActivity("com.example.app")
+//	if err == nil {
+//	    device.StartActivity("com.example.app", activity)
+//	}
+func (d *Device) LauncherActivity(pkg string) (string, error) {
+	pkg = d.packageOr(pkg)
+	if d.useCmd() {
+		output, err := d.Cmd("package", "resolve-activity", "--brief",
+			"-a", "android.intent.action.MAIN", "-c", "android.intent.category.LAUNCHER", pkg)
+		if err == nil {
+			lines := strings.Split(output, "\n")
+			if component := strings.TrimSpace(lines[len(lines)-1]); strings.HasPrefix(component, pkg+"/") {
+				name := strings.TrimPrefix(component, pkg+"/")
+				if strings.HasPrefix(name, ".") {
+					name = pkg + name
+				}
+				return name, nil
+			}
+		}
+	}
+
+	activities, err := d.ListActivities(pkg)
+	if err != nil {
+		return "", err
+	}
+	for _, a := range activities {
+		if a.Launcher {
+			return a.Name, nil
+		}
+	}
+	return "", fmt.Errorf("launcher activity of %s: %w", pkg, ErrNotFound)
+}
+
+// launchTimeout 是 Launch 等待应用进入前台的最长时间。
+const launchTimeout = 10 * time.Second
+
+var (
+	// resumedActivityRe 匹配 'dumpsys activity activities' 中前台的 Activity，例如：
+	// "mResumedActivity: ActivityRecord{8b1e3c4 u0 com.example.app/.MainActivity t12}"（Android 9 及以前）
+	// "topResumedActivity=ActivityRecord{8b1e3c4 u0 com.example.app/.MainActivity t12}"（Android 10+）
+	resumedActivityRe = regexp.MustCompile(`(?:mResumedActivity|[rR]esumedActivity)[:=]\s*ActivityRecord\{\S+ \S+ ([\w.]+)/([\w.$]+)`)
+	// focusedWindowRe 匹配 'dumpsys window' 中获得焦点的窗口，例如：
+	// "mCurrentFocus=Window{a1b2c3 u0 com.example.app/com.example.app.MainActivity}"
+	focusedWindowRe = regexp.MustCompile(`mCurrentFocus=Window\{\S+ \S+ ([\w.]+)/([\w.$]+)\}`)
+)
+
+// CurrentActivity 返回当前处于前台的 Activity。
+//
+// 返回值：
+//   - string: 组件名 "包名/完整类名"，例如 "com.example.app/com.example.app.MainActivity"
+//   - error: 如果命令执行失败或无法识别前台 Activity，返回 error 对象
+//
+// 工作原理：
+//   - 读取 'dumpsys activity activities' 中的 mResumedActivity / topResumedActivity
+//   - 找不到时（例如锁屏）使用 'dumpsys window' 中获得焦点的窗口
+//
+// 示例：
+//
+//	activity, err := device.CurrentActivity()
+//	if err == nil {
+//	    fmt.Println("当前页面:", activity)
+//	}
+func (d *Device) CurrentActivity() (string, error) {
+	output, err := d.Shell("dumpsys activity activities")
+	if err != nil {
+		return "", err
+	}
+	m := resumedActivityRe.FindStringSubmatch(output)
+	if m == nil {
+		if output, err = d.Shell("dumpsys window"); err != nil {
+			return "", err
+		}
+		m = focusedWindowRe.FindStringSubmatch(output)
+	}
+	if m == nil {
+		return "", fmt.Errorf("resumed activity: %w", ErrNotFound)
+	}
+	pkg, name := m[1], m[2]
+	if strings.HasPrefix(name, ".") {
+		name = pkg + name
+	}
+	return pkg + "/" + name, nil
+}
+
+// CurrentPackage 返回当前处于前台的应用包名。
+//
+// 返回值：
+//   - string: 包名，例如 "com.example.app"
+//   - error: 如果无法识别前台应用，返回 error 对象
+//
+// 示例：
+//
+//	if pkg, _ := device.CurrentPackage(); pkg != "com.example.app" {
+//	    device.Launch("com.example.app")
+//	}
+func (d *Device) CurrentPackage() (string, error) {
+	activity, err := d.CurrentActivity()
+	if err != nil {
+		return "", err
+	}
+	pkg, _, _ := strings.Cut(activity, "/")
+	return pkg, nil
+}
+
+// Launch 打开应用的启动入口，相当于在桌面上点击应用图标，不需要知道主 Activity 的名称。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//
+// 返回值：
+//   - error: 如果应用没有可启动的 Activity（错误包装了 ErrNotFound）、
+//     启动失败或 10 秒内没有进入前台，返回 error 对象
+//
+// 工作原理：
+//  1. 执行 'monkey -p <包名> -c android.intent.category.LAUNCHER 1' 发送一次启动事件
+//  2. 轮询 CurrentPackage，确认应用已经进入前台
+//
+// 注意事项：
+//   - 应用已在后台运行时会切回前台，不会重新创建 Activity
+//   - 需要冷启动时先调用 ForceStopApp
+//
+// 示例：
+//
+//	device.ForceStopApp("com.example.app")
+//	if err := device.Launch("com.example.app"); err != nil {
+//	    log.Fatal("启动失败:", err)
+//	}
+func (d *Device) Launch(pkg string) error {
+	pkg = d.packageOr(pkg)
+	output, err := d.Shellf("monkey -p %s -c android.intent.category.LAUNCHER 1", pkg)
+	if strings.Contains(output+errString(err), "No activities found") {
+		return fmt.Errorf("launch %s: no launchable activity: %w", pkg, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(output, "Events injected: 1") {
+		return fmt.Errorf("launch %s failed: %s", pkg, truncate(output, 200))
+	}
+
+	deadline := time.Now().Add(launchTimeout)
+	for {
+		current, err := d.CurrentPackage()
+		if err == nil && current == pkg {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("launch %s: not in foreground after %s (current %q)", pkg, launchTimeout, current)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// AssertActivity 检查当前前台的 Activity 是否符合预期，用于在测试中确认页面跳转的结果。
+//
+// 参数：
+//   - pkg: 期望的包名，为空时使用默认包名；两者都为空时不检查包名
+//   - activity: 期望的 Activity，可以是完整类名，也可以是类名的前缀或后缀，
+//     例如 "DashboardActivity"、".ui.DashboardActivity"、"com.example.app.ui."；为空时只检查包名
+//
+// 返回值：
+//   - error: 不符合时返回包含期望值和实际值的 error 对象，
+//     例如 "expected activity DashboardActivity, got com.example.app/com.example.app.LoginActivity"
+//
+// 示例：
+//
+//	device.ClickButton("登录")
+//	if err := device.AssertActivity("com.example.app", "DashboardActivity"); err != nil {
+//	    t.Fatal(err)
+//	}
+func (d *Device) AssertActivity(pkg, activity string) error {
+	current, err := d.CurrentActivity()
+	if err != nil {
+		return err
+	}
+	pkg = d.packageOr(pkg)
+	if !activityMatches(current, pkg, activity) {
+		want := activity
+		if pkg != "" {
+			want = pkg + "/" + activity
+		}
+		return fmt.Errorf("expected activity %s, got %s", want, current)
+	}
+	return nil
+}
+
+// WaitAndAssertActivity 轮询等待指定的 Activity 进入前台，匹配规则与 AssertActivity 相同。
+//
+// 参数：
+//   - pkg: 期望的包名，为空时使用默认包名
+//   - activity: 期望的 Activity（完整类名、前缀或后缀）
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - error: 超时时返回 error 对象，包含最后一次看到的实际 Activity
+//
+// 示例：
+//
+//	if err := device.WaitAndAssertActivity("", "DashboardActivity", 10*time.Second); err != nil {
+//	    t.Fatal(err) // 例如：wait for activity: timeout after 10s: expected activity ..., got ...
+//	}
+func (d *Device) WaitAndAssertActivity(pkg, activity string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := d.AssertActivity(pkg, activity)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("wait for activity: timeout after %s: %w", timeout, err)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// activityMatches 判断组件名 "包名/类名" 是否符合期望的包名和 Activity。
+func activityMatches(component, pkg, activity string) bool {
+	gotPkg, class, _ := strings.Cut(component, "/")
+	if pkg != "" && gotPkg != pkg {
+		return false
+	}
+	return activity == "" || strings.HasPrefix(class, activity) || strings.HasSuffix(class, activity)
+}
+
+// ActivityRecord 是任务栈中的一个 Activity。
+type ActivityRecord struct {
+	Package  string // 包名
+	Activity string // 完整类名，例如 com.example.app.DetailActivity
+	TaskID   int    // 所属任务 ID
+	State    string // 生命周期状态，例如 RESUMED、PAUSED、STOPPED；无法识别时为空
+	Raw      string // dumpsys 中该记录的原始文本，用于查看未解析的字段
+}
+
+var (
+	// histRecordRe 匹配任务栈中的 Activity 记录，例如：
+	// "    * Hist #1: ActivityRecord{8b1e3c4 u0 com.example.app/.DetailActivity t12}"（Android 10+）
+	// "      Hist #1: ActivityRecord{8b1e3c4 u0 com.example.app/.DetailActivity t12}"（Android 9 及以前）
+	histRecordRe = regexp.MustCompile(`^\s*(?:\* )?Hist #\d+: ActivityRecord\{\S+ \S+ ([\w.]+)/([\w.$]+)(?: t(\d+))?`)
+	// runRecordRe 匹配 "Running activities" 列表中的记录，部分版本只有该列表，例如：
+	// "      Run #0: ActivityRecord{8b1e3c4 u0 com.example.app/.DetailActivity t12}"
+	runRecordRe = regexp.MustCompile(`^\s*Run #\d+: ActivityRecord\{\S+ \S+ ([\w.]+)/([\w.$]+)(?: t(\d+))?`)
+	// activityStateRe 匹配记录详情中的状态，"state=STOPPED"（Android 11 以前）或 "mState=STOPPED"
+	activityStateRe = regexp.MustCompile(`\bm?[sS]tate=([A-Z_]+)`)
+)
+
+// ActivityStack 返回完整的 Activity 任务栈，用于排查 "按返回键没有回到预期页面" 或验证深度链接的跳转结果。
+//
+// 返回值：
+//   - []ActivityRecord: 从上到下排列的 Activity（第一个为最上层），多个任务时最近使用的任务在前
+//   - error: 如果命令执行失败或输出中没有任何 Activity 记录，返回 error 对象
+//
+// 工作原理：
+//   - 解析 'dumpsys activity activities' 中的 "Hist #N: ActivityRecord{...}" 记录及其后的详情，
+//     详情中的 state= / mState= 作为状态
+//   - 没有 Hist 记录时退回到 "Run #N" 列表，此时只有前台 Activity 能通过 mResumedActivity 标记为 RESUMED
+//
+// 兼容性：
+//   - Android 9 的记录按 Stack / TaskRecord 分组，Android 10-11 改为 ActivityStack / Task，
+//     Android 12+ 为 Task 嵌套，各版本的记录行格式相同，因此都能解析
+//   - 各版本的详情字段不同，未解析的内容保存在 Raw 中
+//
+// 示例：
+//
+//	stack, err := device.ActivityStack()
+//	if err == nil {
+//	    for _, r := range stack {
+//	        fmt.Printf("t%d %s/%s %s\n", r.TaskID, r.Package, r.Activity, r.State)
+//	    }
+//	}
+func (d *Device) ActivityStack() ([]ActivityRecord, error) {
+	output, err := d.Shell("dumpsys activity activities")
+	if err != nil {
+		return nil, err
+	}
+	records := parseActivityStack(output, histRecordRe)
+	if len(records) == 0 {
+		records = parseActivityStack(output, runRecordRe)
+		if m := resumedActivityRe.FindStringSubmatch(output); m != nil {
+			for i := range records {
+				if activityMatches(records[i].Package+"/"+records[i].Activity, m[1], m[2]) {
+					records[i].State = "RESUMED"
+				}
+			}
+		}
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no activity record in dumpsys output: %s", truncate(output, 200))
+	}
+	return records, nil
+}
+
+// parseActivityStack 按 recordRe 解析 Activity 记录，每条记录的详情到下一条记录或缩进更少的行为止。
+func parseActivityStack(output string, recordRe *regexp.Regexp) []ActivityRecord {
+	var records []ActivityRecord
+	var raw []string
+	indent := 0
+	flush := func() {
+		if len(records) > 0 && raw != nil {
+			records[len(records)-1].Raw = strings.Join(raw, "\n")
+		}
+		raw = nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := recordRe.FindStringSubmatch(line); m != nil {
+			flush()
+			pkg, name := m[1], m[2]
+			if strings.HasPrefix(name, ".") {
+				name = pkg + name
+			}
+			task, _ := strconv.Atoi(m[3])
+			records = append(records, ActivityRecord{Package: pkg, Activity: name, TaskID: task})
+			raw = []string{line}
+			indent = len(line) - len(strings.TrimLeft(line, " "))
+			continue
+		}
+		if raw == nil {
+			continue
+		}
+		// 缩进不多于记录行时，记录详情结束
+		if strings.TrimSpace(line) == "" || len(line)-len(strings.TrimLeft(line, " ")) <= indent {
+			flush()
+			continue
+		}
+		raw = append(raw, line)
+		if r := &records[len(records)-1]; r.State == "" {
+			if m := activityStateRe.FindStringSubmatch(line); m != nil {
+				r.State = m[1]
+			}
+		}
+	}
+	flush()
+	return records
+}
@@ -3,9 +3,14 @@
 package adb
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Device 代表通过 ADB 连接的 Android 设备实例。
@@ -14,6 +19,9 @@ import (
 //
 // 字段说明：
 //   - Serial: 设备的序列号，可通过 'adb devices' 命令查看
+//   - RotateCoords: 为 true 时，Tap / Swipe 传入的坐标按竖屏（自然方向）理解，
+//     并自动转换到当前屏幕旋转方向下的坐标，详见 RotatePoint
+//   - ImplicitWait: 查找节点时的隐式等待时间，类似 Selenium 的 implicit wait
 //
 // 示例：
 //
@@ -22,8 +30,35 @@ import (
 //
 //	// 创建指定序列号的设备
 //	device := adb.NewDevice("emulator-5554")
+//
+//	// 需要超时、adb 路径等更多配置时使用 NewDeviceWithOptions
+//	device := adb.NewDeviceWithOptions(adb.WithSerial("emulator-5554"), adb.WithTimeout(30*time.Second))
 type Device struct {
-	Serial string // 设备序列号，为空时使用默认设备
+	Serial       string // 设备序列号，为空时使用默认设备
+	RotateCoords bool   // 是否将竖屏坐标自动转换为当前旋转方向的坐标
+
+	// ImplicitWait 大于 0 时，FindNode、FindNodes 以及基于它们的 ClickNode、ClickButton 等方法
+	// 在找不到节点时会每隔轮询间隔（默认 500 毫秒）重新 dump 一次，最多等待该时长后才返回 ErrNotFound。
+	// 为 0（默认）时只查找一次。WaitForElement、WaitForText 等显式等待方法使用自己的超时，不受它影响。
+	ImplicitWait time.Duration
+
+	// 以下字段通过 NewDeviceWithOptions 的 Option 设置，零值表示使用默认行为，
+	// 新增配置字段时需要同步到 Device.WithSerial
+	adbPath        string        // adb 可执行文件路径，为空时使用 PATH 中的 "adb"
+	timeout        time.Duration // 单条命令的超时时间，为 0 时不限制
+	runner         Runner        // 命令执行器，为 nil 时直接执行本地进程
+	defaultPackage string        // 默认应用包名
+	storageCheck   bool          // Push / 安装前是否检查设备可用空间
+	pollInterval   time.Duration // 轮询等待类方法两次检查之间的间隔，为 0 时使用 500 毫秒
+
+	// 以下字段为查询结果缓存，由 mu 保护
+	mu               sync.Mutex
+	screenW, screenH int               // 自然方向的屏幕尺寸，0 表示尚未缓存
+	sdk              int               // API 级别，0 表示尚未缓存
+	props            map[string]string // getprop 的完整输出，nil 表示尚未缓存，只用于读取 ro.* 属性
+
+	// undo 是 Cleanup 使用的恢复操作栈，同样由 mu 保护，不会被 WithSerial 复制
+	undo []undoStep
 }
 
 // NewDevice 创建一个新的 Device 实例。
@@ -80,8 +115,8 @@ func NewDevice(serial ...string) *Device {
 // 执行流程：
 //  1. 检查是否指定了设备序列号，如果指定了则添加 "-s serial" 参数
 //  2. 拼接完整的命令参数
-//  3. 执行命令并获取输出（包括标准输出和标准错误）
-//  4. 如果执行失败，返回错误信息
+//  3. 通过 Runner 执行命令（默认直接执行本地 adb 进程）并获取输出（包括标准输出和标准错误）
+//  4. 如果执行失败或超时，返回错误信息
 //  5. 返回去除首尾空白的输出结果
 //
 // 错误处理：
@@ -89,7 +124,7 @@ func NewDevice(serial ...string) *Device {
 //   - 这有助于诊断问题，如设备未连接、权限不足等
 //
 // 注意事项：
-//   - 该方法使用 CombinedOutput()，同时捕获标准输出和标准错误
+//   - 同时捕获标准输出和标准错误，标准错误拼接在标准输出之后
 //   - 输出会自动去除首尾的空白字符（空格、换行符等）
 //   - 如果设备未连接或 ADB 未安装，会返回相应错误
 //
@@ -102,28 +137,133 @@ func NewDevice(serial ...string) *Device {
 //	}
 //	fmt.Println("Android version:", output)
 func (d *Device) execCommand(args ...string) (string, error) {
-	// 初始化命令参数切片
-	cmdArgs := []string{}
+	// 执行命令，"-s serial" 参数由 run 统一添加
+	stdout, stderr, err := d.run(nil, args...)
 
-	// 如果指定了设备序列号，添加 "-s serial" 参数
-	// 这确保命令在正确的设备上执行
-	if d.Serial != "" {
-		cmdArgs = append(cmdArgs, "-s", d.Serial)
+	// 合并标准输出和标准错误
+	output := append(stdout, stderr...)
+	if err != nil {
+		// 命令执行失败，返回详细的错误信息
+		return "", fmt.Errorf("adb command failed: %w, output: %s", err, string(output))
 	}
 
-	// 将传入的命令参数追加到参数列表
-	cmdArgs = append(cmdArgs, args...)
+	// 返回去除首尾空白字符的输出结果
+	return strings.TrimSpace(string(output)), nil
+}
 
-	// 创建 ADB 命令
-	cmd := exec.Command("adb", cmdArgs...)
+// execRaw 执行 ADB 命令并返回原始的标准输出字节。
+// 与 execCommand 不同，该方法不合并标准错误、不去除空白，适用于截图等二进制数据。
+//
+// 参数：
+//   - args: 要执行的 ADB 命令参数（可变参数）
+//
+// 返回值：
+//   - []byte: 命令的原始标准输出
+//   - error: 如果命令执行失败，返回包含标准错误内容的 error 对象
+func (d *Device) execRaw(args ...string) ([]byte, error) {
+	// 标准输出原样返回，标准错误只用于错误信息
+	output, stderr, err := d.run(nil, args...)
+	if err != nil {
+		return nil, fmt.Errorf("adb command failed: %w, output: %s", err, string(stderr))
+	}
+	return output, nil
+}
 
-	// 执行命令并获取输出（包括 stdout 和 stderr）
-	output, err := cmd.CombinedOutput()
+// execStdin 执行 ADB 命令，并把 stdin 的内容写入命令的标准输入。
+// 输出处理方式与 execCommand 相同（合并标准错误、去除首尾空白）。
+// 读取完 stdin 后，标准输入管道由 exec 包负责关闭，设备端命令会收到 EOF。
+func (d *Device) execStdin(stdin io.Reader, args ...string) (string, error) {
+	stdout, stderr, err := d.run(stdin, args...)
+	output := append(stdout, stderr...)
 	if err != nil {
-		// 命令执行失败，返回详细的错误信息
 		return "", fmt.Errorf("adb command failed: %w, output: %s", err, string(output))
 	}
-
-	// 返回去除首尾空白字符的输出结果
 	return strings.TrimSpace(string(output)), nil
 }
+
+// run 通过 Runner 执行一条针对当前设备的 adb 命令，并应用超时设置。
+func (d *Device) run(stdin io.Reader, args ...string) (stdout, stderr []byte, err error) {
+	return d.runAdb(context.Background(), stdin, d.adbArgs(args...))
+}
+
+// runContext 与 run 相同，ctx 取消时提前结束 adb 进程。
+func (d *Device) runContext(ctx context.Context, stdin io.Reader, args ...string) (stdout, stderr []byte, err error) {
+	return d.runAdb(ctx, stdin, d.adbArgs(args...))
+}
+
+// runAdb 通过 Runner 执行 adb，参数原样传递（不添加 "-s serial"），并应用超时设置。
+// 用于 'adb devices' 等针对 adb 服务端而不是某一台设备的命令。
+func (d *Device) runAdb(ctx context.Context, stdin io.Reader, args []string) (stdout, stderr []byte, err error) {
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	runner := d.runner
+	if runner == nil {
+		runner = execRunner{}
+	}
+	stdout, stderr, err = runner.Run(ctx, d.adb(), args, stdin)
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timeout after %s: %w", d.timeout, ctx.Err())
+	}
+	return stdout, stderr, err
+}
+
+// stream 执行一条针对当前设备的 adb 命令，把标准输出原样写入 w，并应用超时设置。
+// 输出不在内存中缓存，适用于截图、录屏、tar 等大块二进制数据。
+// 设置了自定义 Runner 时通过 Runner 执行，此时输出会先缓存再写入 w。
+func (d *Device) stream(w io.Writer, args ...string) error {
+	if d.runner != nil {
+		stdout, stderr, err := d.run(nil, args...)
+		if err != nil {
+			return fmt.Errorf("adb command failed: %w, output: %s", err, string(stderr))
+		}
+		_, err = w.Write(stdout)
+		return err
+	}
+
+	ctx := context.Background()
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	var stderr bytes.Buffer
+	cmd := d.commandContext(ctx, args...)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timeout after %s: %w", d.timeout, ctx.Err())
+	}
+	if err != nil {
+		return fmt.Errorf("adb command failed: %w, output: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// commandContext 构建一条针对当前设备的本地 adb 进程，ctx 取消时会结束进程。
+// 用于需要流式读取输出的长时间命令，不经过 Runner，也不应用超时设置。
+func (d *Device) commandContext(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, d.adb(), d.adbArgs(args...)...)
+}
+
+// adb 返回 adb 可执行文件路径。
+func (d *Device) adb() string {
+	if d.adbPath != "" {
+		return d.adbPath
+	}
+	return "adb"
+}
+
+// adbArgs 在命令参数前加上 "-s serial"（如果指定了序列号）。
+func (d *Device) adbArgs(args ...string) []string {
+	cmdArgs := []string{}
+	if d.Serial != "" {
+		cmdArgs = append(cmdArgs, "-s", d.Serial)
+	}
+	return append(cmdArgs, args...)
+}
@@ -0,0 +1,216 @@
+package adb
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"unicode"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// AnnotateOptions 是 AnnotatedScreenshot 的选项，零值表示标注所有节点并显示标签。
+type AnnotateOptions struct {
+	Filter     FindNodeFunc // 只标注匹配的节点，为 nil 时标注所有节点
+	HideLabels bool         // 为 true 时只画边框，不显示 resource-id / text 标签
+}
+
+// 标注使用的颜色与尺寸。
+var (
+	annotateClickable = color.RGBA{0, 200, 0, 255}   // 可点击节点：绿色
+	annotateOther     = color.RGBA{0, 120, 255, 255} // 其他节点：蓝色
+	annotateLabelBg   = color.RGBA{0, 0, 0, 200}     // 标签背景
+	annotateLabelFg   = color.RGBA{255, 255, 255, 255}
+)
+
+const (
+	annotateBorder   = 3  // 边框宽度（像素）
+	annotateScale    = 3  // 标签字体放大倍数，3x5 点阵放大后每个字符 9x15 像素
+	annotateMaxLabel = 32 // 标签最多显示的字符数
+)
+
+// AnnotatedScreenshot 截取屏幕，并把 UI 树中每个节点的边框和标签画在截图上，
+// 用于排查"为什么选择器没有找到元素"这类问题，适合作为失败用例的调试附件。
+//
+// 参数：
+//   - opts: 标注选项，Filter 用于只高亮关心的节点
+//
+// 返回值：
+//   - []byte: 标注后的 PNG 图片
+//   - error: 如果获取 UI 结构、截图或编解码失败，返回 error 对象
+//
+// 标注规则：
+//   - 可点击的节点画绿色边框，其他节点画蓝色边框
+//   - 标签优先显示 resource-id 的短名称，没有时显示 text，再没有时显示 content-desc
+//   - 标签使用内置的 3x5 点阵字体，只能显示 ASCII 字母、数字和 - _ . / : 等符号，
+//     字母统一显示为大写，其他字符（例如中文）显示为 ?
+//
+// 注意事项：
+//   - 只依赖标准库（image、image/draw、image/png）
+//   - UI dump 和截图不是同一时刻获取的，界面在变化时边框可能与内容错位
+//
+// 示例：
+//
+//	data, err := device.AnnotatedScreenshot(adb.AnnotateOptions{})
+//	if err == nil {
+//	    os.WriteFile("artifacts/annotated.png", data, 0644)
+//	}
+//
+//	// 只高亮可点击的节点
+//	data, err = device.AnnotatedScreenshot(adb.AnnotateOptions{
+//	    Filter: func(n, pn uixml.Node) bool { return n.IsClickable() },
+//	})
+func (d *Device) AnnotatedScreenshot(opts AnnotateOptions) ([]byte, error) {
+	xml, err := d.XML()
+	if err != nil {
+		return nil, err
+	}
+	data, err := d.Screenshot()
+	if err != nil {
+		return nil, err
+	}
+	src, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	filter := opts.Filter
+	if filter == nil {
+		filter = func(n, pn uixml.Node) bool { return true }
+	}
+	for _, n := range xml.FindAll(filter) {
+		r, err := uixml.ParseBounds(n.Bounds)
+		if err != nil || r.Width() <= 0 || r.Height() <= 0 {
+			continue
+		}
+		rect := image.Rect(r.X1, r.Y1, r.X2, r.Y2)
+		c := annotateOther
+		if n.IsClickable() {
+			c = annotateClickable
+		}
+		drawBorder(img, rect, c)
+		if !opts.HideLabels {
+			drawLabel(img, rect.Min, nodeLabel(n))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// nodeLabel 返回节点的标签文本。
+func nodeLabel(n uixml.Node) string {
+	label := n.ShortID()
+	if label == "" {
+		label = n.Text
+	}
+	if label == "" {
+		label = n.ContentDesc
+	}
+	if r := []rune(label); len(r) > annotateMaxLabel {
+		label = string(r[:annotateMaxLabel])
+	}
+	return label
+}
+
+// drawBorder 在 img 上画出矩形边框。
+func drawBorder(img *image.RGBA, r image.Rectangle, c color.Color) {
+	u := image.NewUniform(c)
+	t := annotateBorder
+	for _, edge := range []image.Rectangle{
+		image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+t),
+		image.Rect(r.Min.X, r.Max.Y-t, r.Max.X, r.Max.Y),
+		image.Rect(r.Min.X, r.Min.Y, r.Min.X+t, r.Max.Y),
+		image.Rect(r.Max.X-t, r.Min.Y, r.Max.X, r.Max.Y),
+	} {
+		draw.Draw(img, edge.Intersect(img.Bounds()), u, image.Point{}, draw.Src)
+	}
+}
+
+// drawLabel 在 pt 位置画出带背景的标签文本。
+func drawLabel(img *image.RGBA, pt image.Point, text string) {
+	if text == "" {
+		return
+	}
+	s := annotateScale
+	// 每个字符 3 列点阵加 1 列间距，上下左右各留 1 个点的边距
+	w := (len([]rune(text))*4 + 1) * s
+	h := 7 * s
+	bg := image.Rect(pt.X, pt.Y, pt.X+w, pt.Y+h).Intersect(img.Bounds())
+	draw.Draw(img, bg, image.NewUniform(annotateLabelBg), image.Point{}, draw.Over)
+
+	x := pt.X + s
+	for _, r := range strings.ToUpper(text) {
+		glyph, ok := glyphs[r]
+		if !ok {
+			glyph = glyphs['?']
+			if unicode.IsSpace(r) {
+				glyph = [5]uint8{}
+			}
+		}
+		for row := 0; row < 5; row++ {
+			for col := 0; col < 3; col++ {
+				if glyph[row]&(0b100>>col) == 0 {
+					continue
+				}
+				dot := image.Rect(x+col*s, pt.Y+(row+1)*s, x+(col+1)*s, pt.Y+(row+2)*s)
+				draw.Draw(img, dot.Intersect(img.Bounds()), image.NewUniform(annotateLabelFg), image.Point{}, draw.Src)
+			}
+		}
+		x += 4 * s
+	}
+}
+
+// glyphs 是 3x5 点阵字体，每个字符 5 行，每行低 3 位表示从左到右的 3 个点。
+var glyphs = map[rune][5]uint8{
+	'0': {0b111, 0b101, 0b101, 0b101, 0b111},
+	'1': {0b010, 0b110, 0b010, 0b010, 0b111},
+	'2': {0b111, 0b001, 0b111, 0b100, 0b111},
+	'3': {0b111, 0b001, 0b111, 0b001, 0b111},
+	'4': {0b101, 0b101, 0b111, 0b001, 0b001},
+	'5': {0b111, 0b100, 0b111, 0b001, 0b111},
+	'6': {0b111, 0b100, 0b111, 0b101, 0b111},
+	'7': {0b111, 0b001, 0b001, 0b001, 0b001},
+	'8': {0b111, 0b101, 0b111, 0b101, 0b111},
+	'9': {0b111, 0b101, 0b111, 0b001, 0b111},
+	'A': {0b010, 0b101, 0b111, 0b101, 0b101},
+	'B': {0b110, 0b101, 0b110, 0b101, 0b110},
+	'C': {0b011, 0b100, 0b100, 0b100, 0b011},
+	'D': {0b110, 0b101, 0b101, 0b101, 0b110},
+	'E': {0b111, 0b100, 0b110, 0b100, 0b111},
+	'F': {0b111, 0b100, 0b110, 0b100, 0b100},
+	'G': {0b011, 0b100, 0b101, 0b101, 0b011},
+	'H': {0b101, 0b101, 0b111, 0b101, 0b101},
+	'I': {0b111, 0b010, 0b010, 0b010, 0b111},
+	'J': {0b001, 0b001, 0b001, 0b101, 0b010},
+	'K': {0b101, 0b101, 0b110, 0b101, 0b101},
+	'L': {0b100, 0b100, 0b100, 0b100, 0b111},
+	'M': {0b101, 0b111, 0b111, 0b101, 0b101},
+	'N': {0b110, 0b101, 0b101, 0b101, 0b101},
+	'O': {0b010, 0b101, 0b101, 0b101, 0b010},
+	'P': {0b110, 0b101, 0b110, 0b100, 0b100},
+	'Q': {0b010, 0b101, 0b101, 0b110, 0b011},
+	'R': {0b110, 0b101, 0b110, 0b101, 0b101},
+	'S': {0b011, 0b100, 0b010, 0b001, 0b110},
+	'T': {0b111, 0b010, 0b010, 0b010, 0b010},
+	'U': {0b101, 0b101, 0b101, 0b101, 0b111},
+	'V': {0b101, 0b101, 0b101, 0b101, 0b010},
+	'W': {0b101, 0b101, 0b111, 0b111, 0b101},
+	'X': {0b101, 0b101, 0b010, 0b101, 0b101},
+	'Y': {0b101, 0b101, 0b010, 0b010, 0b010},
+	'Z': {0b111, 0b001, 0b010, 0b100, 0b111},
+	'-': {0b000, 0b000, 0b111, 0b000, 0b000},
+	'_': {0b000, 0b000, 0b000, 0b000, 0b111},
+	'.': {0b000, 0b000, 0b000, 0b000, 0b010},
+	'/': {0b001, 0b001, 0b010, 0b100, 0b100},
+	':': {0b000, 0b010, 0b000, 0b010, 0b000},
+	'?': {0b111, 0b001, 0b010, 0b000, 0b010},
+}
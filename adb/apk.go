@@ -0,0 +1,273 @@
+package adb
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"unicode/utf16"
+)
+
+// aaptPackageRe 匹配 'aapt dump badging' 输出的第一行，例如：
+// "package: name='com.example.app' versionCode='42' versionName='1.2.3' ..."
+var aaptPackageRe = regexp.MustCompile(`package: name='([^']*)' versionCode='(\d*)' versionName='([^']*)'`)
+
+// ApkInfo 读取本地 APK 的包名和版本信息。
+//
+// 参数：
+//   - path: 本地 APK 路径
+//
+// 返回值：
+//   - AppInfo: 只包含 Package、VersionCode、VersionName
+//   - error: 如果文件不是有效的 APK，返回 error 对象
+//
+// 工作原理：
+//   - 本机 PATH 中有 aapt 时使用 'aapt dump badging'
+//   - 否则直接解析 APK 中二进制格式的 AndroidManifest.xml
+//
+// 示例：
+//
+//	info, err := adb.ApkInfo("app-release.apk")
+//	if err == nil {
+//	    fmt.Println(info.Package, info.VersionCode, info.VersionName)
+//	}
+func ApkInfo(path string) (AppInfo, error) {
+	if aapt, err := exec.LookPath("aapt"); err == nil {
+		output, err := exec.Command(aapt, "dump", "badging", path).Output()
+		if m := aaptPackageRe.FindSubmatch(output); err == nil && m != nil {
+			code, _ := strconv.ParseInt(string(m[2]), 10, 64)
+			return AppInfo{Package: string(m[1]), VersionCode: code, VersionName: string(m[3])}, nil
+		}
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return AppInfo{}, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name != "AndroidManifest.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return AppInfo{}, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return AppInfo{}, err
+		}
+		return parseManifest(data)
+	}
+	return AppInfo{}, fmt.Errorf("AndroidManifest.xml not found in %s", path)
+}
+
+// 二进制 XML（AXML）中的块类型与资源 ID
+const (
+	axmlStringPool   = 0x0001
+	axmlResourceMap  = 0x0180
+	axmlStartElement = 0x0102
+
+	attrVersionCode      = 0x0101021b // android:versionCode
+	attrVersionName      = 0x0101021c // android:versionName
+	attrVersionCodeMajor = 0x01010576 // android:versionCodeMajor
+
+	typeString = 0x03 // Res_value::TYPE_STRING
+	typeIntDec = 0x10 // Res_value::TYPE_INT_DEC
+	typeIntHex = 0x11 // Res_value::TYPE_INT_HEX
+)
+
+// parseManifest 从二进制 AndroidManifest.xml 中读取 <manifest> 元素的包名和版本属性。
+// 属性名优先通过资源 ID 识别，兼容混淆后属性名被清空的 APK。
+func parseManifest(data []byte) (AppInfo, error) {
+	le := binary.LittleEndian
+	if len(data) < 8 || le.Uint16(data) != 0x0003 {
+		return AppInfo{}, fmt.Errorf("bad binary xml header")
+	}
+
+	var strs []string
+	var resIDs []uint32
+	var info AppInfo
+	var major int64
+
+	// 跳过文件头，依次遍历各个块
+	for off := int(le.Uint16(data[2:])); off+8 <= len(data); {
+		typ := le.Uint16(data[off:])
+		headerSize := int(le.Uint16(data[off+2:]))
+		size := int(le.Uint32(data[off+4:]))
+		if size < 8 || off+size > len(data) {
+			return AppInfo{}, fmt.Errorf("bad binary xml chunk at %d", off)
+		}
+		chunk := data[off : off+size]
+
+		switch typ {
+		case axmlStringPool:
+			strs = parseStringPool(chunk)
+		case axmlResourceMap:
+			for i := headerSize; i+4 <= size; i += 4 {
+				resIDs = append(resIDs, le.Uint32(chunk[i:]))
+			}
+		case axmlStartElement:
+			if size < headerSize+20 {
+				break
+			}
+			ext := chunk[headerSize:]
+			if lookup(strs, le.Uint32(ext[4:])) != "manifest" {
+				break
+			}
+			attrStart := int(le.Uint16(ext[8:]))
+			attrSize := int(le.Uint16(ext[10:]))
+			attrCount := int(le.Uint16(ext[12:]))
+			for i := 0; i < attrCount; i++ {
+				a := headerSize + attrStart + i*attrSize
+				if a+20 > size {
+					break
+				}
+				nameIdx := le.Uint32(chunk[a+4:])
+				raw := le.Uint32(chunk[a+8:])
+				dataType := chunk[a+15]
+				value := le.Uint32(chunk[a+16:])
+
+				var id uint32
+				if int(nameIdx) < len(resIDs) {
+					id = resIDs[nameIdx]
+				}
+				name := lookup(strs, nameIdx)
+				switch {
+				case id == attrVersionCode || name == "versionCode":
+					if dataType == typeIntDec || dataType == typeIntHex {
+						info.VersionCode = int64(value)
+					} else {
+						info.VersionCode, _ = strconv.ParseInt(lookup(strs, raw), 10, 64)
+					}
+				case id == attrVersionCodeMajor || name == "versionCodeMajor":
+					major = int64(value)
+				case id == attrVersionName || name == "versionName":
+					if dataType == typeString {
+						info.VersionName = lookup(strs, raw)
+					}
+				case name == "package":
+					info.Package = lookup(strs, raw)
+				}
+			}
+			// versionCodeMajor 是 64 位版本号的高 32 位
+			info.VersionCode |= major << 32
+			if info.Package == "" {
+				return AppInfo{}, fmt.Errorf("package name not found in manifest")
+			}
+			return info, nil
+		}
+		off += size
+	}
+	return AppInfo{}, fmt.Errorf("manifest element not found")
+}
+
+// parseStringPool 解析 AXML 字符串池，支持 UTF-8 和 UTF-16 两种编码。
+// APK 来自外部，所有读取都做了边界检查：越界或长度字段不合法的字符串记为空字符串，不会 panic。
+func parseStringPool(chunk []byte) []string {
+	le := binary.LittleEndian
+	if len(chunk) < 28 {
+		return nil
+	}
+	count := int(le.Uint32(chunk[8:]))
+	utf8 := le.Uint32(chunk[16:])&(1<<8) != 0
+	start := int(le.Uint32(chunk[20:]))
+	headerSize := int(le.Uint16(chunk[2:]))
+	// 每个字符串至少占用 4 字节的偏移量，count 超过这个数量说明头部已损坏
+	if count < 0 || count > len(chunk)/4 {
+		return nil
+	}
+
+	strs := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		o := headerSize + i*4
+		if o < 0 || o+4 > len(chunk) {
+			break
+		}
+		p := start + int(le.Uint32(chunk[o:]))
+		if p < 0 || p >= len(chunk) {
+			strs = append(strs, "")
+			continue
+		}
+		if utf8 {
+			strs = append(strs, decodeString8(chunk, p))
+		} else {
+			strs = append(strs, decodeString16(chunk, p))
+		}
+	}
+	return strs
+}
+
+// decodeString8 读取 UTF-8 字符串池中位于 p 的字符串，越界时返回空字符串。
+func decodeString8(b []byte, p int) string {
+	// 先是 UTF-16 长度，再是 UTF-8 字节长度，各占 1 或 2 字节
+	_, n, ok := decodeLength8(b, p)
+	if !ok {
+		return ""
+	}
+	length, m, ok := decodeLength8(b, p+n)
+	if !ok {
+		return ""
+	}
+	p += n + m
+	if p > len(b) {
+		return ""
+	}
+	if length > len(b)-p {
+		length = len(b) - p
+	}
+	return string(b[p : p+length])
+}
+
+// decodeString16 读取 UTF-16 字符串池中位于 p 的字符串，越界时返回空字符串。
+func decodeString16(b []byte, p int) string {
+	le := binary.LittleEndian
+	// 长度占 1 或 2 个 uint16，之后是字符
+	if p+2 > len(b) {
+		return ""
+	}
+	length := int(le.Uint16(b[p:]))
+	p += 2
+	if length&0x8000 != 0 {
+		if p+2 > len(b) {
+			return ""
+		}
+		length = (length&0x7fff)<<16 | int(le.Uint16(b[p:]))
+		p += 2
+	}
+	if length > (len(b)-p)/2 {
+		length = (len(b) - p) / 2
+	}
+	u := make([]uint16, length)
+	for j := range u {
+		u[j] = le.Uint16(b[p+2*j:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// decodeLength8 读取 UTF-8 字符串池中的长度字段，返回长度、占用的字节数，以及字段是否完整。
+func decodeLength8(b []byte, p int) (int, int, bool) {
+	if p < 0 || p >= len(b) {
+		return 0, 0, false
+	}
+	if b[p]&0x80 != 0 {
+		if p+1 >= len(b) {
+			return 0, 0, false
+		}
+		return int(b[p]&0x7f)<<8 | int(b[p+1]), 2, true
+	}
+	return int(b[p]), 1, true
+}
+
+// lookup 按下标读取字符串池，越界（包括 0xFFFFFFFF 表示的空引用）时返回空字符串。
+func lookup(strs []string, i uint32) string {
+	if int64(i) >= int64(len(strs)) {
+		return ""
+	}
+	return strs[i]
+}
@@ -0,0 +1,78 @@
+package adb
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+// buildStringPool 构造只包含 strs 的 UTF-16 AXML 字符串池块。
+func buildStringPool(strs []string) []byte {
+	le := binary.LittleEndian
+	const headerSize = 28
+	var data []byte
+	offsets := make([]byte, 4*len(strs))
+	for i, s := range strs {
+		le.PutUint32(offsets[4*i:], uint32(len(data)))
+		u := utf16.Encode([]rune(s))
+		data = le.AppendUint16(data, uint16(len(u)))
+		for _, c := range u {
+			data = le.AppendUint16(data, c)
+		}
+		data = le.AppendUint16(data, 0)
+	}
+
+	chunk := make([]byte, headerSize)
+	le.PutUint16(chunk[0:], axmlStringPool)
+	le.PutUint16(chunk[2:], headerSize)
+	le.PutUint32(chunk[8:], uint32(len(strs)))
+	le.PutUint32(chunk[20:], uint32(headerSize+len(offsets)))
+	chunk = append(chunk, offsets...)
+	chunk = append(chunk, data...)
+	le.PutUint32(chunk[4:], uint32(len(chunk)))
+	return chunk
+}
+
+func TestParseStringPool(t *testing.T) {
+	want := []string{"manifest", "package", "com.example.app"}
+	got := parseStringPool(buildStringPool(want))
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("string %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseStringPoolTruncated(t *testing.T) {
+	full := buildStringPool([]string{"manifest", "versionName", "1.2.3"})
+	for n := 0; n <= len(full); n++ {
+		parseStringPool(full[:n]) // 不能 panic
+	}
+
+	// UTF-8 池：长度字段声明的长度超出块的末尾
+	utf8Pool := append([]byte(nil), full[:28]...)
+	binary.LittleEndian.PutUint32(utf8Pool[8:], 1)
+	binary.LittleEndian.PutUint32(utf8Pool[16:], 1<<8)
+	binary.LittleEndian.PutUint32(utf8Pool[20:], 32)
+	utf8Pool = append(utf8Pool, 0, 0, 0, 0, 0x80)
+	for n := 0; n <= len(utf8Pool); n++ {
+		parseStringPool(utf8Pool[:n])
+	}
+}
+
+func TestParseManifestTruncated(t *testing.T) {
+	pool := buildStringPool([]string{"manifest", "package", "com.example.app"})
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint16(data[0:], 0x0003)
+	binary.LittleEndian.PutUint16(data[2:], 8)
+	data = append(data, pool...)
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)))
+	for n := 0; n <= len(data); n++ {
+		if _, err := parseManifest(data[:n]); err == nil {
+			t.Errorf("parseManifest(%d bytes) succeeded without a manifest element", n)
+		}
+	}
+}
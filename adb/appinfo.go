@@ -0,0 +1,177 @@
+package adb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AppInfo 表示应用的基本信息。
+type AppInfo struct {
+	Package          string // 包名
+	VersionCode      int64  // 版本号（包含 versionCodeMajor 时为组合后的 64 位版本号）
+	VersionName      string // 版本名称，例如 "1.2.3"
+	MinSDK           int    // 最低支持的 API 级别（仅设备上已安装的应用提供）
+	TargetSDK        int    // 目标 API 级别（仅设备上已安装的应用提供）
+	FirstInstallTime string // 首次安装时间，格式为设备输出的 "2006-01-02 15:04:05"
+	LastUpdateTime   string // 最近更新时间
+}
+
+var (
+	appVersionCodeRe = regexp.MustCompile(`versionCode=(\d+)`)
+	appVersionNameRe = regexp.MustCompile(`versionName=(\S*)`)
+	appMinSDKRe      = regexp.MustCompile(`minSdk=(\d+)`)
+	appTargetSDKRe   = regexp.MustCompile(`targetSdk=(\d+)`)
+	appFirstInstall  = regexp.MustCompile(`firstInstallTime=(.+)`)
+	appLastUpdate    = regexp.MustCompile(`lastUpdateTime=(.+)`)
+)
+
+// AppInfo 获取设备上已安装应用的版本等信息（解析 'dumpsys package <包名>'）。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//
+// 返回值：
+//   - AppInfo: 应用信息
+//   - error: 如果应用未安装（错误包装了 ErrNotInstalled）或命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - 更新过的系统应用在 dumpsys 中有两条记录，返回第一条（当前生效的版本）
+//
+// 示例：
+//
+//	info, err := device.AppInfo("com.example.app")
+//	if errors.Is(err, adb.ErrNotInstalled) {
+//	    fmt.Println("未安装")
+//	} else if err == nil {
+//	    fmt.Printf("%s %s (%d)\n", info.Package, info.VersionName, info.VersionCode)
+//	}
+func (d *Device) AppInfo(pkg string) (AppInfo, error) {
+	pkg = d.packageOr(pkg)
+	output, err := d.Shellf("dumpsys package %s", pkg)
+	if err != nil {
+		return AppInfo{}, err
+	}
+	// 只解析 "Packages:" 段落中该应用的记录
+	i := strings.Index(output, "Package ["+pkg+"]")
+	if i < 0 {
+		return AppInfo{}, fmt.Errorf("app %s: %w", pkg, ErrNotInstalled)
+	}
+	section := output[i:]
+
+	info := AppInfo{Package: pkg}
+	if m := appVersionCodeRe.FindStringSubmatch(section); m != nil {
+		info.VersionCode, _ = strconv.ParseInt(m[1], 10, 64)
+	}
+	if m := appVersionNameRe.FindStringSubmatch(section); m != nil {
+		info.VersionName = m[1]
+	}
+	if m := appMinSDKRe.FindStringSubmatch(section); m != nil {
+		info.MinSDK, _ = strconv.Atoi(m[1])
+	}
+	if m := appTargetSDKRe.FindStringSubmatch(section); m != nil {
+		info.TargetSDK, _ = strconv.Atoi(m[1])
+	}
+	if m := appFirstInstall.FindStringSubmatch(section); m != nil {
+		info.FirstInstallTime = strings.TrimSpace(m[1])
+	}
+	if m := appLastUpdate.FindStringSubmatch(section); m != nil {
+		info.LastUpdateTime = strings.TrimSpace(m[1])
+	}
+	return info, nil
+}
+
+// VersionCheck 是本地 APK 与设备上已安装版本的比较结果。
+type VersionCheck struct {
+	Package              string // APK 的包名
+	LocalVersionCode     int64  // 本地 APK 的版本号
+	InstalledVersionCode int64  // 设备上已安装的版本号，未安装时为 0
+	NeedsInstall         bool   // 未安装或版本号不同时为 true
+}
+
+// CheckVersion 比较本地 APK 与设备上已安装应用的版本号。
+//
+// 参数：
+//   - apkPath: 本地 APK 路径
+//
+// 返回值：
+//   - VersionCheck: 比较结果
+//   - error: 如果读取 APK 或查询设备失败，返回 error 对象（应用未安装不算错误）
+//
+// 示例：
+//
+//	check, err := device.CheckVersion("app-release.apk")
+//	if err == nil {
+//	    fmt.Printf("本地 %d，设备 %d\n", check.LocalVersionCode, check.InstalledVersionCode)
+//	}
+func (d *Device) CheckVersion(apkPath string) (VersionCheck, error) {
+	local, err := ApkInfo(apkPath)
+	if err != nil {
+		return VersionCheck{}, err
+	}
+	check := VersionCheck{Package: local.Package, LocalVersionCode: local.VersionCode, NeedsInstall: true}
+
+	installed, err := d.AppInfo(local.Package)
+	if err != nil {
+		if errors.Is(err, ErrNotInstalled) {
+			return check, nil
+		}
+		return VersionCheck{}, err
+	}
+	check.InstalledVersionCode = installed.VersionCode
+	check.NeedsInstall = installed.VersionCode != local.VersionCode
+	return check, nil
+}
+
+// NeedsInstall 判断本地 APK 是否需要安装：设备上未安装该应用或版本号不同时返回 true。
+//
+// 参数：
+//   - apkPath: 本地 APK 路径
+//
+// 返回值：
+//   - bool: 是否需要安装
+//   - error: 如果读取 APK 或查询设备失败，返回 error 对象
+//
+// 示例：
+//
+//	if need, _ := device.NeedsInstall("app-release.apk"); need {
+//	    device.InstallAPK("app-release.apk", adb.InstallReplace())
+//	}
+func (d *Device) NeedsInstall(apkPath string) (bool, error) {
+	check, err := d.CheckVersion(apkPath)
+	return check.NeedsInstall, err
+}
+
+// InstallIfChanged 仅在设备上未安装该应用或版本号不同时安装 APK，
+// 避免 CI 每次运行都花几分钟重新安装同一个版本。
+//
+// 参数：
+//   - apkPath: 本地 APK 路径
+//   - opts: 安装参数，与 InstallAPK 相同
+//
+// 返回值：
+//   - VersionCheck: 安装前的版本比较结果，NeedsInstall 为 false 表示跳过了安装
+//   - error: 如果读取版本或安装失败，返回 error 对象
+//
+// 注意事项：
+//   - 只比较 versionCode，同一版本号的不同构建会被认为相同
+//   - 本地版本低于设备版本时，需要传入 InstallDowngrade() 才能安装成功
+//
+// 示例：
+//
+//	check, err := device.InstallIfChanged("app-release.apk", adb.InstallReplace())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !check.NeedsInstall {
+//	    fmt.Println("版本相同，跳过安装:", check.LocalVersionCode)
+//	}
+func (d *Device) InstallIfChanged(apkPath string, opts ...InstallOption) (VersionCheck, error) {
+	check, err := d.CheckVersion(apkPath)
+	if err != nil || !check.NeedsInstall {
+		return check, err
+	}
+	return check, d.InstallAPK(apkPath, opts...)
+}
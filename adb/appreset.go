@@ -0,0 +1,91 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ClearAppData 清除应用的所有数据（'pm clear'），相当于在设置中点击 "清除存储空间"。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//
+// 返回值：
+//   - error: 如果应用未安装（错误包装了 ErrNotInstalled）或清除失败，返回 error 对象
+//
+// 注意事项：
+//   - 会同时结束应用进程，并撤销所有运行时权限
+//
+// 示例：
+//
+//	device.ClearAppData("com.example.app")
+func (d *Device) ClearAppData(pkg string) error {
+	pkg = d.packageOr(pkg)
+	output, err := d.Shellf("pm clear %s", pkg)
+	text := output + errString(err)
+	if strings.Contains(text, "Unknown package") || strings.Contains(text, "not found") {
+		return fmt.Errorf("clear %s: %w", pkg, ErrNotInstalled)
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(output, "Success") {
+		return fmt.Errorf("clear %s failed: %s", pkg, output)
+	}
+	return nil
+}
+
+// ResetOptions 是 ResetApp 的可选步骤，零值只强制停止应用。
+type ResetOptions struct {
+	ClearData   bool     // 清除应用数据
+	Permissions []string // 重新授予的运行时权限（清除数据会撤销所有权限）
+	Launch      bool     // 最后启动应用并等待其进入前台
+}
+
+// ResetApp 将应用恢复到干净的初始状态，是测试用例准备阶段的标准操作。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//   - opts: 要执行的可选步骤
+//
+// 返回值：
+//   - error: 任意一步失败时立即返回，错误信息中包含失败的步骤，例如 "reset com.example.app: grant android.permission.CAMERA: ..."
+//
+// 执行顺序：
+//  1. ForceStopApp 强制停止
+//  2. ClearData 为 true 时 ClearAppData
+//  3. 依次 GrantPermission 授予 Permissions 中的权限
+//  4. Launch 为 true 时调用 Launch 启动应用
+//
+// 示例：
+//
+//	err := device.ResetApp("com.example.app", adb.ResetOptions{
+//	    ClearData:   true,
+//	    Permissions: []string{"android.permission.CAMERA", "android.permission.POST_NOTIFICATIONS"},
+//	    Launch:      true,
+//	})
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+func (d *Device) ResetApp(pkg string, opts ResetOptions) error {
+	pkg = d.packageOr(pkg)
+	if err := d.ForceStopApp(pkg); err != nil {
+		return fmt.Errorf("reset %s: force-stop: %w", pkg, err)
+	}
+	if opts.ClearData {
+		if err := d.ClearAppData(pkg); err != nil {
+			return fmt.Errorf("reset %s: clear data: %w", pkg, err)
+		}
+	}
+	for _, perm := range opts.Permissions {
+		if err := d.GrantPermission(pkg, perm); err != nil {
+			return fmt.Errorf("reset %s: grant %s: %w", pkg, perm, err)
+		}
+	}
+	if opts.Launch {
+		if err := d.Launch(pkg); err != nil {
+			return fmt.Errorf("reset %s: launch: %w", pkg, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package adb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// artifactTimeFormat 是 CollectArtifacts 文件名前缀的时间格式，精确到毫秒。
+const artifactTimeFormat = "20060102-150405.000"
+
+// CollectArtifacts 把排查测试失败所需的现场信息一次性保存到本地目录，适合在测试失败时的 defer 中调用。
+//
+// 参数：
+//   - dir: 本地目录，不存在时自动创建
+//
+// 返回值：
+//   - error: 某一项收集失败时继续收集其他项，最后通过 errors.Join 返回所有失败；
+//     全部成功时返回 nil。即使返回 error，已经写入的文件仍然有效
+//
+// 保存的文件（<时间> 为调用时刻，精确到毫秒，格式 20060102-150405.000）：
+//   - <时间>-screenshot.png: 当前屏幕截图
+//   - <时间>-ui.xml: 当前 UI dump，可用 uixml.LoadFile 或 RunSelector 离线分析
+//   - <时间>-logcat.txt: 'logcat -d' 读取的全部缓冲区日志
+//   - <时间>-activity.txt: 'dumpsys activity top' 的输出（前台 Activity 及其 View 层级）
+//   - <时间>-device.txt: 设备信息，包括 'dumpsys battery' 和全部系统属性
+//
+// 注意事项：
+//   - 文件名带有毫秒精度的时间戳，同一目录中多次收集（包括同一秒内的多次失败）不会互相覆盖
+//   - 完整的收集通常需要数秒，设备离线时每一项都会失败，返回的错误中包含所有失败项
+//
+// 示例：
+//
+//	func TestLogin(t *testing.T) {
+//	    defer func() {
+//	        if t.Failed() {
+//	            if err := device.CollectArtifacts("artifacts/" + t.Name()); err != nil {
+//	                t.Log("部分现场信息收集失败:", err)
+//	            }
+//	        }
+//	    }()
+//	    // ...
+//	}
+func (d *Device) CollectArtifacts(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	prefix := filepath.Join(dir, time.Now().Format(artifactTimeFormat)+"-")
+
+	var errs []error
+	collect := func(name string, fn func(path string) error) {
+		if err := fn(prefix + name); err != nil {
+			errs = append(errs, fmt.Errorf("collect %s: %w", name, err))
+		}
+	}
+
+	collect("screenshot.png", func(path string) error {
+		data, err := d.Screenshot()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0644)
+	})
+	collect("ui.xml", d.SaveDump)
+	collect("logcat.txt", func(path string) error {
+		return d.LogcatSave(path, LogcatOptions{Buffer: "all"})
+	})
+	collect("activity.txt", func(path string) error {
+		output, err := d.ShellRaw("dumpsys activity top")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(output), 0644)
+	})
+	collect("device.txt", d.saveDeviceInfo)
+
+	return errors.Join(errs...)
+}
+
+// saveDeviceInfo 把电量信息和全部系统属性写入本地文件。
+// 两部分分别读取，其中一部分失败时仍写入另一部分。
+func (d *Device) saveDeviceInfo(path string) error {
+	var b strings.Builder
+	var errs []error
+
+	b.WriteString("# dumpsys battery\n")
+	if battery, err := d.Shell("dumpsys battery"); err != nil {
+		errs = append(errs, err)
+	} else {
+		b.WriteString(battery + "\n")
+	}
+
+	b.WriteString("\n# getprop\n")
+	if props, err := d.GetAllProps(); err != nil {
+		errs = append(errs, err)
+	} else {
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "[%s]: [%s]\n", k, props[k])
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
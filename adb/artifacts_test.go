@@ -0,0 +1,27 @@
+package adb
+
+import (
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestCollectArtifactsMillisecondPrefix(t *testing.T) {
+	d, _ := newFakeDevice(func(command string) (string, error) { return hierarchy(), nil })
+	dir := t.TempDir()
+	d.CollectArtifacts(dir) // 伪造的输出不是合法的截图等，这里只检查文件名
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("no artifacts written")
+	}
+	nameRe := regexp.MustCompile(`^\d{8}-\d{6}\.\d{3}-[a-z]+\.(png|xml|txt)$`)
+	for _, e := range entries {
+		if !nameRe.MatchString(e.Name()) {
+			t.Errorf("artifact name %q does not have a millisecond prefix", e.Name())
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// defaultAutocompletePopups 是 DismissAutocomplete 默认识别的联想 / 自动填充下拉框。
+var defaultAutocompletePopups = []FindNodeFunc{
+	// 系统自动填充（Android 8.0+）的候选列表
+	func(n, pn uixml.Node) bool {
+		return n.ResourceID == "android:id/autofill_dataset_picker" || n.ResourceID == "android:id/autofill_dataset_list"
+	},
+	// AutoCompleteTextView / Spinner 弹出的下拉列表
+	func(n, pn uixml.Node) bool {
+		return strings.Contains(n.Class, "DropDownListView") || strings.HasSuffix(n.ResourceID, ":id/select_dialog_listview")
+	},
+}
+
+// autocompleteSettleDelay 是按返回键后等待下拉框消失的时间。
+const autocompleteSettleDelay = 300 * time.Millisecond
+
+// DismissAutocomplete 关闭输入后弹出的联想 / 自动填充下拉框，并确认输入框中的文字没有变化。
+// 登录表单中输入用户名后弹出的账号联想列表经常挡住密码框，导致下一步点击落在联想项上。
+//
+// 参数：
+//   - popups: 识别下拉框的查找函数，任意一个匹配即认为下拉框正在显示；
+//     不传时使用默认规则（系统自动填充列表、DropDownListView 下拉列表）
+//
+// 返回值：
+//   - error: 如果 dump 失败、按返回键后下拉框仍在，或输入框的文字被改变，返回 error 对象；
+//     没有下拉框时直接返回 nil
+//
+// 工作原理：
+//  1. dump 屏幕，记录当前获得焦点的输入框及其文字
+//  2. 没有匹配的下拉框时返回；否则按一次返回键
+//  3. 重新 dump，确认下拉框已消失、输入框的文字没有变化
+//
+// 注意事项：
+//   - 部分输入法在键盘显示时会先用返回键收起键盘，此时下拉框可能仍在，会返回错误，可再调用一次
+//   - 默认规则无法覆盖应用自绘的联想列表，需要通过 popups 传入对应的查找函数
+//
+// 示例：
+//
+//	device.Input("alice")
+//	if err := device.DismissAutocomplete(); err != nil {
+//	    log.Println(err)
+//	}
+//	device.ClickNodeBy(passwordField)
+//
+//	// 应用自定义的联想列表
+//	err := device.DismissAutocomplete(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/suggestions"
+//	})
+func (d *Device) DismissAutocomplete(popups ...FindNodeFunc) error {
+	if len(popups) == 0 {
+		popups = defaultAutocompletePopups
+	}
+	popupShown := func(xml *uixml.Xml) bool {
+		for _, fn := range popups {
+			if _, err := xml.Find(fn); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+	focused := func(n, pn uixml.Node) bool { return n.IsFocused() && strings.Contains(n.Class, "EditText") }
+
+	xml, err := d.XML()
+	if err != nil {
+		return err
+	}
+	if !popupShown(xml) {
+		return nil
+	}
+	field, fieldErr := xml.Find(focused)
+
+	if err := d.PressBack(); err != nil {
+		return err
+	}
+	time.Sleep(autocompleteSettleDelay)
+
+	if xml, err = d.XML(); err != nil {
+		return err
+	}
+	if popupShown(xml) {
+		return fmt.Errorf("autocomplete popup still shown after back")
+	}
+	if fieldErr == nil {
+		after, err := xml.Find(func(n, pn uixml.Node) bool {
+			return n.ResourceID == field.ResourceID && n.Bounds == field.Bounds
+		})
+		if err == nil && after.Text != field.Text {
+			return fmt.Errorf("field text changed from %q to %q while dismissing autocomplete", field.Text, after.Text)
+		}
+	}
+	return nil
+}
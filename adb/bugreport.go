@@ -0,0 +1,179 @@
+package adb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// bugreportProgressRe 匹配 adb bugreport 输出的进度，例如 "[ 42%] generating bugreport-xxx.zip"
+	bugreportProgressRe = regexp.MustCompile(`\[\s*(\d+)%\]`)
+	// bugreportPulledRe 匹配拉取完成后的输出，例如 "/data/.../bugreport-xxx.zip: 1 file pulled, ..."
+	bugreportPulledRe = regexp.MustCompile(`(\S+\.zip): 1 file pulled`)
+)
+
+// Bugreport 生成设备的 bugreport 并保存到本地，等同于 BugreportContext(context.Background(), localZipPath, nil)。
+//
+// 参数：
+//   - localZipPath: 本地保存路径，可以是文件路径或已存在的目录
+//
+// 返回值：
+//   - string: 最终保存的文件路径
+//   - int64: 文件大小（字节）
+//   - error: 如果生成或拉取失败，返回 error 对象
+//
+// 示例：
+//
+//	path, size, err := device.Bugreport("artifacts/")
+//	if err == nil {
+//	    fmt.Printf("bugreport 已保存到 %s（%d 字节）\n", path, size)
+//	}
+func (d *Device) Bugreport(localZipPath string) (string, int64, error) {
+	return d.BugreportContext(context.Background(), localZipPath, nil)
+}
+
+// BugreportContext 生成设备的 bugreport 并保存到本地，支持取消和进度回调。
+// bugreport 通常需要几分钟，建议配合带超时的 context 使用。
+//
+// 参数：
+//   - ctx: 用于取消的 context，取消时会结束 adb 进程
+//   - localZipPath: 本地保存路径，可以是文件路径或已存在的目录
+//   - progress: 可选的进度回调，参数为 0-100 的百分比，可以为 nil
+//
+// 返回值：
+//   - string: 最终保存的文件路径
+//   - int64: 文件大小（字节）
+//   - error: 如果生成、拉取失败或被取消，返回 error 对象
+//
+// 兼容性：
+//   - Android 7.0+（API 24+）：生成 zip 格式，文件路径不以 .zip 结尾时 adb 会自动补上；
+//     传入目录时使用设备生成的文件名
+//   - 更早的版本只支持纯文本格式，保存为 .txt 文件（.zip 后缀会被替换），不提供进度
+//
+// 示例：
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+//	defer cancel()
+//	path, size, err := device.BugreportContext(ctx, "crash.zip", func(percent int) {
+//	    fmt.Printf("\r生成中 %d%%", percent)
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("\n已保存 %s（%d MB）\n", path, size>>20)
+func (d *Device) BugreportContext(ctx context.Context, localZipPath string, progress func(int)) (string, int64, error) {
+	sdk, err := d.SDKLevel()
+	if err != nil {
+		return "", 0, err
+	}
+
+	var path string
+	if sdk >= 24 {
+		path, err = d.bugreportZip(ctx, localZipPath, progress)
+	} else {
+		path, err = d.bugreportText(ctx, localZipPath)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+// bugreportZip 通过 'adb bugreport <path>' 生成 zip 格式的 bugreport，返回本地文件路径。
+func (d *Device) bugreportZip(ctx context.Context, localPath string, progress func(int)) (string, error) {
+	cmd := d.commandContext(ctx, "bugreport", localPath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	// 进度行以 '\r' 结尾覆盖显示，需要同时按 '\r' 和 '\n' 分割
+	var remote string
+	scanner := bufio.NewScanner(stdout)
+	scanner.Split(scanLinesCR)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := bugreportProgressRe.FindStringSubmatch(line); m != nil && progress != nil {
+			percent, _ := strconv.Atoi(m[1])
+			progress(percent)
+		}
+		if m := bugreportPulledRe.FindStringSubmatch(line); m != nil {
+			remote = m[1]
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("adb bugreport failed: %w, output: %s", err, stderr.String())
+	}
+
+	// 目标是目录时，文件名使用设备端生成的名称
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		if remote == "" {
+			return "", fmt.Errorf("bugreport file name not found in adb output")
+		}
+		return filepath.Join(localPath, filepath.Base(remote)), nil
+	}
+	if !strings.HasSuffix(localPath, ".zip") {
+		localPath += ".zip"
+	}
+	return localPath, nil
+}
+
+// bugreportText 通过 'adb shell bugreport' 生成纯文本格式的 bugreport（Android 7.0 以前），返回本地文件路径。
+func (d *Device) bugreportText(ctx context.Context, localPath string) (string, error) {
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		localPath = filepath.Join(localPath, "bugreport.txt")
+	} else {
+		localPath = strings.TrimSuffix(localPath, ".zip") + ".txt"
+	}
+
+	f, err := os.Create(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var stderr bytes.Buffer
+	cmd := d.commandContext(ctx, "shell", "bugreport")
+	cmd.Stdout = f
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(localPath)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("adb bugreport failed: %w, output: %s", err, stderr.String())
+	}
+	return localPath, f.Close()
+}
+
+// scanLinesCR 是 bufio.SplitFunc，按 '\r' 或 '\n' 分割行。
+func scanLinesCR(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,72 @@
+package adb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// undoStep 是一次设备状态修改对应的恢复操作。
+type undoStep struct {
+	name string // 修改的内容，用于错误信息
+	fn   func() error
+}
+
+// pushUndo 记录一次修改的恢复操作，由会改变设备全局状态的方法在修改成功后调用。
+func (d *Device) pushUndo(name string, fn func() error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.undo = append(d.undo, undoStep{name: name, fn: fn})
+}
+
+// Cleanup 恢复本次运行中通过该 Device 修改过的设备状态，避免测试永久改变共享设备的设置。
+//
+// 返回值：
+//   - error: 所有恢复失败的步骤合并后的错误；即使某一步失败，其余步骤也会继续执行
+//
+// 工作原理：
+//   - 以下方法修改成功时会记录修改前的值：DisableAnimations、SetDisplayDensity、SetFontScale、
+//     SetIME、SetProp、SetLocale、SetAppLocale、FreezeRotation
+//   - Cleanup 按与修改相反的顺序逐一恢复，然后清空记录，重复调用是安全的
+//
+// 注意事项：
+//   - 只能恢复通过同一个 Device 实例做的修改；WithSerial 创建的新实例不继承记录
+//   - 直接通过 Shell 执行的修改不会被记录
+//   - 恢复系统语言（SetLocale）会再次软重启设备
+//
+// 示例：
+//
+//	device := adb.NewDevice("emulator-5554")
+//	defer device.Cleanup()
+//
+//	device.DisableAnimations()
+//	device.SetDisplayDensity(480)
+//	device.SetProp("debug.layout", "true")
+//	// ... 测试结束后依次恢复 debug.layout、屏幕密度和动画设置
+func (d *Device) Cleanup() error {
+	d.mu.Lock()
+	steps := d.undo
+	d.undo = nil
+	d.mu.Unlock()
+
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err := steps[i].fn(); err != nil {
+			errs = append(errs, fmt.Errorf("restore %s: %w", steps[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ResetAll 是 Cleanup 的别名。
+func (d *Device) ResetAll() error {
+	return d.Cleanup()
+}
+
+// restoreSetting 把设置项恢复为 prev，prev 为空表示原来没有该设置项，直接删除。
+func (d *Device) restoreSetting(namespace, key, prev string) error {
+	if prev == "" {
+		_, err := d.Shellf("settings delete %s %s", namespace, key)
+		return err
+	}
+	return d.PutSetting(namespace, key, prev)
+}
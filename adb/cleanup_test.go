@@ -0,0 +1,159 @@
+package adb
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// deviceState 模拟 Cleanup 相关的设备状态：系统设置、屏幕密度、输入法和（root 下的）系统属性。
+type deviceState struct {
+	propStore
+	settings map[string]string // "命名空间/键" 到值
+	density  int               // 覆盖密度，0 表示未覆盖
+}
+
+func newDeviceState() *deviceState {
+	return &deviceState{
+		propStore: propStore{root: true, props: map[string]string{
+			"ro.build.version.sdk": "34",
+			"persist.sys.locale":   "en-US",
+			"debug.layout":         "false",
+		}},
+		settings: map[string]string{
+			"global/window_animation_scale":     "1.0",
+			"global/transition_animation_scale": "1.0",
+			// animator_duration_scale 从未设置过
+			"system/accelerometer_rotation": "1",
+			"system/user_rotation":          "0",
+			"secure/default_input_method":   "com.google.android.inputmethod.latin/.LatinIME",
+		},
+	}
+}
+
+// respond 依次执行用 " && " 连接的每一条命令。
+func (s *deviceState) respond(command string) (string, error) {
+	var output []string
+	for _, part := range strings.Split(command, " && ") {
+		out, err := s.run(part)
+		if err != nil {
+			return "", err
+		}
+		if out != "" {
+			output = append(output, out)
+		}
+	}
+	return strings.Join(output, "\n"), nil
+}
+
+func (s *deviceState) run(command string) (string, error) {
+	f := strings.Fields(command)
+	for i := range f {
+		f[i] = strings.Trim(f[i], "'")
+	}
+	switch {
+	case len(f) == 4 && f[0] == "settings" && f[1] == "get":
+		if v, ok := s.settings[f[2]+"/"+f[3]]; ok {
+			return v, nil
+		}
+		return "null", nil
+	case len(f) == 5 && f[0] == "settings" && f[1] == "put":
+		s.settings[f[2]+"/"+f[3]] = f[4]
+	case len(f) == 4 && f[0] == "settings" && f[1] == "delete":
+		delete(s.settings, f[2]+"/"+f[3])
+	case command == "wm density":
+		out := "Physical density: 440"
+		if s.density != 0 {
+			out += fmt.Sprintf("\nOverride density: %d", s.density)
+		}
+		return out, nil
+	case command == "wm density reset":
+		s.density = 0
+	case len(f) == 3 && f[0] == "wm" && f[1] == "density":
+		s.density, _ = strconv.Atoi(f[2])
+	case len(f) == 3 && f[0] == "ime" && f[1] == "enable":
+	case len(f) == 3 && f[0] == "ime" && f[1] == "set":
+		s.settings["secure/default_input_method"] = f[2]
+	case len(f) == 3 && f[0] == "setprop" && strings.HasPrefix(f[1], "ctl."):
+		// ctl.restart 等控制属性不保存
+	default:
+		return s.propStore.respond(command)
+	}
+	return "", nil
+}
+
+// snapshot 复制当前状态，用于比较 Cleanup 前后是否一致。
+func (s *deviceState) snapshot() (settings, props map[string]string, density int) {
+	return maps.Clone(s.settings), maps.Clone(s.props), s.density
+}
+
+func TestSettersRegisterUndo(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(d *Device) error
+	}{
+		{"DisableAnimations", (*Device).DisableAnimations},
+		{"SetDisplayDensity", func(d *Device) error { _, err := d.SetDisplayDensity(560); return err }},
+		{"SetFontScale", func(d *Device) error { _, err := d.SetFontScale(1.3); return err }},
+		{"SetIME", func(d *Device) error { return d.SetIME(adbKeyboardIME) }},
+		{"SetProp", func(d *Device) error { return d.SetProp("debug.layout", "true") }},
+		{"SetLocale", func(d *Device) error { return d.SetLocale("zh-CN") }},
+		{"FreezeRotation", func(d *Device) error { return d.FreezeRotation(90) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newDeviceState()
+			d, _ := newFakeDevice(s.respond)
+			settings, props, density := s.snapshot()
+
+			if err := tt.set(d); err != nil {
+				t.Fatal(err)
+			}
+			if n := len(d.undo); n != 1 {
+				t.Fatalf("registered %d undo steps, want 1", n)
+			}
+			if s2, p2, d2 := s.snapshot(); maps.Equal(s2, settings) && maps.Equal(p2, props) && d2 == density {
+				t.Fatal("setter did not change the device state")
+			}
+
+			if err := d.Cleanup(); err != nil {
+				t.Fatal(err)
+			}
+			if len(d.undo) != 0 {
+				t.Errorf("%d undo steps left after Cleanup", len(d.undo))
+			}
+			s2, p2, d2 := s.snapshot()
+			if !maps.Equal(s2, settings) {
+				t.Errorf("settings after Cleanup = %v, want %v", s2, settings)
+			}
+			if !maps.Equal(p2, props) {
+				t.Errorf("props after Cleanup = %v, want %v", p2, props)
+			}
+			if d2 != density {
+				t.Errorf("density after Cleanup = %d, want %d", d2, density)
+			}
+		})
+	}
+}
+
+func TestCleanupRestoresInReverseOrder(t *testing.T) {
+	s := newDeviceState()
+	d, _ := newFakeDevice(s.respond)
+	settings, _, _ := s.snapshot()
+
+	// 两次修改同一设置项，按相反顺序恢复后回到最初的值
+	if err := d.FreezeRotation(90); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.FreezeRotation(180); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ResetAll(); err != nil {
+		t.Fatal(err)
+	}
+	if s2, _, _ := s.snapshot(); !maps.Equal(s2, settings) {
+		t.Errorf("settings after ResetAll = %v, want %v", s2, settings)
+	}
+}
@@ -0,0 +1,240 @@
+package adb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetDeviceTime 获取设备当前的系统时间。
+//
+// 返回值：
+//   - time.Time: 设备时间（本地时区，精度为秒）
+//   - error: 如果命令执行失败或输出无法解析，返回 error 对象
+//
+// 示例：
+//
+//	t, err := device.GetDeviceTime()
+//	if err == nil {
+//	    fmt.Println("设备时间与本机相差:", time.Since(t))
+//	}
+func (d *Device) GetDeviceTime() (time.Time, error) {
+	output, err := d.Shell("date +%s")
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, err := strconv.ParseInt(output, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected date output %q: %w", output, err)
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// SetDeviceTime 修改设备的系统时间，用于测试订阅到期、提醒、token 刷新等与时间相关的场景。
+//
+// 参数：
+//   - t: 要设置的时间
+//
+// 返回值：
+//   - error: 如果设置失败，返回 error 对象；
+//     既没有 root 权限、系统也不允许 shell 修改时间时，返回的错误包装了 ErrRootRequired
+//
+// 设置方式（按优先级）：
+//  1. 'cmd alarm set-time <毫秒>'（较新的 Android，需要 shell 拥有 SET_TIME 权限）
+//  2. root：toybox 'date -u MMDDhhmmCCYY.ss'
+//  3. root：旧版 toolbox 'date -s YYYYMMDD.hhmmss'
+//
+// 注意事项：
+//   - 开启了自动时间时系统会很快把时间同步回来，设置前应先调用 SetAutoTime(false)
+//
+// 示例：
+//
+//	device.SetAutoTime(false)
+//	defer device.SetAutoTime(true)
+//
+//	// 把时间调到 31 天后，验证订阅过期提示
+//	err := device.SetDeviceTime(time.Now().AddDate(0, 0, 31))
+//	if errors.Is(err, adb.ErrRootRequired) {
+//	    log.Fatal("设备不允许修改时间")
+//	}
+func (d *Device) SetDeviceTime(t time.Time) error {
+	output, err := d.Shell(fmt.Sprintf("cmd alarm set-time %d", t.UnixMilli()))
+	if err == nil && (output == "" || strings.Contains(output, "true")) {
+		return nil
+	}
+
+	if root, _ := d.IsRoot(); !root {
+		return fmt.Errorf("set device time: %s: %w", output, ErrRootRequired)
+	}
+
+	utc := t.UTC()
+	// toybox date 的设置格式为 MMDDhhmm[[CC]YY][.ss]
+	output, err = d.Shell("date -u " + utc.Format("010215042006.05"))
+	if err != nil || strings.Contains(output, "date:") {
+		// 旧版 toolbox date 只支持 -s，按设备本地时区解释，TZ=UTC 确保使用 UTC
+		output, err = d.Shell("TZ=UTC date -s " + utc.Format("20060102.150405"))
+		if err != nil {
+			return err
+		}
+		if strings.Contains(output, "date:") {
+			return fmt.Errorf("set device time failed: %s", output)
+		}
+	}
+	return nil
+}
+
+// SetAutoTime 开启或关闭 "自动确定日期和时间"（settings global auto_time）。
+//
+// 参数：
+//   - on: true 为开启自动同步网络时间，false 为关闭
+//
+// 返回值：
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 示例：
+//
+//	device.SetAutoTime(false)
+func (d *Device) SetAutoTime(on bool) error {
+	value := "0"
+	if on {
+		value = "1"
+	}
+	return d.PutSetting("global", "auto_time", value)
+}
+
+// Uptime 返回设备自启动以来经过的时间（读取 /proc/uptime，包含深度睡眠的时间）。
+//
+// 返回值：
+//   - time.Duration: 开机时长，精度为 /proc/uptime 提供的 10 毫秒
+//   - error: 如果命令执行失败或输出无法解析，返回 error 对象
+//
+// 示例：
+//
+//	before, _ := device.Uptime()
+//	runLongTest()
+//	after, _ := device.Uptime()
+//	if after < before {
+//	    log.Println("测试期间设备重启过")
+//	}
+func (d *Device) Uptime() (time.Duration, error) {
+	output, err := d.Shell("cat /proc/uptime")
+	if err != nil {
+		return 0, err
+	}
+	return parseUptime(output)
+}
+
+// parseUptime 解析 /proc/uptime 的第一个字段，例如 "350735.47 234388.90"。
+// 整数部分和小数部分分别解析，避免浮点数换算带来的误差。
+func parseUptime(output string) (time.Duration, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected /proc/uptime output %q", output)
+	}
+	secStr, fracStr, _ := strings.Cut(fields[0], ".")
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected /proc/uptime output %q: %w", output, err)
+	}
+	frac, err := parseFraction(fracStr)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected /proc/uptime output %q: %w", output, err)
+	}
+	return time.Duration(sec)*time.Second + frac, nil
+}
+
+// parseFraction 将秒数的小数部分（例如 "47"、"123456789"）转换为时长，超过纳秒的位数被截断。
+func parseFraction(frac string) (time.Duration, error) {
+	if frac == "" {
+		return 0, nil
+	}
+	if strings.Trim(frac, "0123456789") != "" {
+		return 0, fmt.Errorf("bad fraction %q", frac)
+	}
+	if len(frac) > 9 {
+		frac = frac[:9]
+	}
+	nsec, err := strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+	return time.Duration(nsec), err
+}
+
+// BootTime 返回设备的开机时间（设备当前时间减去 Uptime）。
+// 开机时间在两次调用之间发生变化即说明设备重启过。
+//
+// 返回值：
+//   - time.Time: 开机时间，基于设备时钟而不是本机时钟，四舍五入到整秒
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 兼容性：
+//   - Android 6.0+ 的 toybox date 支持 %N，当前时间精确到纳秒，多次调用的结果一致
+//   - 更早的 toolbox date 不支持 %N，只能得到整秒，此时按该秒的中点计算，结果可能相差 1 秒
+//
+// 示例：
+//
+//	boot, err := device.BootTime()
+//	if err == nil {
+//	    fmt.Println("开机于:", boot.Format(time.DateTime))
+//	}
+func (d *Device) BootTime() (time.Time, error) {
+	// 在同一条命令中读取，保证两个值对应同一时刻
+	output, err := d.Shell("cat /proc/uptime; date +%s.%N")
+	if err != nil {
+		return time.Time{}, err
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) < 2 {
+		return time.Time{}, fmt.Errorf("unexpected output %q", output)
+	}
+	uptime, err := parseUptime(lines[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return bootTime(strings.TrimSpace(lines[1]), uptime)
+}
+
+// bootTime 根据 'date +%s.%N' 的输出和开机时长计算开机时间。
+func bootTime(date string, uptime time.Duration) (time.Time, error) {
+	secStr, fracStr, _ := strings.Cut(date, ".")
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected date output %q: %w", date, err)
+	}
+	now := time.Duration(sec) * time.Second
+	if frac, err := parseFraction(fracStr); err == nil && fracStr != "" {
+		now += frac
+	} else {
+		// 不支持 %N 时原样输出 "%N" 或 "N"，真实时间在这一秒内的某个时刻，取中点
+		now += 500 * time.Millisecond
+	}
+	return time.Unix(0, int64(now-uptime)).Round(time.Second), nil
+}
+
+// LastBootReason 返回上一次启动的原因，用于区分正常重启与崩溃、看门狗、掉电等异常重启。
+//
+// 返回值：
+//   - string: 启动原因，例如 "reboot"、"shutdown,userrequested"、"kernel_panic"、"watchdog"
+//   - error: 如果设备没有记录启动原因（错误包装了 ErrUnsupported）或命令执行失败，返回 error 对象
+//
+// 兼容性：
+//   - Android 9+ 读取规范化的 sys.boot.reason
+//   - 更早的版本读取引导程序提供的 ro.boot.bootreason，格式由厂商决定
+//
+// 示例：
+//
+//	reason, err := device.LastBootReason()
+//	if err == nil && strings.Contains(reason, "panic") {
+//	    log.Println("设备发生过内核崩溃:", reason)
+//	}
+func (d *Device) LastBootReason() (string, error) {
+	for _, key := range []string{"sys.boot.reason", "ro.boot.bootreason"} {
+		reason, err := d.GetProp(key)
+		if err != nil {
+			return "", err
+		}
+		if reason != "" {
+			return reason, nil
+		}
+	}
+	return "", fmt.Errorf("boot reason: %w", ErrUnsupported)
+}
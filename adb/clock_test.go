@@ -0,0 +1,66 @@
+package adb
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseUptime(t *testing.T) {
+	tests := []struct {
+		output string
+		want   time.Duration
+		ok     bool
+	}{
+		{"350735.47 234388.90", 350735*time.Second + 470*time.Millisecond, true},
+		{"12.05 3.00\n", 12*time.Second + 50*time.Millisecond, true},
+		{"42", 42 * time.Second, true},
+		{"", 0, false},
+		{"cat: /proc/uptime: Permission denied", 0, false},
+		{"1.-5 0.00", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseUptime(tt.output)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseUptime(%q) = %v, %v; want %v, ok %v", tt.output, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBootTime(t *testing.T) {
+	boot := time.Unix(1700000000, 0)
+	tests := []struct {
+		name   string
+		date   string
+		uptime time.Duration
+		want   time.Time
+	}{
+		// 开机 100.99 秒时读取：整秒的 date 与小数的 uptime 相减后截断会早 1 秒
+		{"nanoseconds", "1700000100.990000000", 100*time.Second + 990*time.Millisecond, boot},
+		{"next second", "1700000101.010000000", 101*time.Second + 10*time.Millisecond, boot},
+		{"rounds up", "1700000100.400000000", 100*time.Second + 900*time.Millisecond, boot},
+		// toolbox date 不支持 %N，按这一秒的中点计算
+		{"toolbox %N", "1700000100.%N", 100*time.Second + 600*time.Millisecond, boot},
+		{"toolbox N", "1700000100.N", 100*time.Second + 400*time.Millisecond, boot},
+	}
+	for _, tt := range tests {
+		got, err := bootTime(tt.date, tt.uptime)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("%s: bootTime(%q, %v) = %v, %v; want %v", tt.name, tt.date, tt.uptime, got.Unix(), err, tt.want.Unix())
+		}
+	}
+	if _, err := bootTime("date: bad format", time.Second); err == nil {
+		t.Error("bootTime accepted a non-numeric date")
+	}
+
+	d, r := newFakeDevice(func(command string) (string, error) {
+		return "100.99 50.00\n1700000100.990000000", nil
+	})
+	got, err := d.BootTime()
+	if err != nil || !got.Equal(boot) {
+		t.Errorf("BootTime = %v, %v; want %v", got.Unix(), err, boot.Unix())
+	}
+	if want := []string{"cat /proc/uptime; date +%s.%N"}; !slices.Equal(r.shellCommands(), want) {
+		t.Errorf("commands = %q, want %q", r.shellCommands(), want)
+	}
+}
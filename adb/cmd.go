@@ -0,0 +1,106 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cmdMinSDK 是 cmd 命令可用于 activity / package 服务的最低 API 级别（Android 7.0）。
+const cmdMinSDK = 24
+
+// Cmd 通过 'cmd <service> <args...>' 直接调用系统服务的 shell 命令。
+// 与 am / pm 等脚本相比，cmd 不需要启动新的 Java 进程（app_process），执行更快。
+//
+// 参数：
+//   - service: 系统服务名，例如 "activity"、"package"、"statusbar"
+//   - args: 命令参数，每个参数都会被单引号转义，可以安全包含空格和特殊字符
+//
+// 返回值：
+//   - string: 命令输出
+//   - error: 如果命令执行失败返回 error 对象；服务不存在或不支持 cmd 时，错误包装了 ErrUnsupported
+//
+// 示例：
+//
+//	// 等同于 am force-stop，但更快
+//	_, err := device.Cmd("activity", "force-stop", "com.example.app")
+//
+//	// 列出已安装的第三方应用
+//	output, err := device.Cmd("package", "list", "packages", "-3")
+func (d *Device) Cmd(service string, args ...string) (string, error) {
+	parts := []string{"cmd", shellQuote(service)}
+	for _, arg := range args {
+		parts = append(parts, shellQuote(arg))
+	}
+	output, err := d.Shell(strings.Join(parts, " "))
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(output, "Can't find service") || strings.Contains(output, "cmd: not found") {
+		return "", fmt.Errorf("cmd %s: %s: %w", service, output, ErrUnsupported)
+	}
+	return output, nil
+}
+
+// useCmd 判断设备是否优先使用 cmd 调用系统服务，API 级别只在首次调用时读取。
+func (d *Device) useCmd() bool {
+	sdk, err := d.SDKLevel()
+	return err == nil && sdk >= cmdMinSDK
+}
+
+// GrantPermission 授予应用运行时权限。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//   - permission: 完整的权限名，例如 "android.permission.CAMERA"
+//
+// 返回值：
+//   - error: 如果授予失败（例如权限未在清单中声明，或不是运行时权限），返回 error 对象
+//
+// 注意事项：
+//   - Android 7.0+ 使用 'cmd package grant'，更早的版本使用 'pm grant'
+//
+// 示例：
+//
+//	device.GrantPermission("com.example.app", "android.permission.CAMERA")
+//	device.GrantPermission("com.example.app", "android.permission.ACCESS_FINE_LOCATION")
+func (d *Device) GrantPermission(pkg, permission string) error {
+	return d.packagePermission("grant", d.packageOr(pkg), permission)
+}
+
+// RevokePermission 撤销应用的运行时权限，用于测试权限被拒绝的场景。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//   - permission: 完整的权限名
+//
+// 返回值：
+//   - error: 如果撤销失败，返回 error 对象
+//
+// 注意事项：
+//   - 撤销权限会导致应用进程被系统结束
+//
+// 示例：
+//
+//	device.RevokePermission("com.example.app", "android.permission.CAMERA")
+func (d *Device) RevokePermission(pkg, permission string) error {
+	return d.packagePermission("revoke", d.packageOr(pkg), permission)
+}
+
+// packagePermission 执行 grant / revoke 子命令。
+func (d *Device) packagePermission(action, pkg, permission string) error {
+	var output string
+	var err error
+	if d.useCmd() {
+		output, err = d.Cmd("package", action, pkg, permission)
+	} else {
+		output, err = d.Shellf("pm %s %s %s", action, pkg, permission)
+	}
+	if err != nil {
+		return err
+	}
+	// 成功时没有输出，失败时输出异常信息
+	if output != "" {
+		return fmt.Errorf("%s %s %s failed: %s", action, pkg, permission, output)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package adb
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// sdkDevice 返回 API 级别为 sdk 的伪造设备，除 getprop 外的命令输出 output。
+func sdkDevice(sdk, output string) (*Device, *fakeRunner) {
+	s := &propStore{props: map[string]string{"ro.build.version.sdk": sdk}}
+	return newFakeDevice(func(command string) (string, error) {
+		if strings.HasPrefix(command, "getprop") {
+			return s.respond(command)
+		}
+		return output, nil
+	})
+}
+
+// serviceCommands 返回除读取属性以外的命令。
+func serviceCommands(r *fakeRunner) []string {
+	var cmds []string
+	for _, c := range r.shellCommands() {
+		if !strings.HasPrefix(c, "getprop") {
+			cmds = append(cmds, c)
+		}
+	}
+	return cmds
+}
+
+func TestCmdOrPmByAPILevel(t *testing.T) {
+	tests := []struct {
+		sdk  string
+		want []string
+	}{
+		{"23", []string{
+			"pm 'grant' 'com.example.app' 'android.permission.CAMERA'",
+			"pm 'revoke' 'com.example.app' 'android.permission.CAMERA'",
+			"am force-stop com.example.app",
+		}},
+		{"34", []string{
+			"cmd 'package' 'grant' 'com.example.app' 'android.permission.CAMERA'",
+			"cmd 'package' 'revoke' 'com.example.app' 'android.permission.CAMERA'",
+			"cmd 'activity' 'force-stop' 'com.example.app'",
+		}},
+	}
+	for _, tt := range tests {
+		d, r := sdkDevice(tt.sdk, "")
+		if err := d.GrantPermission("com.example.app", "android.permission.CAMERA"); err != nil {
+			t.Errorf("API %s: GrantPermission: %v", tt.sdk, err)
+		}
+		if err := d.RevokePermission("com.example.app", "android.permission.CAMERA"); err != nil {
+			t.Errorf("API %s: RevokePermission: %v", tt.sdk, err)
+		}
+		if err := d.ForceStopApp("com.example.app"); err != nil {
+			t.Errorf("API %s: ForceStopApp: %v", tt.sdk, err)
+		}
+		if got := serviceCommands(r); !slices.Equal(got, tt.want) {
+			t.Errorf("API %s: commands = %q, want %q", tt.sdk, got, tt.want)
+		}
+
+		// API 级别只读取一次
+		reads := 0
+		for _, c := range r.shellCommands() {
+			if strings.HasPrefix(c, "getprop") {
+				reads++
+			}
+		}
+		if reads != 1 {
+			t.Errorf("API %s: read the SDK level %d times, want once", tt.sdk, reads)
+		}
+	}
+}
+
+func TestGrantPermissionFailure(t *testing.T) {
+	const failure = "Exception occurred while executing 'grant':\njava.lang.SecurityException: Package com.example.app has not requested permission android.permission.CAMERA"
+	d, _ := sdkDevice("34", failure)
+	err := d.GrantPermission("com.example.app", "android.permission.CAMERA")
+	if err == nil || !strings.Contains(err.Error(), "has not requested permission") {
+		t.Errorf("GrantPermission = %v, want the exception in the error", err)
+	}
+}
+
+func TestCmdMissingService(t *testing.T) {
+	d, r := sdkDevice("34", "Can't find service: locale")
+	if _, err := d.Cmd("locale", "get-app-locales", "it's"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Cmd(missing service) = %v, want ErrUnsupported", err)
+	}
+	if got, want := r.shellCommands(), []string{`cmd 'locale' 'get-app-locales' 'it'\''s'`}; !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
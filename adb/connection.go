@@ -0,0 +1,169 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeviceInfo 是 'adb devices -l' 中的一行。
+type DeviceInfo struct {
+	Serial      string // 序列号或网络地址
+	State       string // 连接状态：device、offline、unauthorized、recovery 等
+	Product     string // product: 字段
+	Model       string // model: 字段
+	Device      string // device: 字段
+	TransportID string // transport_id: 字段
+}
+
+// IsTCP 判断设备是否通过网络（adb connect）连接。
+func (i DeviceInfo) IsTCP() bool {
+	return isTCPSerial(i.Serial)
+}
+
+// GetDeviceList 获取 adb 已知的所有设备及其状态。
+// 与 GetDevices 不同，它也返回 offline、unauthorized 等状态的设备，并附带型号等信息。
+// 使用 PATH 中的 adb 和默认配置；需要自定义 adb 路径或 Runner 时使用 Device.DeviceList。
+//
+// 返回值：
+//   - []DeviceInfo: 设备列表，没有设备时为空切片
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 示例：
+//
+//	devices, err := adb.GetDeviceList()
+//	for _, dev := range devices {
+//	    fmt.Printf("%s %s %s\n", dev.Serial, dev.State, dev.Model)
+//	}
+func GetDeviceList() ([]DeviceInfo, error) {
+	return NewDevice().DeviceList()
+}
+
+// DeviceList 与 GetDeviceList 相同，但通过该实例配置的 adb 路径、Runner 和超时执行 'adb devices -l'。
+// 命令针对 adb 服务端，不添加 "-s serial"，返回的是所有设备而不只是当前设备。
+//
+// 返回值：
+//   - []DeviceInfo: 设备列表，没有设备时为空切片
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 示例：
+//
+//	device := adb.NewDeviceWithOptions(adb.WithAdbPath("/opt/android-sdk/platform-tools/adb"))
+//	devices, err := device.DeviceList()
+func (d *Device) DeviceList() ([]DeviceInfo, error) {
+	stdout, stderr, err := d.runAdb(context.Background(), nil, []string{"devices", "-l"})
+	output := append(stdout, stderr...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w, output: %s", err, string(output))
+	}
+	return parseDeviceList(string(output)), nil
+}
+
+// parseDeviceList 解析 'adb devices -l' 的输出，例如：
+// "emulator-5554          device product:sdk_gphone64 model:sdk_gphone64 device:emu64a transport_id:1"
+func parseDeviceList(output string) []DeviceInfo {
+	devices := []DeviceInfo{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		// 跳过标题、空行和 adb 服务启动时的 "* daemon ..." 提示
+		if len(fields) < 2 || strings.HasPrefix(line, "List of devices") || strings.HasPrefix(line, "*") {
+			continue
+		}
+		info := DeviceInfo{Serial: fields[0], State: fields[1]}
+		for _, f := range fields[2:] {
+			key, value, ok := strings.Cut(f, ":")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "product":
+				info.Product = value
+			case "model":
+				info.Model = value
+			case "device":
+				info.Device = value
+			case "transport_id":
+				info.TransportID = value
+			}
+		}
+		devices = append(devices, info)
+	}
+	return devices
+}
+
+// isTCPSerial 判断序列号是否为网络地址（host:port）。
+func isTCPSerial(serial string) bool {
+	i := strings.LastIndex(serial, ":")
+	return i > 0 && i < len(serial)-1 && !strings.HasPrefix(serial, "emulator-")
+}
+
+// WaitForConnection 等待设备出现在 'adb devices' 中并处于 device 状态。
+// 对于网络设备，掉线（不在列表中或 offline）时会主动执行 adb connect 重新连接，
+// 而 WaitForDevice 只是被动等待，适合长时间运行的无线测试。
+//
+// 参数：
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - error: 超时返回 error 对象，错误信息中包含已等待的时间、最后一次看到的状态和最后一次 adb connect 的输出
+//
+// 注意事项：
+//   - Serial 为空时，只有唯一一台 device 状态的设备时视为已连接
+//   - 网络设备处于 offline 时先 disconnect 再 connect，清除 adb 服务端残留的连接
+//   - 两次检查之间的间隔由 WithPollInterval 设置，默认 500 毫秒
+//
+// 示例：
+//
+//	device := adb.NewDevice("192.168.1.100:5555")
+//	if err := device.WaitForConnection(2 * time.Minute); err != nil {
+//	    // 例如：device 192.168.1.100:5555 not connected after 2m0s
+//	    // (last state "missing", last connect: failed to connect to '192.168.1.100:5555': Connection refused)
+//	    log.Fatal(err)
+//	}
+func (d *Device) WaitForConnection(timeout time.Duration) error {
+	start := time.Now()
+	state := "missing"
+	lastConnect := ""
+	for {
+		devices, err := d.DeviceList()
+		if err == nil {
+			state = "missing"
+			ready := 0
+			for _, dev := range devices {
+				if dev.State == "device" {
+					ready++
+				}
+				if dev.Serial == d.Serial {
+					state = dev.State
+				}
+			}
+			if (d.Serial == "" && ready == 1) || state == "device" {
+				return nil
+			}
+		}
+
+		if time.Since(start) >= timeout {
+			if lastConnect != "" {
+				return fmt.Errorf("device %s not connected after %s (last state %q, last connect: %s)",
+					d.Serial, time.Since(start).Round(time.Second), state, lastConnect)
+			}
+			return fmt.Errorf("device %s not connected after %s (last state %q)", d.Serial, time.Since(start).Round(time.Second), state)
+		}
+		if isTCPSerial(d.Serial) {
+			// connect / disconnect 针对 adb 服务端，与 DeviceList 一样不添加 "-s serial"
+			if state == "offline" {
+				d.runAdb(context.Background(), nil, []string{"disconnect", d.Serial})
+			}
+			if state == "offline" || state == "missing" {
+				// 连接失败时 adb connect 的退出码通常仍为 0，原因只出现在输出中
+				stdout, stderr, err := d.runAdb(context.Background(), nil, []string{"connect", d.Serial})
+				lastConnect = strings.TrimSpace(string(append(stdout, stderr...)))
+				if err != nil {
+					lastConnect = fmt.Sprintf("%v: %s", err, lastConnect)
+				}
+			}
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
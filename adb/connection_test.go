@@ -0,0 +1,87 @@
+package adb
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeviceListUsesRunner(t *testing.T) {
+	r := &fakeRunner{respond: func(context.Context, []string) (string, error) {
+		return "List of devices attached\n" +
+			"emulator-5554 device product:sdk model:Pixel device:emu64a transport_id:1\n" +
+			"192.168.1.100:5555 offline transport_id:2\n", nil
+	}}
+	d := NewDeviceWithOptions(WithSerial("emulator-5554"), WithRunner(r))
+
+	devices, err := d.DeviceList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 'adb devices' 针对服务端，不能带 "-s serial"
+	if len(r.calls) != 1 || !slices.Equal(r.calls[0], []string{"devices", "-l"}) {
+		t.Errorf("calls = %q, want [[devices -l]]", r.calls)
+	}
+	want := []DeviceInfo{
+		{Serial: "emulator-5554", State: "device", Product: "sdk", Model: "Pixel", Device: "emu64a", TransportID: "1"},
+		{Serial: "192.168.1.100:5555", State: "offline", TransportID: "2"},
+	}
+	if !slices.Equal(devices, want) {
+		t.Errorf("got %+v, want %+v", devices, want)
+	}
+}
+
+func TestWaitForConnectionReconnects(t *testing.T) {
+	const serial = "192.168.1.100:5555"
+	lists := []string{
+		serial + " offline transport_id:2\n",
+		"",
+		serial + " device product:sdk model:Pixel device:emu64a transport_id:3\n",
+	}
+	r := &fakeRunner{}
+	r.respond = func(_ context.Context, args []string) (string, error) {
+		switch args[0] {
+		case "devices":
+			list := lists[0]
+			if len(lists) > 1 {
+				lists = lists[1:]
+			}
+			return "List of devices attached\n" + list, nil
+		case "connect":
+			return "connected to " + serial, nil
+		}
+		return "", nil
+	}
+	d := NewDeviceWithOptions(WithSerial(serial), WithRunner(r), WithPollInterval(time.Millisecond))
+
+	if err := d.WaitForConnection(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	// connect / disconnect 针对服务端，不能带 "-s serial"
+	want := [][]string{
+		{"devices", "-l"}, {"disconnect", serial}, {"connect", serial},
+		{"devices", "-l"}, {"connect", serial},
+		{"devices", "-l"},
+	}
+	if !slices.EqualFunc(r.calls, want, slices.Equal[[]string]) {
+		t.Errorf("calls = %q, want %q", r.calls, want)
+	}
+}
+
+func TestWaitForConnectionTimeoutIncludesConnectOutput(t *testing.T) {
+	const refused = "failed to connect to '192.168.1.100:5555': Connection refused"
+	r := &fakeRunner{respond: func(_ context.Context, args []string) (string, error) {
+		if args[0] == "connect" {
+			return refused + "\n", nil
+		}
+		return "List of devices attached\n", nil
+	}}
+	d := NewDeviceWithOptions(WithSerial("192.168.1.100:5555"), WithRunner(r), WithPollInterval(time.Millisecond))
+
+	err := d.WaitForConnection(20 * time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), `last state "missing", last connect: `+refused+")") {
+		t.Errorf("WaitForConnection = %v, want the last connect output", err)
+	}
+}
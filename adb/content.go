@@ -0,0 +1,168 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// systemBarRe 匹配 'dumpsys window' 中状态栏和导航栏的 InsetsSource，例如：
+// "InsetsSource type=ITYPE_STATUS_BAR frame=[0,0][1080,63] visible=true"（Android 11-12）
+// "InsetsSource id=3c570000 type=navigationBars frame=[0,2274][1080,2400] visible=true"（Android 13+）
+var systemBarRe = regexp.MustCompile(`type=(ITYPE_STATUS_BAR|ITYPE_NAVIGATION_BAR|statusBars|navigationBars) frame=\[(-?\d+),(-?\d+)\]\[(-?\d+),(-?\d+)\]`)
+
+// gestureEdgeRatio 是手势导航时左右两侧返回手势区域占屏幕宽度的比例。
+const gestureEdgeRatio = 20
+
+// ContentBounds 返回当前应用内容区域的边界（屏幕当前方向的坐标），不包括状态栏、导航栏，
+// 手势导航时还不包括左右两侧的返回手势区域。在该区域内滑动不会误触发系统手势。
+//
+// 返回值：
+//   - uixml.Rect: 内容区域
+//   - error: 如果获取 UI 结构失败或无法确定应用窗口，返回 error 对象
+//
+// 工作原理：
+//  1. 从 UI 结构中取 android:id/content 节点的边界，没有时使用第一个非系统界面的顶层节点
+//  2. 从 'dumpsys window' 读取状态栏和导航栏的位置（Android 11+），从区域中去掉与它们重叠的部分，
+//     兼容沉浸式（edge-to-edge）布局的应用和横屏时位于侧边的导航栏
+//  3. 'settings get secure navigation_mode' 为 2（手势导航）时，左右两侧各去掉屏幕宽度的 1/20
+//
+// 示例：
+//
+//	r, err := device.ContentBounds()
+//	if err == nil {
+//	    fmt.Printf("内容区域: %dx%d\n", r.Width(), r.Height())
+//	}
+func (d *Device) ContentBounds() (uixml.Rect, error) {
+	xml, err := d.XML()
+	if err != nil {
+		return uixml.Rect{}, err
+	}
+	node, err := xml.Find(func(n, pn uixml.Node) bool { return n.ResourceID == "android:id/content" })
+	if err != nil {
+		for _, n := range xml.Nodes {
+			if n.Package != "com.android.systemui" {
+				node, err = n, nil
+				break
+			}
+		}
+	}
+	if err != nil {
+		return uixml.Rect{}, fmt.Errorf("app window: %w", ErrNotFound)
+	}
+	r, err := uixml.ParseBounds(node.Bounds)
+	if err != nil {
+		return uixml.Rect{}, err
+	}
+	screenW, screenH := r.X2, r.Y2
+	if len(xml.Nodes) > 0 {
+		if root, err := uixml.ParseBounds(xml.Nodes[0].Bounds); err == nil {
+			screenW, screenH = max(screenW, root.X2), max(screenH, root.Y2)
+		}
+	}
+
+	if output, err := d.Shell("dumpsys window"); err == nil {
+		seen := map[string]bool{}
+		for _, m := range systemBarRe.FindAllStringSubmatch(output, -1) {
+			// 同一种系统栏可能在多个窗口的信息中重复出现，只取第一次
+			if seen[m[1]] {
+				continue
+			}
+			seen[m[1]] = true
+			var bar uixml.Rect
+			bar.X1, _ = strconv.Atoi(m[2])
+			bar.Y1, _ = strconv.Atoi(m[3])
+			bar.X2, _ = strconv.Atoi(m[4])
+			bar.Y2, _ = strconv.Atoi(m[5])
+			r = excludeBar(r, bar, screenW, screenH)
+		}
+	}
+
+	if mode, err := d.GetSetting("secure", "navigation_mode"); err == nil && mode == "2" {
+		edge := screenW / gestureEdgeRatio
+		r.X1 = max(r.X1, edge)
+		r.X2 = min(r.X2, screenW-edge)
+	}
+
+	if r.Width() <= 0 || r.Height() <= 0 {
+		return uixml.Rect{}, fmt.Errorf("empty content bounds %+v", r)
+	}
+	return r, nil
+}
+
+// excludeBar 从区域 r 中去掉贴在屏幕某一边的系统栏 bar。
+func excludeBar(r, bar uixml.Rect, screenW, screenH int) uixml.Rect {
+	if bar.Width() <= 0 || bar.Height() <= 0 {
+		return r
+	}
+	switch {
+	case bar.Width() >= bar.Height() && bar.Y1 <= 0: // 顶部
+		r.Y1 = max(r.Y1, bar.Y2)
+	case bar.Width() >= bar.Height() && bar.Y2 >= screenH: // 底部
+		r.Y2 = min(r.Y2, bar.Y1)
+	case bar.X1 <= 0: // 左侧
+		r.X1 = max(r.X1, bar.X2)
+	case bar.X2 >= screenW: // 右侧
+		r.X2 = min(r.X2, bar.X1)
+	}
+	return r
+}
+
+// safeSwipeArea 返回 area 与 ContentBounds 的交集，滑动辅助方法用它把起点和终点限制在内容区域内，
+// 避免触发返回手势或下拉通知栏。无法获取内容区域或交集为空时返回 area 本身。
+func (d *Device) safeSwipeArea(area uixml.Rect) uixml.Rect {
+	content, err := d.ContentBounds()
+	if err != nil {
+		return area
+	}
+	r := uixml.Rect{
+		X1: max(area.X1, content.X1), Y1: max(area.Y1, content.Y1),
+		X2: min(area.X2, content.X2), Y2: min(area.Y2, content.Y2),
+	}
+	if r.Width() <= 0 || r.Height() <= 0 {
+		return area
+	}
+	return r
+}
+
+// SwipeContent 在应用内容区域内滑动，起点和终点都在 ContentBounds 之内，避免触发返回手势或下拉通知栏。
+//
+// 参数：
+//   - direction: 手指移动的方向：Above 向上（内容向下滚动）、Below 向下、LeftOf 向左、RightOf 向右
+//   - duration: 滑动时长
+//
+// 返回值：
+//   - error: 如果方向非法、获取内容区域或滑动失败，返回 error 对象
+//
+// 注意事项：
+//   - 沿滑动方向从区域的 3/4 处滑到 1/4 处，另一方向位于区域中央
+//
+// 示例：
+//
+//	// 向上滑动翻到下一页
+//	err := device.SwipeContent(adb.Above, 300*time.Millisecond)
+func (d *Device) SwipeContent(direction Direction, duration time.Duration) error {
+	r, err := d.ContentBounds()
+	if err != nil {
+		return err
+	}
+	cx, cy := r.Center()
+	near, far := r.Height()/4, r.Height()*3/4
+	switch direction {
+	case Above:
+		return d.swipe(cx, r.Y1+far, cx, r.Y1+near, int(duration.Milliseconds()))
+	case Below:
+		return d.swipe(cx, r.Y1+near, cx, r.Y1+far, int(duration.Milliseconds()))
+	}
+	near, far = r.Width()/4, r.Width()*3/4
+	switch direction {
+	case LeftOf:
+		return d.swipe(r.X1+far, cy, r.X1+near, cy, int(duration.Milliseconds()))
+	case RightOf:
+		return d.swipe(r.X1+near, cy, r.X1+far, cy, int(duration.Milliseconds()))
+	}
+	return fmt.Errorf("bad direction %v", direction)
+}
@@ -0,0 +1,127 @@
+package adb
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ScalePoint 将在 fromW x fromH 分辨率下记录的坐标按比例换算到 toW x toH 分辨率。
+// 结果四舍五入并限制在屏幕范围内。
+func ScalePoint(x, y, fromW, fromH, toW, toH int) Point {
+	sx := (x*toW*2 + fromW) / (fromW * 2)
+	sy := (y*toH*2 + fromH) / (fromH * 2)
+	return Point{X: min(max(sx, 0), toW-1), Y: min(max(sy, 0), toH-1)}
+}
+
+// NormalizeTap 把在另一种分辨率的设备上记录的坐标按比例换算到当前设备后点击，
+// 用于在分辨率不同的设备上回放基于坐标的操作脚本。
+//
+// 参数：
+//   - x, y: 记录时的坐标（竖屏 / 自然方向）
+//   - fromW, fromH: 记录时设备的屏幕尺寸（自然方向）
+//
+// 返回值：
+//   - error: 如果记录尺寸无效、获取屏幕尺寸或点击失败，返回 error 对象
+//
+// 精度限制：
+//   - 纯比例换算只在两台设备宽高比相同时准确；宽高比不同（例如 16:9 与 20:9）时，
+//     界面通常是多出的高度用于显示更多内容，而不是拉伸，越靠近屏幕下方的坐标偏差越大
+//   - 按 dp 布局的控件在不同像素密度下大小不同，状态栏、导航栏、刘海的高度也各不相同
+//   - 能用选择器定位时应优先使用选择器，坐标只作为最后手段；对关键坐标可用 CoordCache 按设备分别校准
+//
+// 注意事项：
+//   - 换算后的坐标总是按当前屏幕方向转换（参见 RotatePoint），横屏时无需设置 RotateCoords
+//
+// 示例：
+//
+//	// 脚本在 1080x2400 的设备上录制
+//	err := device.NormalizeTap(540, 1800, 1080, 2400)
+func (d *Device) NormalizeTap(x, y, fromW, fromH int) error {
+	if fromW <= 0 || fromH <= 0 {
+		return fmt.Errorf("bad source resolution %dx%d", fromW, fromH)
+	}
+	w, h, err := d.cachedScreenSize()
+	if err != nil {
+		return err
+	}
+	p := ScalePoint(x, y, fromW, fromH, w, h)
+	// 换算结果处于自然方向，总是按当前旋转角度转换，与 RotateCoords 无关
+	rx, ry, err := d.RotatePoint(p.X, p.Y)
+	if err != nil {
+		return err
+	}
+	return d.tap(rx, ry)
+}
+
+// coordKey 是 CoordCache 的键：同一台设备在同一分辨率下的一个命名坐标。
+type coordKey struct {
+	serial string
+	w, h   int
+	name   string
+}
+
+// CoordCache 按 "设备序列号 + 屏幕尺寸" 缓存命名坐标，可在多台设备、多个 goroutine 之间共享。
+// 典型用法是第一次通过选择器或图像识别找到目标后缓存其坐标，之后在同一设备上直接点击；
+// 设备修改了分辨率（wm size）时键不同，缓存自动失效。
+// 零值可以直接使用；每次 Lookup / Store 都会读取一次 'wm size' 以构造键。
+//
+// 示例：
+//
+//	cache := adb.NewCoordCache()
+//	p, ok, _ := cache.Lookup(device, "start_button")
+//	if !ok {
+//	    node, err := device.FindNode(adb.ByHint("开始"))
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    x, y := node.Middle()
+//	    p = adb.Point{X: x, Y: y}
+//	    cache.Store(device, "start_button", p)
+//	}
+//	device.Tap(p.X, p.Y)
+type CoordCache struct {
+	mu     sync.Mutex
+	points map[coordKey]Point
+}
+
+// NewCoordCache 创建一个空的坐标缓存。
+func NewCoordCache() *CoordCache {
+	return &CoordCache{points: map[coordKey]Point{}}
+}
+
+// Lookup 查找设备当前分辨率下名为 name 的坐标，不存在时第二个返回值为 false。
+func (c *CoordCache) Lookup(d *Device, name string) (Point, bool, error) {
+	key, err := c.key(d, name)
+	if err != nil {
+		return Point{}, false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.points[key]
+	return p, ok, nil
+}
+
+// Store 记录设备当前分辨率下名为 name 的坐标。
+func (c *CoordCache) Store(d *Device, name string, p Point) error {
+	key, err := c.key(d, name)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.points == nil {
+		c.points = map[coordKey]Point{}
+	}
+	c.points[key] = p
+	return nil
+}
+
+// key 使用设备当前的屏幕尺寸构造缓存键。
+// 不使用 cachedScreenSize：它在设备实例的生命周期内不会更新，分辨率修改后键不会变化。
+func (c *CoordCache) key(d *Device, name string) (coordKey, error) {
+	w, h, err := d.ScreenSize()
+	if err != nil {
+		return coordKey{}, err
+	}
+	return coordKey{serial: d.Serial, w: w, h: h, name: name}, nil
+}
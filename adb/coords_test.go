@@ -0,0 +1,49 @@
+package adb
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCoordCacheZeroValue(t *testing.T) {
+	size := "Physical size: 1080x2400"
+	d, _ := newFakeDevice(func(command string) (string, error) { return size, nil })
+
+	var cache CoordCache
+	if _, ok, err := cache.Lookup(d, "start"); err != nil || ok {
+		t.Fatalf("Lookup on empty cache = %v, %v", ok, err)
+	}
+	if err := cache.Store(d, "start", Point{X: 10, Y: 20}); err != nil {
+		t.Fatal(err)
+	}
+	if p, ok, err := cache.Lookup(d, "start"); err != nil || !ok || p != (Point{X: 10, Y: 20}) {
+		t.Fatalf("Lookup = %v, %v, %v; want {10 20}", p, ok, err)
+	}
+
+	// 修改分辨率后键不同，缓存失效
+	size = "Physical size: 1080x2400\nOverride size: 720x1600"
+	if _, ok, err := cache.Lookup(d, "start"); err != nil || ok {
+		t.Errorf("Lookup after wm size = %v, %v; want miss", ok, err)
+	}
+}
+
+func TestNormalizeTapRotates(t *testing.T) {
+	d, r := newFakeDevice(func(command string) (string, error) {
+		switch command {
+		case "wm size":
+			return "Physical size: 1080x2400", nil
+		case dumpCommand:
+			return strings.Replace(hierarchy(), `rotation="0"`, `rotation="1"`, 1), nil
+		}
+		return "", nil
+	})
+	if err := d.NormalizeTap(540, 1800, 1080, 2400); err != nil {
+		t.Fatal(err)
+	}
+	cmds := r.shellCommands()
+	// 自然方向的 (540,1800) 在旋转 90 度后为 (1800, 1080-540)
+	if i := slices.Index(cmds, "input tap 1800 540"); i < 0 {
+		t.Errorf("commands = %q, want input tap 1800 540", cmds)
+	}
+}
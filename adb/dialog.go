@@ -0,0 +1,93 @@
+package adb
+
+import (
+	"fmt"
+	"time"
+)
+
+// DialogMatcher 描述一种需要自动关闭的弹窗：如何识别它，以及点击哪里关闭它。
+//
+// 示例：
+//
+//	rating := adb.DialogMatcher{
+//	    Name:    "评分弹窗",
+//	    Detect:  func(n, pn uixml.Node) bool { return strings.Contains(n.Text, "给我们评分") },
+//	    Dismiss: func(n, pn uixml.Node) bool { return n.Text == "以后再说" },
+//	}
+type DialogMatcher struct {
+	Name    string       // 名称，仅用于错误信息
+	Detect  FindNodeFunc // 识别弹窗的查找函数
+	Dismiss FindNodeFunc // 关闭按钮的查找函数；为 nil 时按返回键关闭
+}
+
+// dialogSettleDelay 是关闭弹窗后等待界面稳定的时间。
+const dialogSettleDelay = 500 * time.Millisecond
+
+// DismissDialogs 自动关闭干扰流程的弹窗，例如广告、评分提示、系统权限对话框。
+// 每一轮 dump 一次屏幕，找到第一个出现的弹窗并点击它的关闭按钮，直到没有弹窗或达到最大轮数。
+//
+// 参数：
+//   - matchers: 弹窗匹配规则，按顺序检查
+//   - maxRounds: 最多关闭的轮数，用于防止关闭按钮无效时无限循环
+//
+// 返回值：
+//   - int: 关闭的弹窗数量
+//   - error: 如果 dump 失败，或识别到弹窗却找不到关闭按钮，返回 error 对象
+//
+// 注意事项：
+//   - 每次关闭后等待 500 毫秒再重新 dump，连续弹出的多个弹窗会在后续轮次中处理
+//   - 达到 maxRounds 时直接返回，不视为错误
+//
+// 示例：
+//
+//	count, err := device.DismissDialogs([]adb.DialogMatcher{
+//	    {
+//	        Name:    "权限请求",
+//	        Detect:  func(n, pn uixml.Node) bool { return n.ResourceID == "com.android.permissioncontroller:id/grant_dialog" },
+//	        Dismiss: func(n, pn uixml.Node) bool { return n.ResourceID == "com.android.permissioncontroller:id/permission_allow_button" },
+//	    },
+//	    {
+//	        Name:    "广告",
+//	        Detect:  func(n, pn uixml.Node) bool { return n.ResourceID == "com.example:id/ad_container" },
+//	        Dismiss: func(n, pn uixml.Node) bool { return n.ContentDesc == "关闭" },
+//	    },
+//	}, 5)
+//	fmt.Printf("关闭了 %d 个弹窗\n", count)
+func (d *Device) DismissDialogs(matchers []DialogMatcher, maxRounds int) (int, error) {
+	count := 0
+	for round := 0; round < maxRounds; round++ {
+		xml, err := d.XML()
+		if err != nil {
+			return count, err
+		}
+
+		dismissed := false
+		for _, m := range matchers {
+			if _, err := xml.Find(m.Detect); err != nil {
+				continue
+			}
+			if m.Dismiss == nil {
+				err = d.PressBack()
+			} else {
+				node, ferr := xml.Find(m.Dismiss)
+				if ferr != nil {
+					return count, fmt.Errorf("dialog %q: dismiss button: %w", m.Name, ferr)
+				}
+				err = d.ClickNodeBy(node)
+			}
+			if err != nil {
+				return count, err
+			}
+			count++
+			dismissed = true
+			// 界面已变化，需要重新 dump
+			break
+		}
+
+		if !dismissed {
+			return count, nil
+		}
+		time.Sleep(dialogSettleDelay)
+	}
+	return count, nil
+}
@@ -0,0 +1,342 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// sizeRe 用于解析 'wm size' 的输出，例如 "Physical size: 1080x2340"。
+var sizeRe = regexp.MustCompile(`(Physical|Override) size: (\d+)x(\d+)`)
+
+// ScreenSize 获取屏幕在自然方向（通常是竖屏）下的宽高。
+// 如果通过 'wm size' 设置过覆盖分辨率，返回覆盖后的分辨率。
+//
+// 返回值：
+//   - w: 屏幕宽度（像素）
+//   - h: 屏幕高度（像素）
+//   - err: 如果命令执行失败或输出无法解析，返回 error 对象
+//
+// 注意事项：
+//   - 返回值不随屏幕旋转变化，横屏时屏幕实际的横向像素数为 h
+//
+// 示例：
+//
+//	w, h, err := device.ScreenSize()
+//	if err == nil {
+//	    device.Tap(w/2, h/2) // 点击屏幕中心
+//	}
+func (d *Device) ScreenSize() (w, h int, err error) {
+	output, err := d.Shell("wm size")
+	if err != nil {
+		return 0, 0, err
+	}
+	matches := sizeRe.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return 0, 0, fmt.Errorf("unexpected wm size output: %s", output)
+	}
+	// Override size 出现在 Physical size 之后，取最后一个即可
+	m := matches[len(matches)-1]
+	w, _ = strconv.Atoi(m[2])
+	h, _ = strconv.Atoi(m[3])
+	return w, h, nil
+}
+
+// cachedScreenSize 返回缓存的自然方向屏幕尺寸，首次调用时通过 ScreenSize 读取。
+// 物理分辨率在连接期间基本不变，适合用于频繁调用的手势计算。
+func (d *Device) cachedScreenSize() (w, h int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.screenW == 0 {
+		if d.screenW, d.screenH, err = d.ScreenSize(); err != nil {
+			d.screenW, d.screenH = 0, 0
+			return 0, 0, err
+		}
+	}
+	return d.screenW, d.screenH, nil
+}
+
+// orientedScreenSize 返回当前方向下的屏幕尺寸：横屏（90 / 270 度）时交换缓存的自然方向宽高。
+// UI 节点的 bounds 和不经旋转转换的手势坐标都处于当前方向，与它们比较时使用该尺寸。
+func (d *Device) orientedScreenSize() (w, h int, err error) {
+	if w, h, err = d.cachedScreenSize(); err != nil {
+		return 0, 0, err
+	}
+	rotation, err := d.Rotation()
+	if err != nil {
+		return 0, 0, err
+	}
+	if rotation == 90 || rotation == 270 {
+		w, h = h, w
+	}
+	return w, h, nil
+}
+
+// Rotation 获取屏幕当前的旋转角度。
+// 该值来自 UIAutomator dump 中 <hierarchy rotation="..."> 属性。
+//
+// 返回值：
+//   - int: 旋转角度，取值为 0、90、180、270
+//   - error: 如果获取 dump 失败或属性无法解析，返回 error 对象
+//
+// 注意事项：
+//   - 每次调用都会 dump 一次屏幕，有一定耗时
+//   - dump 中的 rotation 是 Surface.ROTATION_* 常量（0-3），这里换算为角度
+//
+// 示例：
+//
+//	rotation, err := device.Rotation()
+//	if err == nil && (rotation == 90 || rotation == 270) {
+//	    fmt.Println("当前为横屏")
+//	}
+func (d *Device) Rotation() (int, error) {
+	xml, err := d.XML()
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.Atoi(xml.Rotation)
+	if err != nil {
+		return 0, fmt.Errorf("bad rotation %q: %w", xml.Rotation, err)
+	}
+	// ROTATION_0..ROTATION_270 对应 0..3
+	if value >= 0 && value <= 3 {
+		return value * 90, nil
+	}
+	return value, nil
+}
+
+// RotatePoint 将竖屏（自然方向）下的坐标转换为当前旋转方向下的屏幕坐标。
+// 开启 Device.RotateCoords 后，Tap 和 Swipe 会自动调用该转换。
+//
+// 参数：
+//   - x, y: 竖屏坐标系下的坐标
+//
+// 返回值：
+//   - rx, ry: 当前旋转方向下 input 命令使用的坐标
+//   - err: 如果获取旋转角度或屏幕尺寸失败，返回 error 对象
+//
+// 转换公式（W、H 为竖屏下的宽高，即 ScreenSize 的返回值）：
+//   - 0°:   (x, y)         -> (x, y)
+//   - 90°:  (x, y)         -> (y, W - x)      屏幕逆时针转为横屏，原来的顶部在左侧
+//   - 180°: (x, y)         -> (W - x, H - y)  上下颠倒
+//   - 270°: (x, y)         -> (H - y, x)      屏幕顺时针转为横屏，原来的顶部在右侧
+//
+// 注意事项：
+//   - 每次转换需要 dump 屏幕和读取屏幕尺寸，频繁点击时建议自行缓存
+//   - 从 UI 节点得到的坐标（Middle 等）已经是当前方向的坐标，不需要转换
+//
+// 示例：
+//
+//	// 竖屏下录制的坐标，在横屏时依然点到同一个位置
+//	x, y, err := device.RotatePoint(200, 1600)
+//	if err == nil {
+//	    device.Shell(fmt.Sprintf("input tap %d %d", x, y))
+//	}
+func (d *Device) RotatePoint(x, y int) (rx, ry int, err error) {
+	rotation, w, h, err := d.rotationAndSize()
+	if err != nil {
+		return 0, 0, err
+	}
+	rx, ry = rotatePoint(x, y, rotation, w, h)
+	return rx, ry, nil
+}
+
+// rotationAndSize 同时获取旋转角度和竖屏尺寸。
+func (d *Device) rotationAndSize() (rotation, w, h int, err error) {
+	if rotation, err = d.Rotation(); err != nil {
+		return 0, 0, 0, err
+	}
+	if w, h, err = d.ScreenSize(); err != nil {
+		return 0, 0, 0, err
+	}
+	return rotation, w, h, nil
+}
+
+// rotatePoint 按旋转角度转换坐标，w、h 为竖屏下的宽高。
+func rotatePoint(x, y, rotation, w, h int) (int, int) {
+	switch rotation {
+	case 90:
+		return y, w - x
+	case 180:
+		return w - x, h - y
+	case 270:
+		return h - y, x
+	default:
+		return x, y
+	}
+}
+
+// densityRe 用于解析 'wm density' 的输出，例如 "Physical density: 420"、"Override density: 480"。
+var densityRe = regexp.MustCompile(`(Physical|Override) density: (\d+)`)
+
+// 字体缩放的合理范围。系统设置中提供的档位约为 0.85 ~ 2.0。
+const (
+	minFontScale = 0.5
+	maxFontScale = 3.0
+)
+
+// SetFontScale 修改系统字体缩放比例，用于测试大字体下的界面布局（无障碍测试）。
+//
+// 参数：
+//   - scale: 缩放比例，1.0 为默认大小，取值范围 0.5 ~ 3.0
+//
+// 返回值：
+//   - float64: 修改前的比例，测试结束后可传回 SetFontScale 恢复
+//   - error: 如果 scale 超出范围或修改失败，返回 error 对象
+//
+// 注意事项：
+//   - 修改后前台应用会重新布局（通常会重建 Activity），可调用 WaitForIdle 等待界面稳定
+//   - 修改成功后会记录原来的值，调用 Cleanup 时恢复
+//
+// 示例：
+//
+//	prev, err := device.SetFontScale(1.3)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer device.SetFontScale(prev)
+//	device.WaitForIdle(time.Second, 10*time.Second)
+func (d *Device) SetFontScale(scale float64) (float64, error) {
+	if scale < minFontScale || scale > maxFontScale {
+		return 0, fmt.Errorf("font scale %g out of range [%g, %g]", scale, minFontScale, maxFontScale)
+	}
+	prev := 1.0
+	value, err := d.GetSetting("system", "font_scale")
+	if err != nil {
+		return 0, err
+	}
+	// 从未修改过时没有该设置项
+	if v, err := strconv.ParseFloat(value, 64); err == nil {
+		prev = v
+	}
+	if err := d.PutSetting("system", "font_scale", strconv.FormatFloat(scale, 'f', -1, 64)); err != nil {
+		return 0, err
+	}
+	d.pushUndo("font scale", func() error { return d.restoreSetting("system", "font_scale", value) })
+	return prev, nil
+}
+
+// DisplayDensity 返回当前的屏幕密度（dpi），设置过覆盖值时返回覆盖值。
+func (d *Device) DisplayDensity() (int, error) {
+	dpi, _, err := d.density()
+	return dpi, err
+}
+
+// density 返回当前的屏幕密度，以及是否设置了覆盖值。
+func (d *Device) density() (dpi int, override bool, err error) {
+	output, err := d.Shell("wm density")
+	if err != nil {
+		return 0, false, err
+	}
+	matches := densityRe.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return 0, false, fmt.Errorf("unexpected wm density output: %s", output)
+	}
+	// Override density 出现在 Physical density 之后，取最后一个即可
+	m := matches[len(matches)-1]
+	dpi, err = strconv.Atoi(m[2])
+	return dpi, m[1] == "Override", err
+}
+
+// SetDisplayDensity 修改屏幕密度（'wm density <dpi>'），相当于设置中的 "显示大小"，用于测试界面缩放。
+//
+// 参数：
+//   - dpi: 新的密度，必须大于 0，例如 480
+//
+// 返回值：
+//   - int: 修改前的密度，测试结束后可传回 SetDisplayDensity 恢复，或调用 ResetDisplayDensity
+//   - error: 如果 dpi 无效或修改失败，返回 error 对象
+//
+// 注意事项：
+//   - 修改后所有应用都会重新布局，可调用 WaitForIdle 等待界面稳定
+//   - 修改会一直保留到重置，即使重启设备
+//   - 修改成功后会记录原来的值，调用 Cleanup 时恢复
+//
+// 示例：
+//
+//	prev, err := device.SetDisplayDensity(560)
+//	if err == nil {
+//	    defer device.SetDisplayDensity(prev)
+//	}
+func (d *Device) SetDisplayDensity(dpi int) (int, error) {
+	if dpi <= 0 {
+		return 0, fmt.Errorf("bad density %d", dpi)
+	}
+	prev, override, err := d.density()
+	if err != nil {
+		return 0, err
+	}
+	output, err := d.Shellf("wm density %d", dpi)
+	if err != nil {
+		return 0, err
+	}
+	if output != "" {
+		return 0, fmt.Errorf("wm density %d failed: %s", dpi, output)
+	}
+	d.pushUndo("display density", func() error {
+		if !override {
+			return d.ResetDisplayDensity()
+		}
+		_, err := d.Shellf("wm density %d", prev)
+		return err
+	})
+	return prev, nil
+}
+
+// ResetDisplayDensity 恢复屏幕的物理密度（'wm density reset'）。
+func (d *Device) ResetDisplayDensity() error {
+	output, err := d.Shell("wm density reset")
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		return fmt.Errorf("wm density reset failed: %s", output)
+	}
+	return nil
+}
+
+// FreezeRotation 关闭自动旋转并把屏幕固定在指定方向，避免测试过程中设备被转动导致布局变化。
+//
+// 参数：
+//   - rotation: 旋转角度，取值为 0、90、180、270，与 Rotation 的返回值一致
+//
+// 返回值：
+//   - error: 如果角度无效或修改设置失败，返回 error 对象
+//
+// 注意事项：
+//   - 通过设置 accelerometer_rotation=0 和 user_rotation 实现，只对允许旋转的界面生效，
+//     锁定了方向的 Activity 仍保持自己的方向
+//   - 修改成功后会记录原来的自动旋转设置，调用 Cleanup 时恢复
+//
+// 示例：
+//
+//	if err := device.FreezeRotation(0); err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer device.Cleanup()
+func (d *Device) FreezeRotation(rotation int) error {
+	if rotation%90 != 0 || rotation < 0 || rotation > 270 {
+		return fmt.Errorf("bad rotation %d", rotation)
+	}
+	prevAuto, err := d.GetSetting("system", "accelerometer_rotation")
+	if err != nil {
+		return err
+	}
+	prevUser, err := d.GetSetting("system", "user_rotation")
+	if err != nil {
+		return err
+	}
+	if err := d.PutSetting("system", "accelerometer_rotation", "0"); err != nil {
+		return err
+	}
+	if err := d.PutSetting("system", "user_rotation", strconv.Itoa(rotation/90)); err != nil {
+		return err
+	}
+	d.pushUndo("rotation", func() error {
+		if err := d.restoreSetting("system", "user_rotation", prevUser); err != nil {
+			return err
+		}
+		return d.restoreSetting("system", "accelerometer_rotation", prevAuto)
+	})
+	return nil
+}
@@ -0,0 +1,44 @@
+package adb
+
+import (
+	"errors"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// ErrRootRequired 表示操作需要 root 权限（adbd 以 root 运行或设备已 root），
+// 而当前 shell 用户不具备该权限。
+//
+// 使用方式：
+//
+//	err := device.SetProp("persist.sys.timezone", "Asia/Shanghai")
+//	if errors.Is(err, adb.ErrRootRequired) {
+//	    log.Println("设备未 root，跳过时区设置")
+//	}
+var ErrRootRequired = errors.New("requires root")
+
+// ErrProductionBuild 表示设备是正式版（user）系统，adbd 无法以 root 运行，
+// 'adb root'、'adb remount' 等操作只能在 userdebug / eng 系统上使用。
+var ErrProductionBuild = errors.New("adbd cannot run as root in production builds")
+
+// ErrUnsupported 表示当前设备或 Android 版本不支持该操作。
+var ErrUnsupported = errors.New("unsupported on this device")
+
+// ErrNotRunning 表示目标应用或进程当前没有运行。
+var ErrNotRunning = errors.New("not running")
+
+// ErrNotInstalled 表示目标应用没有安装在设备上。
+var ErrNotInstalled = errors.New("not installed")
+
+// ErrInsufficientStorage 表示设备存储空间不足，详见 WithStorageCheck。
+var ErrInsufficientStorage = errors.New("insufficient storage")
+
+// ErrNotFound 表示没有找到匹配的节点或内容，与 uixml.ErrNotFound 是同一个值。
+//
+// 使用方式：
+//
+//	_, err := device.FindNode(fn)
+//	if errors.Is(err, adb.ErrNotFound) {
+//	    log.Println("元素不存在")
+//	}
+var ErrNotFound = uixml.ErrNotFound
@@ -2,8 +2,11 @@ package adb
 
 import (
 	"fmt"
+	"io"
 	"regexp"
 	"strings"
+
+	"github.com/LucaHhx/adb/adb/uixml"
 )
 
 // Execout 执行 adb exec-out 命令并返回输出。
@@ -36,6 +39,33 @@ func (d *Device) Execout(command string) (string, error) {
 	return d.execCommand("exec-out", command)
 }
 
+// ExecoutTo 执行 adb exec-out 命令，把输出原样写入 w。
+// 与 Execout 不同，输出不会整体缓存在内存中，也不会去除空白或合并标准错误，
+// 适合截图、录屏、tar 归档等可能有数十 MB 的二进制数据。
+//
+// 参数：
+//   - w: 输出的写入目标，例如 *os.File、网络连接或 hash.Hash
+//   - command: 要在设备上执行的命令字符串
+//
+// 返回值：
+//   - error: 如果命令执行失败或写入 w 失败，返回 error 对象（包含标准错误的内容）
+//
+// 注意事项：
+//   - 命令失败时 w 中可能已经写入了部分数据
+//   - WithTimeout 设置的超时同样适用，长时间的输出需要相应调大超时
+//   - 设置了 WithRunner 时输出由 Runner 返回，会先完整缓存再写入 w
+//
+// 示例：
+//
+//	f, _ := os.Create("screen.png")
+//	defer f.Close()
+//	if err := device.ExecoutTo(f, "screencap -p"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (d *Device) ExecoutTo(w io.Writer, command string) error {
+	return d.stream(w, "exec-out", command)
+}
+
 // errorMap 存储常见错误信息的翻译映射。
 // 用于将设备返回的非中文错误信息转换为中文，便于理解。
 var errorMap = map[string]string{
@@ -230,7 +260,76 @@ func (d *Device) Regexp(rex string) (string, error) {
 	}
 
 	// 没有找到匹配项
-	return "", fmt.Errorf("not found")
+	return "", ErrNotFound
+}
+
+// ScreenText 提取当前屏幕上所有可见的文本，按 UI 树的遍历顺序排列。
+// 每个节点取 text，text 为空时取 content-desc；空白文本被忽略。
+//
+// 返回值：
+//   - []string: 屏幕上的文本，每个节点一项
+//   - error: 如果获取 UI 结构失败，返回 error 对象
+//
+// 示例：
+//
+//	texts, err := device.ScreenText()
+//	if err == nil {
+//	    fmt.Println(strings.Join(texts, "\n"))
+//	}
+func (d *Device) ScreenText() ([]string, error) {
+	xml, err := d.XML()
+	if err != nil {
+		return nil, err
+	}
+	var texts []string
+	for _, n := range xml.FindAll(func(n, pn uixml.Node) bool { return true }) {
+		text := n.Text
+		if strings.TrimSpace(text) == "" {
+			text = n.ContentDesc
+		}
+		if strings.TrimSpace(text) != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts, nil
+}
+
+// ScreenTextMatches 判断屏幕上是否有文本匹配正则表达式，并返回第一个匹配的子匹配。
+// 与 Regexp 直接匹配 dump 出的 XML 不同，它只匹配节点的文本（ScreenText），
+// 不会误匹配到 resource-id、class 等属性或 XML 语法。
+//
+// 参数：
+//   - pattern: 正则表达式（Go RE2 语法），对每个节点的文本单独匹配
+//
+// 返回值：
+//   - bool: 是否有文本匹配
+//   - []string: 第一个匹配的结果，下标 0 为整个匹配，之后为各个捕获组；没有匹配时为 nil
+//   - error: 如果正则表达式无法编译或获取 UI 结构失败，返回 error 对象
+//
+// 示例：
+//
+//	ok, m, err := device.ScreenTextMatches(`订单号[:：]\s*(\d+)`)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if ok {
+//	    fmt.Println("订单号:", m[1])
+//	}
+func (d *Device) ScreenTextMatches(pattern string) (bool, []string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, nil, err
+	}
+	texts, err := d.ScreenText()
+	if err != nil {
+		return false, nil, err
+	}
+	for _, text := range texts {
+		if m := re.FindStringSubmatch(text); m != nil {
+			return true, m, nil
+		}
+	}
+	return false, nil, nil
 }
 
 // FindDesc 根据元素的边界坐标（bounds）查找其 content-desc 属性值。
@@ -0,0 +1,62 @@
+package adb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecoutToStreamsLargeOutput(t *testing.T) {
+	fakeAdb(t)
+	d := NewDeviceWithOptions(WithTimeout(30 * time.Second))
+	const size = 16 << 20
+	line := "0123456789abcdef\n"
+	want := sha256.Sum256([]byte(strings.Repeat(line, size/len(line)+1)[:size]))
+
+	h := sha256.New()
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	err := d.ExecoutTo(h, fmt.Sprintf("yes %s | head -c %d", strings.TrimSpace(line), size))
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Sum(nil); !bytes.Equal(got, want[:]) {
+		t.Error("streamed output differs from the generated blob")
+	}
+	// 输出直接写入 w，分配的内存应远小于输出本身
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > size/4 {
+		t.Errorf("allocated %d bytes while streaming %d bytes", alloc, size)
+	}
+}
+
+func TestExecoutToKeepsBinaryBytes(t *testing.T) {
+	fakeAdb(t)
+	d := NewDeviceWithOptions(WithTimeout(10 * time.Second))
+	var b bytes.Buffer
+	if err := d.ExecoutTo(&b, `printf '\r\n  \000\377\n'`); err != nil {
+		t.Fatal(err)
+	}
+	if want := "\r\n  \x00\xff\n"; b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
+
+func TestExecoutToReportsWriteError(t *testing.T) {
+	fakeAdb(t)
+	d := NewDeviceWithOptions(WithTimeout(10 * time.Second))
+	if err := d.ExecoutTo(failingWriter{}, "yes | head -c 100000"); err == nil {
+		t.Error("ExecoutTo succeeded although the writer failed")
+	}
+}
+
+// failingWriter 的每次写入都失败。
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, io.ErrClosedPipe }
@@ -0,0 +1,222 @@
+package adb
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statFormat 是 stat -c 的输出格式：大小|修改时间（Unix 秒）|八进制权限|文件类型|路径。
+// 路径放在最后，其中包含 "|" 也不影响解析。
+const statFormat = "%s|%Y|%a|%F|%n"
+
+// FileInfo 描述设备上的一个文件。
+type FileInfo struct {
+	Path    string      // 完整路径
+	Size    int64       // 大小（字节）
+	Mode    os.FileMode // 权限位，目录带有 os.ModeDir
+	ModTime time.Time   // 最后修改时间
+	IsDir   bool        // 是否为目录
+}
+
+// Stat 获取设备上文件或目录的信息。
+//
+// 参数：
+//   - path: 设备上的路径
+//
+// 返回值：
+//   - FileInfo: 文件信息
+//   - error: 如果文件不存在（错误包装了 ErrNotFound）或无法读取，返回 error 对象
+//
+// 兼容性：
+//   - 需要 toybox 的 stat -c（Android 6.0+）
+//
+// 示例：
+//
+//	info, err := device.Stat("/sdcard/Download/report.pdf")
+//	if err == nil {
+//	    fmt.Println(info.Size, info.ModTime)
+//	}
+func (d *Device) Stat(path string) (FileInfo, error) {
+	output, err := d.Shellf("stat -c %s %s", statFormat, path)
+	if strings.Contains(output+errString(err), "No such file") {
+		return FileInfo{}, fmt.Errorf("stat %s: %w", path, ErrNotFound)
+	}
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return parseStat(output)
+}
+
+// FileExists 判断设备上的文件或目录是否存在。
+func (d *Device) FileExists(path string) (bool, error) {
+	r, err := d.ShellResult("test -e " + shellQuote(path))
+	if err != nil {
+		return false, err
+	}
+	switch r.ExitCode {
+	case 0:
+		return true, nil
+	case 1:
+		return false, nil
+	}
+	return false, fmt.Errorf("test -e %s failed with exit code %d: %s", path, r.ExitCode, strings.TrimSpace(r.Stderr))
+}
+
+// WaitForFile 轮询等待设备上的文件或目录出现，例如等待应用异步导出的报告。
+//
+// 参数：
+//   - devicePath: 设备上的路径
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - error: 超时返回 error 对象
+//
+// 注意事项：
+//   - 文件出现时可能还没写完，拉取前应使用 WaitForFileStable
+//
+// 示例：
+//
+//	device.ClickNode("android.widget.Button", "导出")
+//	if err := device.WaitForFile("/sdcard/Download/report.csv", 30*time.Second); err != nil {
+//	    log.Fatal(err)
+//	}
+func (d *Device) WaitForFile(devicePath string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if exists, err := d.FileExists(devicePath); err == nil && exists {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("wait for file %s timeout after %s", devicePath, timeout)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// WaitForFileStable 等待设备上的文件出现，并且大小和修改时间在 stableFor 时长内不再变化，
+// 避免拉取写了一半的文件（录屏、堆转储、导出的报告等）。
+//
+// 参数：
+//   - devicePath: 设备上的文件路径
+//   - stableFor: 文件保持不变的时长，例如 2*time.Second；应大于写入方两次写入之间的间隔
+//   - timeout: 最长等待时间（包括等待文件出现的时间）
+//
+// 返回值：
+//   - FileInfo: 稳定后的文件信息
+//   - error: 超时返回 error 对象，错误信息中包含最后一次观察到的大小
+//
+// 工作原理：
+//   - 每个轮询间隔执行一次 Stat，大小或修改时间变化时重新计时，连续 stableFor 没有变化即认为写完
+//
+// 示例：
+//
+//	info, err := device.WaitForFileStable("/sdcard/Download/report.csv", 2*time.Second, time.Minute)
+//	if err == nil {
+//	    device.Pull(info.Path, "artifacts/report.csv")
+//	}
+func (d *Device) WaitForFileStable(devicePath string, stableFor, timeout time.Duration) (FileInfo, error) {
+	deadline := time.Now().Add(timeout)
+	var last FileInfo
+	var since time.Time // last 第一次被观察到的时间，零值表示还没有观察到文件
+	for {
+		info, err := d.Stat(devicePath)
+		switch {
+		case err != nil:
+			since = time.Time{}
+		case since.IsZero() || info.Size != last.Size || !info.ModTime.Equal(last.ModTime):
+			last, since = info, time.Now()
+		case time.Since(since) >= stableFor:
+			return info, nil
+		}
+		if time.Now().After(deadline) {
+			if since.IsZero() {
+				return FileInfo{}, fmt.Errorf("wait for file %s timeout after %s: file not found", devicePath, timeout)
+			}
+			return FileInfo{}, fmt.Errorf("wait for file %s stable timeout after %s, last size: %d", devicePath, timeout, last.Size)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// modifiedSlack 是 find -newermt 预筛选时放宽的时间，用于容忍设备时区与本机不同，
+// 精确的过滤在本地完成。
+const modifiedSlack = 24 * time.Hour
+
+// FilesModifiedAfter 列出目录（包括子目录）中在指定时间之后修改过的文件，用于增量收集日志。
+//
+// 参数：
+//   - dir: 设备上的目录
+//   - since: 只返回修改时间晚于该时间的文件
+//
+// 返回值：
+//   - []FileInfo: 匹配的文件（不包括目录），按 find 的遍历顺序排列；没有匹配时返回空切片
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 工作原理：
+//  1. 'find <dir> -type f -newermt <时间>' 在设备上预筛选，并通过 -exec stat 一次取得所有文件信息
+//  2. find 不支持 -newermt 时改为列出所有文件
+//  3. 在本地按 ModTime 精确过滤
+//
+// 注意事项：
+//   - 没有读权限的子目录会被跳过
+//   - 文件名中不能包含换行符
+//
+// 示例：
+//
+//	last := time.Now()
+//	// ... 运行一段时间 ...
+//	files, err := device.FilesModifiedAfter("/sdcard/Android/data/com.example.app/files/logs", last)
+//	for _, f := range files {
+//	    device.Pull(f.Path, filepath.Join("artifacts", path.Base(f.Path)))
+//	}
+func (d *Device) FilesModifiedAfter(dir string, since time.Time) ([]FileInfo, error) {
+	pre := since.Add(-modifiedSlack).Format(time.DateTime)
+	output, err := d.Shellf("find %s -type f -newermt %s -exec stat -c %s {} +", dir, pre, statFormat)
+	if err != nil || strings.Contains(output, "newermt") {
+		// find 不支持 -newermt（或有无法读取的子目录），列出所有文件后在本地过滤
+		output, err = d.Shellf("find %s -type f -exec stat -c %s {} + 2>/dev/null; true", dir, statFormat)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files := []FileInfo{}
+	for _, line := range strings.Split(output, "\n") {
+		info, err := parseStat(line)
+		if err != nil {
+			continue
+		}
+		if info.ModTime.After(since) {
+			files = append(files, info)
+		}
+	}
+	return files, nil
+}
+
+// parseStat 解析一行 statFormat 格式的 stat 输出。
+func parseStat(line string) (FileInfo, error) {
+	fields := strings.SplitN(strings.TrimSpace(line), "|", 5)
+	if len(fields) != 5 {
+		return FileInfo{}, fmt.Errorf("unexpected stat output: %s", truncate(line, 200))
+	}
+	size, err1 := strconv.ParseInt(fields[0], 10, 64)
+	mtime, err2 := strconv.ParseInt(fields[1], 10, 64)
+	perm, err3 := strconv.ParseUint(fields[2], 8, 32)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return FileInfo{}, fmt.Errorf("unexpected stat output: %s", truncate(line, 200))
+	}
+	info := FileInfo{
+		Path:    fields[4],
+		Size:    size,
+		Mode:    os.FileMode(perm),
+		ModTime: time.Unix(mtime, 0),
+		IsDir:   fields[3] == "directory",
+	}
+	if info.IsDir {
+		info.Mode |= os.ModeDir
+	}
+	return info, nil
+}
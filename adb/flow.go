@@ -0,0 +1,133 @@
+package adb
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// defaultFlowStepTimeout 是 Flow 中 Tap / WaitFor / Type 步骤等待选择器出现的默认时间。
+const defaultFlowStepTimeout = 10 * time.Second
+
+// Flow 是按顺序执行的 UI 操作步骤列表，通过 Device.Flow 创建。
+// 步骤在第一个失败处停止，错误信息包含失败步骤的序号和描述，比一长串手写的操作更容易定位问题。
+type Flow struct {
+	d     *Device
+	steps []flowStep
+}
+
+// flowStep 是 Flow 中的一个步骤。
+type flowStep struct {
+	desc    string
+	sel     Selector
+	timeout time.Duration
+	action  func(node uixml.Node) error // 为 nil 时只要求节点存在
+}
+
+// Flow 创建一个空的步骤列表。
+//
+// 返回值：
+//   - *Flow: 步骤列表，通过链式调用添加步骤，最后调用 Run 执行
+//
+// 示例：
+//
+//	err := device.Flow().
+//	    Type(adb.Selector{ResourceID: "com.example:id/username"}, "alice").
+//	    Type(adb.Selector{ResourceID: "com.example:id/password"}, "secret").
+//	    Tap(adb.Selector{Text: "登录"}).
+//	    WaitFor(adb.Selector{ResourceID: "com.example:id/home"}).Timeout(30 * time.Second).
+//	    AssertVisible(adb.Selector{Text: "欢迎回来"}).
+//	    Run()
+//	if err != nil {
+//	    log.Fatal(err) // 例如：step 4 (wait for {ResourceID:com.example:id/home ...}): wait for selector timeout after 30s: ...
+//	}
+func (d *Device) Flow() *Flow {
+	return &Flow{d: d}
+}
+
+// Tap 添加一个步骤：等待选择器对应的节点出现并点击。
+func (f *Flow) Tap(sel Selector) *Flow {
+	return f.add(flowStep{desc: fmt.Sprintf("tap %+v", sel), sel: sel, timeout: defaultFlowStepTimeout, action: f.d.ClickNodeBy})
+}
+
+// WaitFor 添加一个步骤：等待选择器对应的节点出现。
+func (f *Flow) WaitFor(sel Selector) *Flow {
+	return f.add(flowStep{desc: fmt.Sprintf("wait for %+v", sel), sel: sel, timeout: defaultFlowStepTimeout})
+}
+
+// Type 添加一个步骤：等待输入框出现，点击后输入文本。
+// 步骤描述（以及失败时的错误信息）中只包含文本的字符数，不包含文本本身，避免密码等敏感内容出现在日志中。
+func (f *Flow) Type(sel Selector, text string) *Flow {
+	return f.add(flowStep{
+		desc:    fmt.Sprintf("type <%d chars> into %+v", utf8.RuneCountInString(text), sel),
+		sel:     sel,
+		timeout: defaultFlowStepTimeout,
+		action:  func(node uixml.Node) error { return f.d.typeInto(node, text) },
+	})
+}
+
+// AssertVisible 添加一个步骤：检查选择器对应的节点当前可见。
+// 默认只检查一次，需要等待时通过 Timeout 设置。
+func (f *Flow) AssertVisible(sel Selector) *Flow {
+	return f.add(flowStep{desc: fmt.Sprintf("assert visible %+v", sel), sel: sel})
+}
+
+// Timeout 设置上一个步骤等待节点出现的最长时间，没有步骤时无效。
+func (f *Flow) Timeout(timeout time.Duration) *Flow {
+	if len(f.steps) > 0 {
+		f.steps[len(f.steps)-1].timeout = timeout
+	}
+	return f
+}
+
+// add 追加一个步骤。
+func (f *Flow) add(step flowStep) *Flow {
+	f.steps = append(f.steps, step)
+	return f
+}
+
+// Run 按顺序执行所有步骤，遇到第一个失败的步骤时停止。
+//
+// 返回值：
+//   - error: 失败步骤的错误，格式为 "step N (描述): 原因"，N 从 1 开始；原因中保留了 ErrNotFound 等哨兵错误
+func (f *Flow) Run() error {
+	for i, step := range f.steps {
+		node, err := f.d.waitForSelector(step.sel, step.timeout)
+		if err == nil && step.action != nil {
+			err = step.action(node)
+		}
+		if err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, step.desc, err)
+		}
+	}
+	return nil
+}
+
+// DryRun 检查步骤列表而不执行任何操作，用于在真正运行前发现写错的选择器。
+//
+// 返回值：
+//   - error: 第一个有问题的步骤，格式与 Run 相同
+//
+// 检查内容：
+//   - 每个步骤的选择器至少设置了一个匹配字段，Index 不为负数
+//   - 第一个步骤的选择器能在当前界面上找到（后续步骤的界面要在执行前面的步骤后才出现，无法检查）
+func (f *Flow) DryRun() error {
+	for i, step := range f.steps {
+		s := step.sel
+		var err error
+		switch {
+		case s.ResourceID == "" && s.Text == "" && s.ContentDesc == "" && s.Class == "":
+			err = fmt.Errorf("empty selector")
+		case s.Index < 0:
+			err = fmt.Errorf("negative selector index %d", s.Index)
+		case i == 0:
+			_, err = f.d.waitForSelector(s, 0)
+		}
+		if err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, step.desc, err)
+		}
+	}
+	return nil
+}
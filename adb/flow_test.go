@@ -0,0 +1,20 @@
+package adb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlowTypeHidesText(t *testing.T) {
+	d, _ := newFakeDevice(func(command string) (string, error) { return hierarchy(), nil })
+	err := d.Flow().Type(Selector{ResourceID: "com.example:id/password"}, "hunter2").Timeout(0).Run()
+	if err == nil {
+		t.Fatal("Run succeeded without a matching node")
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("error leaks the typed text: %v", err)
+	}
+	if !strings.Contains(err.Error(), "type <7 chars> into") {
+		t.Errorf("error = %v, want the step described as type <7 chars>", err)
+	}
+}
@@ -0,0 +1,120 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Vibrate 让设备振动指定时长，用于验证触感反馈相关的代码路径（例如振动权限、勿扰模式下的行为）。
+//
+// 参数：
+//   - duration: 振动时长，必须大于 0，按毫秒取整
+//
+// 返回值：
+//   - error: 如果 duration 不大于 0，或设备没有振动器 / 当前 API 级别不支持（错误包装了 ErrUnsupported），
+//     返回 error 对象
+//
+// 兼容性：
+//   - Android 12+ 使用 'cmd vibrator_manager synced oneshot <毫秒>'
+//   - Android 8.0 - 11 使用 'cmd vibrator vibrate <毫秒>'
+//   - 更早的版本使用 'service call vibrator'，参数布局与 Android 5.0 - 7.1 的 IVibratorService 一致，
+//     部分厂商修改过该接口，调用失败时返回 ErrUnsupported
+//
+// 注意事项：
+//   - 系统设置中关闭了振动或处于勿扰模式时，命令成功但设备可能不振动
+//
+// 示例：
+//
+//	if err := device.Vibrate(500 * time.Millisecond); errors.Is(err, adb.ErrUnsupported) {
+//	    fmt.Println("设备不支持振动")
+//	}
+func (d *Device) Vibrate(duration time.Duration) error {
+	if duration <= 0 {
+		return fmt.Errorf("vibrate: duration must be positive, got %s", duration)
+	}
+	ms := max(duration.Milliseconds(), 1)
+
+	sdk, err := d.SDKLevel()
+	if err != nil {
+		return err
+	}
+
+	var output string
+	switch {
+	case sdk >= 31:
+		output, err = d.Cmd("vibrator_manager", "synced", "oneshot", fmt.Sprint(ms))
+	case sdk >= 26:
+		output, err = d.Cmd("vibrator", "vibrate", fmt.Sprint(ms))
+	default:
+		// vibrate(int uid, String opPkg, long milliseconds, int usageHint, IBinder token)
+		output, err = d.Shellf("service call vibrator 2 i32 2000 s16 com.android.shell i64 %d i32 0 i32 0", ms)
+		if err == nil && !strings.Contains(output, "Parcel(00000000") {
+			return fmt.Errorf("vibrate via service call on API %d: %s: %w", sdk, truncate(output, 200), ErrUnsupported)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	// 没有振动器或子命令不存在时 cmd 仍以 0 退出，只打印错误信息
+	if strings.Contains(output, "Error") || strings.Contains(output, "error:") ||
+		strings.Contains(output, "No vibrator") || strings.Contains(output, "Unknown command") {
+		return fmt.Errorf("vibrate on API %d: %s: %w", sdk, truncate(output, 200), ErrUnsupported)
+	}
+	return nil
+}
+
+// Flashlight 打开或关闭闪光灯（手电筒），用于测试与相机、手电筒状态相关的逻辑。
+//
+// 参数：
+//   - on: true 表示打开，false 表示关闭
+//
+// 返回值：
+//   - error: 没有 root 时错误包装了 ErrRootRequired；找不到闪光灯设备时错误包装了 ErrUnsupported
+//
+// 工作原理：
+//   - Android 没有控制手电筒的 shell 命令，该方法直接写入 /sys/class/leds 下名称包含 flash 或 torch 的 LED：
+//     打开时写入 max_brightness，关闭时写入 0
+//
+// 注意事项：
+//   - 需要 root（参见 Root）
+//   - 绕过了系统的 CameraService，系统快捷设置中的手电筒图标不会同步变化
+//   - 模拟器没有闪光灯，总是返回 ErrUnsupported
+//
+// 示例：
+//
+//	device.Root()
+//	if err := device.Flashlight(true); err == nil {
+//	    defer device.Flashlight(false)
+//	}
+func (d *Device) Flashlight(on bool) error {
+	if root, err := d.IsRoot(); err != nil {
+		return err
+	} else if !root {
+		return fmt.Errorf("flashlight: %w", ErrRootRequired)
+	}
+
+	// 没有匹配时 ls 以非 0 退出，只看输出
+	output, _ := d.Shell("ls -d /sys/class/leds/*flash* /sys/class/leds/*torch* 2>/dev/null")
+	leds := strings.Fields(output)
+	if len(leds) == 0 {
+		return fmt.Errorf("flashlight: no flash led in /sys/class/leds: %w", ErrUnsupported)
+	}
+
+	var cmds []string
+	for _, led := range leds {
+		value := "0"
+		if on {
+			value = "$(cat " + shellQuote(led+"/max_brightness") + ")"
+		}
+		cmds = append(cmds, fmt.Sprintf("echo %s > %s", value, shellQuote(led+"/brightness")))
+	}
+	output, err := d.Shell(strings.Join(cmds, "; "))
+	if err != nil {
+		return fmt.Errorf("flashlight: %w", err)
+	}
+	if output != "" {
+		return fmt.Errorf("flashlight: %s", output)
+	}
+	return nil
+}
@@ -0,0 +1,349 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 设备就绪检查的阈值。
+const (
+	minFreeStorage = 100 << 20 // /data 至少保留 100 MB 可用空间
+	minBattery     = 5         // 电量低于 5% 时设备随时可能关机
+)
+
+// Health 是 HealthCheck 的检查结果，每一项对应一个检查。
+type Health struct {
+	State         string // adb get-state 的结果，正常为 "device"
+	Responsive    bool   // 能否执行 shell 命令（Ping）
+	BootCompleted bool   // 系统是否启动完成（sys.boot_completed）
+	ScreenOn      bool   // 屏幕是否点亮
+	Unlocked      bool   // 是否已解锁（没有显示锁屏）
+	FreeStorage   int64  // /data 可用空间（字节），未知时为 -1
+	BatteryLevel  int    // 电量百分比，未知时为 -1
+}
+
+// Ready 判断设备是否可以开始测试。
+func (h Health) Ready() bool {
+	return h.Reason() == ""
+}
+
+// Reason 返回第一项未通过的检查及原因，设备就绪时返回空字符串。
+func (h Health) Reason() string {
+	switch {
+	case h.State != "device":
+		return fmt.Sprintf("device state is %q", h.State)
+	case !h.Responsive:
+		return "shell not responsive"
+	case !h.BootCompleted:
+		return "boot not completed"
+	case !h.ScreenOn:
+		return "screen is off"
+	case !h.Unlocked:
+		return "screen is locked"
+	case h.FreeStorage >= 0 && h.FreeStorage < minFreeStorage:
+		return fmt.Sprintf("low storage: %d bytes free on /data", h.FreeStorage)
+	case h.BatteryLevel >= 0 && h.BatteryLevel < minBattery:
+		return fmt.Sprintf("low battery: %d%%", h.BatteryLevel)
+	}
+	return ""
+}
+
+// HealthCheck 检查设备是否真正可用，适合在测试开始前调用一次。
+//
+// 返回值：
+//   - Health: 各项检查的结果，即使设备未就绪也会尽量填充
+//   - error: 设备未就绪时返回 error 对象，错误信息为 Health.Reason 给出的第一个失败原因
+//
+// 检查项：
+//  1. 连接状态（adb get-state 为 device，而不是 offline / unauthorized）
+//  2. shell 可以响应（Ping）
+//  3. 系统启动完成（sys.boot_completed 为 1）
+//  4. 屏幕点亮且没有显示锁屏
+//  5. /data 可用空间不少于 100 MB
+//  6. 电量不低于 5%
+//
+// 注意事项：
+//   - 连接状态或 shell 检查失败时不再执行后面的检查
+//   - 可用空间和电量读取失败时记为 -1，不影响 Ready 的判断
+//
+// 示例：
+//
+//	health, err := device.HealthCheck()
+//	if err != nil {
+//	    log.Fatalf("设备 %s 不可用: %v", device.Serial, err)
+//	}
+//	fmt.Printf("电量 %d%%, 可用空间 %d MB\n", health.BatteryLevel, health.FreeStorage>>20)
+func (d *Device) HealthCheck() (Health, error) {
+	h := Health{FreeStorage: -1, BatteryLevel: -1}
+
+	state, err := d.execCommand("get-state")
+	if err != nil {
+		h.State = deviceStateFromError(err)
+		return h, fmt.Errorf("device not ready: %s", h.Reason())
+	}
+	h.State = state
+
+	h.Responsive = d.Ping() == nil
+	if !h.Responsive {
+		return h, fmt.Errorf("device not ready: %s", h.Reason())
+	}
+
+	boot, _ := d.GetProp("sys.boot_completed")
+	h.BootCompleted = boot == "1"
+	h.ScreenOn, _ = d.IsScreenOn()
+	locked, err := d.IsLocked()
+	h.Unlocked = err == nil && !locked
+	if usage, err := d.DiskUsage("/data"); err == nil {
+		h.FreeStorage = usage.Available
+	}
+	if level, err := d.BatteryLevel(); err == nil {
+		h.BatteryLevel = level
+	}
+
+	if reason := h.Reason(); reason != "" {
+		return h, fmt.Errorf("device not ready: %s", reason)
+	}
+	return h, nil
+}
+
+// deviceStateFromError 从 adb get-state 的错误信息中提取设备状态。
+func deviceStateFromError(err error) string {
+	msg := err.Error()
+	for _, state := range []string{"unauthorized", "offline", "not found", "no devices"} {
+		if strings.Contains(msg, state) {
+			return state
+		}
+	}
+	return "unknown"
+}
+
+// batteryLevelRe 匹配 'dumpsys battery' 输出中的电量，例如 "  level: 85"
+var batteryLevelRe = regexp.MustCompile(`(?m)^\s*level:\s*(\d+)`)
+
+// BatteryLevel 返回设备当前的电量百分比。
+//
+// 返回值：
+//   - int: 电量，取值 0-100
+//   - error: 如果命令执行失败或输出无法解析，返回 error 对象
+//
+// 示例：
+//
+//	level, err := device.BatteryLevel()
+//	if err == nil && level < 20 {
+//	    fmt.Println("电量不足:", level)
+//	}
+func (d *Device) BatteryLevel() (int, error) {
+	output, err := d.Shell("dumpsys battery")
+	if err != nil {
+		return 0, err
+	}
+	m := batteryLevelRe.FindStringSubmatch(output)
+	if m == nil {
+		return 0, fmt.Errorf("unexpected battery output: %s", truncate(output, 200))
+	}
+	return strconv.Atoi(m[1])
+}
+
+// IsScreenOn 判断屏幕是否点亮。
+//
+// 返回值：
+//   - bool: 屏幕点亮时返回 true（屏保 / 息屏显示不算点亮）
+//   - error: 如果命令执行失败或输出无法识别，返回 error 对象
+//
+// 兼容性：
+//   - Android 5.0+ 读取 'dumpsys power' 中的 mWakefulness
+//   - 更早的版本读取 mScreenOn
+//
+// 示例：
+//
+//	if on, _ := device.IsScreenOn(); !on {
+//	    device.Shell("input keyevent KEYCODE_WAKEUP")
+//	}
+func (d *Device) IsScreenOn() (bool, error) {
+	output, err := d.Shell("dumpsys power")
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case strings.Contains(output, "mWakefulness=Awake"):
+		return true, nil
+	case strings.Contains(output, "mWakefulness="):
+		return false, nil
+	case strings.Contains(output, "mScreenOn=true"):
+		return true, nil
+	case strings.Contains(output, "mScreenOn=false"):
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected power output: %s", truncate(output, 200))
+}
+
+// WaitForScreenState 轮询等待屏幕进入指定的亮灭状态，用于验证无操作超时后息屏、通知点亮屏幕等行为。
+//
+// 参数：
+//   - on: true 表示等待屏幕点亮，false 表示等待屏幕熄灭
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - error: 超时返回 error 对象，错误信息中包含最后一次观察到的状态
+//
+// 注意事项：
+//   - 每次检查执行一次 'dumpsys power'，间隔见 WithPollInterval（默认 500 毫秒）
+//   - 轮询期间命令失败会继续等待，直到超时
+//
+// 示例：
+//
+//	// 息屏时间设为 15 秒后，确认屏幕会自动熄灭
+//	device.PutSetting("system", "screen_off_timeout", "15000")
+//	if err := device.WaitForScreenState(false, 30*time.Second); err != nil {
+//	    log.Fatal(err) // 例如：wait for screen off timeout after 30s, last state: on
+//	}
+func (d *Device) WaitForScreenState(on bool, timeout time.Duration) error {
+	names := map[bool]string{true: "on", false: "off"}
+	deadline := time.Now().Add(timeout)
+	last := "unknown"
+	for {
+		state, err := d.IsScreenOn()
+		if err == nil {
+			if state == on {
+				return nil
+			}
+			last = names[state]
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("wait for screen %s timeout after %s, last state: %s", names[on], timeout, last)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// IsLocked 判断设备是否正在显示锁屏界面（keyguard）。
+// 开机完成后锁屏仍会挡住所有界面操作，自动化开始前应确认设备已解锁。
+//
+// 返回值：
+//   - bool: 正在显示锁屏时返回 true
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 兼容性：
+//   - Android 8.0+ 读取 'dumpsys window policy' 中 KeyguardServiceDelegate 段的 "showing=true"
+//   - 更早的版本读取 mShowingLockscreen、mDreamingLockscreen 或 isStatusBarKeyguard
+//
+// 示例：
+//
+//	if locked, _ := device.IsLocked(); locked {
+//	    device.Unlock()
+//	}
+func (d *Device) IsLocked() (bool, error) {
+	output, err := d.Shell("dumpsys window policy")
+	if err != nil {
+		return false, err
+	}
+	return keyguardShowing(output), nil
+}
+
+// keyguardShowing 根据 'dumpsys window policy' 的输出判断是否正在显示锁屏。
+func keyguardShowing(output string) bool {
+	for _, s := range []string{"showing=true", "mShowingLockscreen=true", "mDreamingLockscreen=true", "isStatusBarKeyguard=true"} {
+		if strings.Contains(output, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyguardSecure 根据 'dumpsys window policy' 的输出判断锁屏是否设置了 PIN、图案或密码。
+// Android 8.0+ 输出 "secure=true"，更早的版本输出 "mKeyguardSecure=true"。
+func keyguardSecure(output string) bool {
+	return strings.Contains(output, " secure=true") || strings.Contains(output, "mKeyguardSecure=true")
+}
+
+// WaitForUnlocked 轮询等待锁屏消失，例如等待人工解锁或其他工具解锁设备。
+//
+// 参数：
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - error: 超时返回 error 对象
+//
+// 示例：
+//
+//	if err := device.WaitForUnlocked(time.Minute); err != nil {
+//	    log.Fatal("请手动解锁设备:", err)
+//	}
+func (d *Device) WaitForUnlocked(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if locked, err := d.IsLocked(); err == nil && !locked {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("wait for unlocked timeout after %s", timeout)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// unlockTimeout 是 Unlock 发出解锁操作后等待锁屏消失的最长时间。
+const unlockTimeout = 3 * time.Second
+
+// Unlock 点亮屏幕并解除没有设置密码的锁屏（滑动解锁或无锁屏）。
+//
+// 返回值：
+//   - error: 如果锁屏设置了 PIN、图案或密码（错误包装了 ErrUnsupported），
+//     或解锁操作后锁屏仍未消失，返回 error 对象
+//
+// 工作原理：
+//  1. 屏幕熄灭时发送 KEYCODE_WAKEUP 点亮屏幕
+//  2. 已经没有锁屏时直接返回
+//  3. 从屏幕（当前方向）下方向上滑动解除锁屏；Android 6.0+ 再执行 'wm dismiss-keyguard' 作为补充
+//  4. 最多等待 3 秒确认锁屏消失
+//
+// 注意事项：
+//   - 带密码的锁屏无法在不知道密码的情况下解除，测试设备应在系统设置中把屏幕锁定设为"无"或"滑动"
+//
+// 示例：
+//
+//	if err := device.Unlock(); errors.Is(err, adb.ErrUnsupported) {
+//	    log.Fatal("设备设置了锁屏密码，请先在设置中关闭")
+//	}
+func (d *Device) Unlock() error {
+	if on, err := d.IsScreenOn(); err == nil && !on {
+		if err := d.SendKeys(KeyWakeup); err != nil {
+			return err
+		}
+		time.Sleep(d.pollEvery())
+	}
+
+	output, err := d.Shell("dumpsys window policy")
+	if err != nil {
+		return err
+	}
+	if !keyguardShowing(output) {
+		return nil
+	}
+	if keyguardSecure(output) {
+		return fmt.Errorf("keyguard is secured with a PIN, pattern or password: %w", ErrUnsupported)
+	}
+
+	// 滑动坐标不经旋转转换，横屏锁屏时需要当前方向的尺寸
+	w, h, err := d.orientedScreenSize()
+	if err != nil {
+		return err
+	}
+	if err := d.swipe(w/2, h*4/5, w/2, h/5, 300); err != nil {
+		return err
+	}
+	if sdk, err := d.SDKLevel(); err == nil && sdk >= 23 {
+		if locked, err := d.IsLocked(); err == nil && locked {
+			if _, err := d.Shell("wm dismiss-keyguard"); err != nil {
+				return fmt.Errorf("unlock: wm dismiss-keyguard: %w", err)
+			}
+		}
+	}
+	if err := d.WaitForUnlocked(unlockTimeout); err != nil {
+		return fmt.Errorf("unlock: keyguard still showing after swipe: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package adb
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeLockedDevice 返回一台横屏、显示滑动锁屏的设备，swipe 之后锁屏消失。
+// dismiss 为 'wm dismiss-keyguard' 的结果。
+func fakeLockedDevice(dismiss error) (*Device, *fakeRunner) {
+	unlocked := false
+	return newFakeDevice(func(command string) (string, error) {
+		switch {
+		case command == "dumpsys power":
+			return "mWakefulness=Awake", nil
+		case command == "dumpsys window policy":
+			if unlocked {
+				return "showing=false", nil
+			}
+			return "showing=true secure=false", nil
+		case command == "wm size":
+			return "Physical size: 1080x2400", nil
+		case command == dumpCommand:
+			return strings.Replace(hierarchy(), `rotation="0"`, `rotation="1"`, 1), nil
+		case command == "getprop":
+			return "[ro.build.version.sdk]: [34]", nil
+		case strings.HasPrefix(command, "input swipe"):
+			unlocked = dismiss == nil
+		case command == "wm dismiss-keyguard":
+			return "", dismiss
+		}
+		return "", nil
+	})
+}
+
+func TestUnlockSwipesInCurrentOrientation(t *testing.T) {
+	d, r := fakeLockedDevice(nil)
+	if err := d.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	// 横屏时当前方向的尺寸为 2400x1080
+	if cmds := r.shellCommands(); !slices.Contains(cmds, "input swipe 1200 864 1200 216 300") {
+		t.Errorf("commands = %q, want a swipe in landscape coordinates", cmds)
+	}
+}
+
+func TestUnlockReportsDismissError(t *testing.T) {
+	dismissErr := errors.New("dismiss failed")
+	d, _ := fakeLockedDevice(dismissErr)
+	if err := d.Unlock(); !errors.Is(err, dismissErr) {
+		t.Errorf("Unlock() = %v, want wrapped dismiss error", err)
+	}
+}
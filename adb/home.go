@@ -0,0 +1,122 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// homeAttempts 是 ResetToHome 最多按 Home 键的次数。
+const homeAttempts = 3
+
+// knownLaunchers 是常见的桌面应用包名，无法解析默认桌面时用于判断是否已回到桌面。
+var knownLaunchers = []string{
+	"com.google.android.apps.nexuslauncher",
+	"com.android.launcher3",
+	"com.android.launcher",
+	"com.miui.home",
+	"com.huawei.android.launcher",
+	"com.sec.android.app.launcher",
+	"com.oppo.launcher",
+	"com.bbk.launcher2",
+	"com.hihonor.android.launcher",
+	"com.teslacoilsw.launcher",
+}
+
+// HomeOptions 是 ResetToHomeWith 的可选步骤。
+type HomeOptions struct {
+	CollapseNotifications bool // 回到桌面前收起通知栏
+	HideKeyboard          bool // 回到桌面后收起软键盘
+}
+
+// ResetToHome 将设备恢复到确定的初始状态：收起通知栏，回到桌面并确认桌面在前台，收起软键盘。
+// 适合作为每个测试用例的准备步骤。
+//
+// 返回值：
+//   - error: 如果按 3 次 Home 键后桌面仍未进入前台，返回 error 对象（包含当前前台的包名）
+//
+// 示例：
+//
+//	func setup(t *testing.T, device *adb.Device) {
+//	    if err := device.ResetToHome(); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+func (d *Device) ResetToHome() error {
+	return d.ResetToHomeWith(HomeOptions{CollapseNotifications: true, HideKeyboard: true})
+}
+
+// ResetToHomeWith 与 ResetToHome 相同，可以选择是否收起通知栏和软键盘。
+//
+// 参数：
+//   - opts: 可选步骤，零值只按 Home 键并确认回到桌面
+//
+// 返回值：
+//   - error: 如果桌面没有进入前台或可选步骤失败，返回 error 对象
+//
+// 工作原理：
+//  1. 根据 opts 收起通知栏
+//  2. 按 Home 键，等待后检查前台应用是否为桌面；部分设备第一次按 Home 只会关闭弹窗或回到桌面首屏之外的页面，
+//     因此最多重试 3 次
+//  3. 根据 opts 收起软键盘（例如桌面搜索框弹出的键盘）
+//
+// 注意事项：
+//   - 桌面包名优先通过 'cmd package resolve-activity' 解析默认 HOME 应用（Android 7.0+），
+//     没有设置默认桌面或更早的版本时与常见桌面包名列表比较
+//
+// 示例：
+//
+//	err := device.ResetToHomeWith(adb.HomeOptions{CollapseNotifications: true})
+func (d *Device) ResetToHomeWith(opts HomeOptions) error {
+	if opts.CollapseNotifications {
+		if err := d.CollapseNotifications(); err != nil {
+			return err
+		}
+	}
+
+	launchers := knownLaunchers
+	if home := d.homePackage(); home != "" {
+		launchers = []string{home}
+	}
+
+	var current string
+	for i := 0; i < homeAttempts; i++ {
+		if err := d.PressHome(); err != nil {
+			return err
+		}
+		time.Sleep(d.pollEvery())
+		pkg, err := d.CurrentPackage()
+		if err != nil {
+			continue
+		}
+		current = pkg
+		for _, l := range launchers {
+			if pkg == l {
+				if opts.HideKeyboard {
+					return d.HideKeyboard()
+				}
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("launcher not in foreground after %d home presses (current %q)", homeAttempts, current)
+}
+
+// homePackage 返回默认桌面应用的包名，无法解析时返回空字符串。
+func (d *Device) homePackage() string {
+	if !d.useCmd() {
+		return ""
+	}
+	output, err := d.Cmd("package", "resolve-activity", "--brief",
+		"-a", "android.intent.action.MAIN", "-c", "android.intent.category.HOME")
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(output, "\n")
+	pkg, _, ok := strings.Cut(strings.TrimSpace(lines[len(lines)-1]), "/")
+	// 没有设置默认桌面时解析结果为系统的选择器 "android/com.android.internal.app.ResolverActivity"
+	if !ok || pkg == "android" {
+		return ""
+	}
+	return pkg
+}
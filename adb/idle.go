@@ -0,0 +1,125 @@
+package adb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// UIHash 返回当前界面的指纹，用于低成本地判断界面是否发生了变化。
+// 使用 'uiautomator dump --compressed' 获取精简的 UI 结构并计算 SHA-256，不解析 XML。
+//
+// 返回值：
+//   - string: 十六进制的哈希值，界面不变时保持相同
+//   - error: 如果 dump 失败，返回 error 对象
+//
+// 注意事项：
+//   - --compressed 会省略没有文本、不可交互的布局节点，因此指纹是粗粒度的：
+//     只改变这些节点的变化（例如纯装饰的视图移动）检测不到
+//   - 仍需要一次 dump，节省的是 XML 解析和节点遍历的开销，适合在轮询中先判断是否变化，
+//     变化后再调用 XML / FindNode
+//   - 界面上的时钟、进度条等持续变化的内容会让指纹一直变化
+//
+// 示例：
+//
+//	before, _ := device.UIHash()
+//	device.PressBack()
+//	after, _ := device.UIHash()
+//	if before == after {
+//	    fmt.Println("返回键没有改变界面")
+//	}
+func (d *Device) UIHash() (string, error) {
+	output, err := d.execCommand("exec-out", "uiautomator dump --compressed /dev/tty")
+	if err != nil {
+		return "", err
+	}
+	// 去掉末尾的 "UI hierchary dumped to: /dev/tty" 提示
+	i := strings.LastIndex(output, "</hierarchy>")
+	if i < 0 {
+		return "", fmt.Errorf("unexpected uiautomator output: %s", truncate(output, 200))
+	}
+	return dumpHash(output[:i]), nil
+}
+
+// dumpHash 返回 dump 文本的 SHA-256 指纹。
+func dumpHash(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// dumpPoller 在轮询中复用上一次的解析结果：dump 的指纹没有变化时不再解析 XML，也不必重新查找节点。
+type dumpPoller struct {
+	d    *Device
+	hash string
+	xml  *uixml.Xml
+}
+
+// next 获取一次 dump。changed 为 false 表示界面与上一次完全相同，返回的是上一次的解析结果。
+func (p *dumpPoller) next() (xml *uixml.Xml, changed bool, err error) {
+	data, err := p.d.UiautomatorDump()
+	if err != nil {
+		return nil, false, err
+	}
+	hash := dumpHash(data)
+	if p.xml != nil && hash == p.hash {
+		return p.xml, false, nil
+	}
+	if xml, err = uixml.NewXml(data); err != nil {
+		return nil, false, err
+	}
+	p.hash, p.xml = hash, xml
+	return xml, true, nil
+}
+
+// WaitForIdle 等待界面停止变化，即连续 quiet 时间内 UIHash 保持不变。
+// 用于页面切换、列表加载、动画结束之后再查找元素，避免在界面变化过程中 dump 到中间状态。
+//
+// 参数：
+//   - quiet: 界面需要保持不变的时长，例如 1 秒
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - error: 超时时界面仍在变化，返回 error 对象
+//
+// 注意事项：
+//   - 每隔轮询间隔（默认 500 毫秒）获取一次 UIHash，只比较指纹，不解析 XML
+//   - 界面上有持续变化的内容（时钟、倒计时、轮播图）时会一直等到超时
+//
+// 示例：
+//
+//	device.ClickButton("下一步")
+//	if err := device.WaitForIdle(time.Second, 10*time.Second); err != nil {
+//	    log.Println(err)
+//	}
+//	device.ClickButton("完成")
+func (d *Device) WaitForIdle(quiet, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	last, since := "", time.Now()
+	for {
+		hash, err := d.UIHash()
+		if err == nil {
+			if hash != last {
+				last, since = hash, time.Now()
+			} else if time.Since(since) >= quiet {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("wait for idle: ui still changing after %s", timeout)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// find 获取一次 dump 并查找第一个匹配的节点，界面没有变化时在上一次的解析结果中查找。
+func (p *dumpPoller) find(fn FindNodeFunc) (uixml.Node, error) {
+	xml, _, err := p.next()
+	if err != nil {
+		return uixml.Node{}, err
+	}
+	return xml.Find(fn)
+}
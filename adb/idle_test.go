@@ -0,0 +1,87 @@
+package adb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+func TestDumpPollerSkipsUnchangedDump(t *testing.T) {
+	dumps := []string{
+		hierarchy(`<node text="a" bounds="[0,0][10,10]" />`),
+		hierarchy(`<node text="a" bounds="[0,0][10,10]" />`),
+		hierarchy(`<node text="b" bounds="[0,0][10,10]" />`),
+	}
+	i := 0
+	d, _ := newFakeDevice(func(command string) (string, error) {
+		dump := dumps[min(i, len(dumps)-1)]
+		i++
+		return dump, nil
+	})
+
+	poll := dumpPoller{d: d}
+	first, changed, err := poll.next()
+	if err != nil || !changed {
+		t.Fatalf("first next() = changed %v, err %v; want changed", changed, err)
+	}
+	second, changed, err := poll.next()
+	if err != nil || changed || second != first {
+		t.Fatalf("second next() = changed %v, err %v, reused %v; want cached result", changed, err, second == first)
+	}
+	third, changed, err := poll.next()
+	if err != nil || !changed || third.Nodes[0].Text != "b" {
+		t.Fatalf("third next() = changed %v, err %v; want new dump", changed, err)
+	}
+}
+
+func TestWaitForElementCountPolls(t *testing.T) {
+	item := `<node class="android.widget.TextView" bounds="[0,0][10,10]" />`
+	calls := 0
+	d, _ := newFakeDevice(func(command string) (string, error) {
+		calls++
+		if calls < 4 {
+			return hierarchy(item), nil
+		}
+		return hierarchy(item, item), nil
+	})
+	nodes, err := d.WaitForElementCount(func(n, pn uixml.Node) bool { return n.Class == "android.widget.TextView" }, 2, EqualTo, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 || calls != 4 {
+		t.Errorf("got %d nodes after %d dumps, want 2 nodes after 4 dumps", len(nodes), calls)
+	}
+}
+
+// benchmarkDump 构造一份包含 n 个节点的 dump，大小接近真实应用的界面。
+func benchmarkDump(n int) string {
+	nodes := make([]string, n)
+	for i := range nodes {
+		nodes[i] = fmt.Sprintf(`<node index="%d" text="item %d" resource-id="com.example:id/title" class="android.widget.TextView" package="com.example" content-desc="" checkable="false" checked="false" clickable="true" enabled="true" focusable="true" focused="false" scrollable="false" long-clickable="false" password="false" selected="false" bounds="[0,%d][1080,%d]" />`, i, i, i*100, i*100+100)
+	}
+	return hierarchy(nodes...)
+}
+
+// BenchmarkPollUnchanged 比较界面没有变化时每次轮询的开销：只计算指纹与完整解析后查找。
+func BenchmarkPollUnchanged(b *testing.B) {
+	dump := benchmarkDump(300)
+	fn := func(n, pn uixml.Node) bool { return n.Text == "item 299" }
+	b.Run("hash", func(b *testing.B) {
+		b.SetBytes(int64(len(dump)))
+		for i := 0; i < b.N; i++ {
+			dumpHash(dump)
+		}
+	})
+	b.Run("parse", func(b *testing.B) {
+		b.SetBytes(int64(len(dump)))
+		for i := 0; i < b.N; i++ {
+			xml, err := uixml.NewXml(dump)
+			if err != nil {
+				b.Fatal(err)
+			}
+			xml.Find(fn)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// imeShownRe 匹配 'dumpsys input_method' 中表示软键盘可见状态的字段。
+// 不同 Android 版本的字段名不同：
+//   - mInputShown=true（大多数版本）
+//   - mIsInputViewShown=true / isInputViewShown=true（部分版本的 InputMethodService 段落）
+var imeShownRe = regexp.MustCompile(`\b(?:mInputShown|mIsInputViewShown|isInputViewShown)=(true|false)`)
+
+// IsKeyboardShown 判断软键盘当前是否显示。
+//
+// 返回值：
+//   - bool: 软键盘显示时返回 true
+//   - error: 如果命令执行失败或输出中找不到已知字段，返回 error 对象
+//
+// 注意事项：
+//   - 任意一个已知字段为 true 即认为键盘可见
+//
+// 示例：
+//
+//	shown, err := device.IsKeyboardShown()
+//	if err == nil && shown {
+//	    fmt.Println("键盘正在显示")
+//	}
+func (d *Device) IsKeyboardShown() (bool, error) {
+	output, err := d.Shell("dumpsys input_method")
+	if err != nil {
+		return false, err
+	}
+	matches := imeShownRe.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return false, fmt.Errorf("keyboard state not found in dumpsys input_method")
+	}
+	for _, m := range matches {
+		if m[1] == "true" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// HideKeyboard 收起软键盘。
+// 输入完成后软键盘经常会遮挡提交按钮，导致点击落在键盘上，调用该方法可以避免这个问题。
+//
+// 返回值：
+//   - error: 如果收起失败（尝试后键盘依然可见），返回 error 对象；键盘本就未显示时返回 nil
+//
+// 工作原理：
+//  1. 检查键盘是否显示，未显示则直接返回
+//  2. 发送 ESCAPE 键（111），大多数输入法会收起键盘且不影响页面
+//  3. 如果键盘仍在显示，再发送返回键
+//  4. 再次确认键盘已收起
+//
+// 注意事项：
+//   - 返回键在键盘已收起的情况下会返回上一页，因此只在 ESCAPE 无效时才使用
+//
+// 示例：
+//
+//	device.Input("password123")
+//	if err := device.HideKeyboard(); err != nil {
+//	    log.Println("收起键盘失败:", err)
+//	}
+//	device.ClickNode("", "登录")
+func (d *Device) HideKeyboard() error {
+	shown, err := d.IsKeyboardShown()
+	if err != nil || !shown {
+		return err
+	}
+
+	for _, key := range []int{111, 4} { // KEYCODE_ESCAPE, KEYCODE_BACK
+		if err := d.KeyEvent(key); err != nil {
+			return err
+		}
+		// 等待键盘收起动画结束
+		time.Sleep(300 * time.Millisecond)
+		if shown, err = d.IsKeyboardShown(); err != nil || !shown {
+			return err
+		}
+	}
+	return fmt.Errorf("keyboard still shown")
+}
+
+// SetIME 切换当前输入法，例如切换到 ADB Keyboard 以支持 Unicode 输入。
+//
+// 参数：
+//   - id: 输入法 ID，例如 "com.android.adbkeyboard/.AdbIME"，可通过 'ime list -s' 查看
+//
+// 返回值：
+//   - error: 如果输入法不存在或切换失败，返回 error 对象
+//
+// 注意事项：
+//   - 会先执行 'ime enable' 启用该输入法，再通过 'ime set' 切换
+//   - 切换成功后会记录原来的输入法，调用 Cleanup 时恢复
+//
+// 示例：
+//
+//	if err := device.SetIME("com.android.adbkeyboard/.AdbIME"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer device.Cleanup()
+func (d *Device) SetIME(id string) error {
+	prev, err := d.GetSetting("secure", "default_input_method")
+	if err != nil {
+		return err
+	}
+	output, err := d.Shellf("ime enable %s && ime set %s", id, id)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(output, "Unknown") || strings.Contains(output, "Error") {
+		return fmt.Errorf("set ime %s failed: %s", id, output)
+	}
+	if prev != "" && prev != id {
+		d.pushUndo("input method", func() error {
+			_, err := d.Shellf("ime set %s", prev)
+			return err
+		})
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package adb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InputEvent 是一条 Linux 输入事件，对应 getevent 输出中的一行。
+type InputEvent struct {
+	Time   time.Duration // 事件时间戳（开机以来的时间）
+	Device string        // 输入设备节点，例如 /dev/input/event2
+	Type   string        // 事件类型名称，例如 EV_ABS；没有名称时为十六进制数字，例如 "0003"
+	Code   string        // 事件代码名称，例如 ABS_MT_POSITION_X、BTN_TOUCH、SYN_REPORT
+	Value  int32         // 事件值；按键事件 DOWN 为 1、UP 为 0、REPEAT 为 2
+}
+
+// geteventRe 匹配 'getevent -lt' 的事件行，例如：
+// "[   12345.678901] /dev/input/event2: EV_ABS       ABS_MT_POSITION_X    000001f4"
+// 只读取一个设备（'getevent -lt /dev/input/event2'）时行中没有设备节点：
+// "[   12345.678901] EV_ABS       ABS_MT_POSITION_X    000001f4"
+var geteventRe = regexp.MustCompile(`^\[\s*(\d+)\.(\d+)\]\s+(?:(/dev/input/\S+):\s+)?(\S+)\s+(\S+)\s+(\S+)`)
+
+// GetEvent 实时读取设备的原始输入事件（'getevent -lt'），用于分析真实的触摸手势或录制精确的回放数据。
+//
+// 参数：
+//   - ctx: 控制读取时长，ctx 取消后结束 getevent 进程
+//   - devices: 只返回这些输入设备节点的事件，例如 "/dev/input/event2"；不传时返回所有设备的事件
+//
+// 返回值：
+//   - <-chan InputEvent: 事件通道，getevent 进程结束（包括 ctx 取消、设备断开）后关闭
+//   - error: 如果无法启动 adb 进程，返回 error 对象
+//
+// 注意事项：
+//   - 调用方需要持续读取通道，否则 getevent 的输出会被阻塞
+//   - 类型和代码使用 getevent -l 的符号名称，内核没有对应名称时为十六进制数字
+//   - 不经过 Runner，也不应用 WithTimeout 设置的超时
+//
+// 示例：
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	events, err := device.GetEvent(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for ev := range events {
+//	    fmt.Println(ev.Device, ev.Type, ev.Code, ev.Value)
+//	}
+func (d *Device) GetEvent(ctx context.Context, devices ...string) (<-chan InputEvent, error) {
+	// 只有一个设备时由 getevent 过滤，输出中不再包含设备节点
+	args := []string{"shell", "getevent", "-lt"}
+	device := ""
+	if len(devices) == 1 {
+		device = devices[0]
+		args = append(args, shellQuote(device))
+	}
+	cmd := d.commandContext(ctx, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	wanted := map[string]bool{}
+	for _, dev := range devices {
+		wanted[dev] = true
+	}
+
+	events := make(chan InputEvent)
+	go func() {
+		defer close(events)
+		defer cmd.Wait()
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			ev, ok := parseGetevent(scanner.Text(), device)
+			if !ok || (len(wanted) > 0 && !wanted[ev.Device]) {
+				continue
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// parseGetevent 解析 'getevent -lt' 的一行输出，不是事件行时返回 false。
+// 行中没有设备节点时（只读取一个设备），Device 字段使用 device。
+func parseGetevent(line, device string) (InputEvent, bool) {
+	m := geteventRe.FindStringSubmatch(line)
+	if m == nil {
+		return InputEvent{}, false
+	}
+	sec, _ := strconv.ParseInt(m[1], 10, 64)
+	usec, _ := strconv.ParseInt(m[2], 10, 64)
+	ev := InputEvent{
+		Time:   time.Duration(sec)*time.Second + time.Duration(usec)*time.Microsecond,
+		Device: m[3],
+		Type:   m[4],
+		Code:   m[5],
+	}
+	if ev.Device == "" {
+		ev.Device = device
+	}
+	switch m[6] {
+	case "UP":
+		ev.Value = 0
+	case "DOWN":
+		ev.Value = 1
+	case "REPEAT":
+		ev.Value = 2
+	default:
+		v, err := strconv.ParseUint(m[6], 16, 32)
+		if err != nil {
+			return InputEvent{}, false
+		}
+		// 负数（例如抬起时的 tracking id -1）以 32 位补码输出
+		ev.Value = int32(uint32(v))
+	}
+	return ev, true
+}
+
+// eventNumbers 是 getevent -l 符号名称对应的数值（linux/input-event-codes.h），用于 SendEvent。
+var eventNumbers = map[string]int{
+	"EV_SYN": evSyn, "EV_KEY": evKey, "EV_REL": 2, "EV_ABS": evAbs, "EV_MSC": 4,
+
+	"SYN_REPORT": 0, "SYN_MT_REPORT": 2,
+	"BTN_TOUCH": btnTouch, "BTN_TOOL_FINGER": 325,
+	"ABS_X": 0, "ABS_Y": 1, "ABS_PRESSURE": 24,
+	"ABS_MT_SLOT": absMTSlot, "ABS_MT_TOUCH_MAJOR": absMTTouchMajor, "ABS_MT_TOUCH_MINOR": 49,
+	"ABS_MT_WIDTH_MAJOR": 50, "ABS_MT_POSITION_X": absMTPositionX, "ABS_MT_POSITION_Y": absMTPositionY,
+	"ABS_MT_TRACKING_ID": absMTTrackingID, "ABS_MT_PRESSURE": absMTPressure,
+	"ABS_MT_TOOL_TYPE": absMTToolType, "MSC_SCAN": 4,
+	"KEY_VOLUMEDOWN": 114, "KEY_VOLUMEUP": 115, "KEY_POWER": 116, "KEY_BACK": 158, "KEY_HOMEPAGE": 172,
+}
+
+// eventNumber 将事件类型或代码的名称转换为数值，名称不在表中时按 getevent 输出的十六进制数字解析。
+func eventNumber(name string) (int, error) {
+	if n, ok := eventNumbers[name]; ok {
+		return n, nil
+	}
+	n, err := strconv.ParseUint(name, 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("unknown event name %q", name)
+	}
+	return int(n), nil
+}
+
+// 事件回放的参数。
+const (
+	maxEventsPerCommand = 100                   // 每条 shell 命令最多包含的 sendevent 数量
+	minEventGap         = 10 * time.Millisecond // 相邻事件的时间差达到该值时才插入 sleep
+)
+
+// sleepCommand 返回在设备 shell 中暂停 d 的命令。
+// toybox（Android 6.0+）的 sleep 支持小数秒；更早的 toolbox 只接受整数秒，此时退回到 usleep，
+// 两者都不可用时不暂停。错误输出被丢弃，以免被当作 sendevent 的失败信息。
+func sleepCommand(d time.Duration) string {
+	return fmt.Sprintf("(sleep %.3f || usleep %d) 2>/dev/null", d.Seconds(), d.Microseconds())
+}
+
+// SendEvent 通过 sendevent 直接向输入设备写入原始事件。
+// 与 'input' 命令不同，事件直接进入内核输入子系统，与真实的触摸无法区分，
+// 适用于屏蔽了 input 命令注入的应用，也用于精确回放 GetEvent 录制的事件。
+//
+// 参数：
+//   - device: 输入设备节点，例如 "/dev/input/event2"
+//   - events: 要写入的事件；Device 字段被忽略，Type / Code 可以是符号名称或十六进制数字
+//
+// 返回值：
+//   - error: 如果事件名称无法识别、没有写入设备的权限（错误包装了 ErrRootRequired）或执行失败，返回 error 对象
+//
+// 工作原理：
+//   - 所有事件转换为 'sendevent <设备> <类型> <代码> <值>'，多条合并为一条 shell 命令
+//   - 相邻事件的 Time 相差 10 毫秒以上时插入 sleep，保持录制时的节奏；Time 全为 0 时连续写入
+//   - 事件很多时每 100 条分为一条命令
+//
+// 注意事项：
+//   - 大多数设备的 shell 用户属于 input 组，可以直接写入；否则需要 root
+//   - 触摸坐标是触摸屏的原始坐标，不一定等于屏幕像素坐标，可使用 SendTouchPath 自动换算
+//
+// 示例：
+//
+//	// 回放录制的事件
+//	var recorded []adb.InputEvent
+//	for ev := range events {
+//	    recorded = append(recorded, ev)
+//	}
+//	err := device.SendEvent("/dev/input/event2", recorded)
+func (d *Device) SendEvent(device string, events []InputEvent) error {
+	var cmds []string
+	flush := func() error {
+		if len(cmds) == 0 {
+			return nil
+		}
+		output, err := d.Shell(strings.Join(cmds, "; "))
+		cmds = nil
+		if strings.Contains(output+errString(err), "Permission denied") {
+			return fmt.Errorf("sendevent %s: permission denied: %w", device, ErrRootRequired)
+		}
+		if err != nil {
+			return err
+		}
+		if output != "" {
+			return fmt.Errorf("sendevent %s failed: %s", device, truncate(output, 200))
+		}
+		return nil
+	}
+
+	for i, ev := range events {
+		typ, err := eventNumber(ev.Type)
+		if err != nil {
+			return err
+		}
+		code, err := eventNumber(ev.Code)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if gap := ev.Time - events[i-1].Time; gap >= minEventGap {
+				cmds = append(cmds, sleepCommand(gap))
+			}
+		}
+		cmds = append(cmds, fmt.Sprintf("sendevent %s %d %d %d", shellQuote(device), typ, code, ev.Value))
+		if len(cmds) >= maxEventsPerCommand {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// TouchEvents 生成单指按下、移动、抬起的事件序列（B 类多点触控协议），配合 SendEvent 使用。
+//
+// 参数：
+//   - path: 触摸屏原始坐标下的轨迹，第一个点按下，最后一个点抬起，至少一个点
+//   - interval: 相邻两个点之间的时间间隔，会写入事件的 Time 字段
+//
+// 返回值：
+//   - []InputEvent: 事件序列
+//
+// 示例：
+//
+//	events := adb.TouchEvents([]adb.Point{{X: 500, Y: 1800}, {X: 500, Y: 1200}, {X: 500, Y: 600}}, 16*time.Millisecond)
+//	err := device.SendEvent("/dev/input/event2", events)
+func TouchEvents(path []Point, interval time.Duration) []InputEvent {
+	if len(path) == 0 {
+		return nil
+	}
+	var events []InputEvent
+	var t time.Duration
+	add := func(typ, code string, value int32) {
+		events = append(events, InputEvent{Time: t, Type: typ, Code: code, Value: value})
+	}
+
+	add("EV_ABS", "ABS_MT_SLOT", 0)
+	add("EV_ABS", "ABS_MT_TRACKING_ID", 1)
+	for i, p := range path {
+		if i > 0 {
+			t += interval
+		}
+		add("EV_ABS", "ABS_MT_POSITION_X", int32(p.X))
+		add("EV_ABS", "ABS_MT_POSITION_Y", int32(p.Y))
+		if i == 0 {
+			add("EV_KEY", "BTN_TOUCH", 1)
+		}
+		add("EV_SYN", "SYN_REPORT", 0)
+	}
+	// tracking id 为 -1 表示手指抬起
+	add("EV_ABS", "ABS_MT_TRACKING_ID", -1)
+	add("EV_KEY", "BTN_TOUCH", 0)
+	add("EV_SYN", "SYN_REPORT", 0)
+	return events
+}
+
+// SendTouchPath 沿屏幕坐标轨迹模拟一次单指触摸（按下、移动、抬起），通过 SendEvent 写入触摸屏设备。
+// 自动查找触摸屏设备并把屏幕坐标换算为触摸屏坐标。
+//
+// 参数：
+//   - interval: 相邻两个点之间的时间间隔，例如 16 毫秒
+//   - points: 屏幕坐标（自然方向）下的轨迹，只有一个点时相当于点击
+//
+// 返回值：
+//   - error: 如果坐标超出屏幕、找不到触摸屏设备或写入失败，返回 error 对象
+//
+// 示例：
+//
+//	// 从下往上慢速拖动
+//	err := device.SendTouchPath(16*time.Millisecond, adb.Point{X: 540, Y: 1800}, adb.Point{X: 540, Y: 1200}, adb.Point{X: 540, Y: 600})
+func (d *Device) SendTouchPath(interval time.Duration, points ...Point) error {
+	if len(points) == 0 {
+		return fmt.Errorf("no point to touch")
+	}
+	w, h, err := d.cachedScreenSize()
+	if err != nil {
+		return err
+	}
+	dev, err := d.touchDevice()
+	if err != nil {
+		return err
+	}
+	raw := make([]Point, len(points))
+	for i, p := range points {
+		if p.X < 0 || p.Y < 0 || p.X >= w || p.Y >= h {
+			return fmt.Errorf("point (%d,%d) out of screen %dx%d", p.X, p.Y, w, h)
+		}
+		raw[i] = Point{X: scaleAxis(p.X, w, dev.minX, dev.maxX), Y: scaleAxis(p.Y, h, dev.minY, dev.maxY)}
+	}
+	return d.SendEvent(dev.path, TouchEvents(raw, interval))
+}
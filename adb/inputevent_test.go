@@ -0,0 +1,96 @@
+package adb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseGetevent(t *testing.T) {
+	ts := 12345*time.Second + 678901*time.Microsecond
+	tests := []struct {
+		line   string
+		device string // 只读取一个设备时传给 parseGetevent 的设备节点
+		want   InputEvent
+		ok     bool
+	}{
+		// 读取所有设备时每行带设备节点
+		{"[   12345.678901] /dev/input/event2: EV_ABS       ABS_MT_POSITION_X    000001f4", "",
+			InputEvent{Time: ts, Device: "/dev/input/event2", Type: "EV_ABS", Code: "ABS_MT_POSITION_X", Value: 500}, true},
+		{"[   12345.678901] /dev/input/event0: EV_KEY       KEY_POWER            DOWN", "",
+			InputEvent{Time: ts, Device: "/dev/input/event0", Type: "EV_KEY", Code: "KEY_POWER", Value: 1}, true},
+		// 只读取一个设备时行中没有设备节点
+		{"[   12345.678901] EV_ABS       ABS_MT_POSITION_Y    00000320", "/dev/input/event2",
+			InputEvent{Time: ts, Device: "/dev/input/event2", Type: "EV_ABS", Code: "ABS_MT_POSITION_Y", Value: 800}, true},
+		{"[   12345.678901] EV_KEY       BTN_TOUCH            UP", "/dev/input/event2",
+			InputEvent{Time: ts, Device: "/dev/input/event2", Type: "EV_KEY", Code: "BTN_TOUCH", Value: 0}, true},
+		{"[   12345.678901] EV_KEY       KEY_VOLUMEUP         REPEAT", "/dev/input/event1",
+			InputEvent{Time: ts, Device: "/dev/input/event1", Type: "EV_KEY", Code: "KEY_VOLUMEUP", Value: 2}, true},
+		// 负数以 32 位补码输出
+		{"[   12345.678901] /dev/input/event2: EV_ABS       ABS_MT_TRACKING_ID   ffffffff", "",
+			InputEvent{Time: ts, Device: "/dev/input/event2", Type: "EV_ABS", Code: "ABS_MT_TRACKING_ID", Value: -1}, true},
+		{"[   12345.678901] EV_ABS       ABS_MT_TRACKING_ID   fffffffe", "/dev/input/event2",
+			InputEvent{Time: ts, Device: "/dev/input/event2", Type: "EV_ABS", Code: "ABS_MT_TRACKING_ID", Value: -2}, true},
+		// 内核没有名称的类型和代码保留十六进制数字
+		{"[       1.000002] /dev/input/event5: 0003         0035                 00000000", "",
+			InputEvent{Time: time.Second + 2*time.Microsecond, Device: "/dev/input/event5", Type: "0003", Code: "0035"}, true},
+		// 不是事件行
+		{"add device 2: /dev/input/event2", "", InputEvent{}, false},
+		{`  name:     "synaptics_dsx"`, "", InputEvent{}, false},
+		{"could not get driver version for /dev/input/mouse0, Not a typewriter", "", InputEvent{}, false},
+		{"[   12345.678901] EV_ABS       ABS_MT_POSITION_X    zzzz", "/dev/input/event2", InputEvent{}, false},
+		{"", "", InputEvent{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseGetevent(tt.line, tt.device)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseGetevent(%q, %q) = %+v, %v; want %+v, %v", tt.line, tt.device, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetEventSingleDevice(t *testing.T) {
+	fakeAdb(t)
+	// 伪造的 getevent：指定设备时输出不带设备节点的格式
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"if [ -n \"$2\" ]; then\n" +
+		"  echo \"[   100.000001] EV_ABS       ABS_MT_TRACKING_ID   ffffffff\"\n" +
+		"  echo \"[   100.000002] EV_SYN       SYN_REPORT           00000000\"\n" +
+		"else\n" +
+		"  echo \"[   100.000001] /dev/input/event1: EV_KEY       KEY_POWER            DOWN\"\n" +
+		"  echo \"[   100.000002] /dev/input/event2: EV_SYN       SYN_REPORT           00000000\"\n" +
+		"fi\n"
+	if err := os.WriteFile(filepath.Join(dir, "getevent"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	d := NewDevice()
+
+	collect := func(devices ...string) []InputEvent {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		events, err := d.GetEvent(ctx, devices...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []InputEvent
+		for ev := range events {
+			got = append(got, ev)
+		}
+		return got
+	}
+
+	got := collect("/dev/input/event2")
+	if len(got) != 2 || got[0].Device != "/dev/input/event2" || got[0].Value != -1 || got[1].Code != "SYN_REPORT" {
+		t.Errorf("single device events = %+v", got)
+	}
+	if got := collect("/dev/input/event1", "/dev/input/event3"); len(got) != 1 || got[0].Code != "KEY_POWER" {
+		t.Errorf("filtered events = %+v, want only KEY_POWER from event1", got)
+	}
+	if got := collect(); len(got) != 2 {
+		t.Errorf("all devices returned %d events, want 2", len(got))
+	}
+}
@@ -0,0 +1,438 @@
+package adb
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// InstallOption 是安装 APK 时的可选参数。
+//
+// 示例：
+//
+//	err := device.InstallMultiple(apks, adb.InstallReplace(), adb.InstallGrantPermissions())
+type InstallOption func(*installOptions)
+
+// installOptions 保存安装参数，对应 pm install 的命令行标志。
+type installOptions struct {
+	replace   bool // -r 覆盖安装
+	downgrade bool // -d 允许降级
+	grant     bool // -g 授予所有运行时权限
+	allowTest bool // -t 允许安装测试包
+
+	httpClient *http.Client // InstallFromURL 下载使用的客户端
+	maxSize    int64        // InstallFromURL 允许下载的最大字节数
+}
+
+// defaultMaxDownloadSize 是 InstallFromURL 默认允许下载的最大 APK 大小（1 GiB）。
+const defaultMaxDownloadSize = 1 << 30
+
+// InstallReplace 覆盖安装已存在的应用（保留应用数据），对应 -r。
+func InstallReplace() InstallOption {
+	return func(o *installOptions) { o.replace = true }
+}
+
+// InstallDowngrade 允许安装比已安装版本更低的版本，对应 -d。
+func InstallDowngrade() InstallOption {
+	return func(o *installOptions) { o.downgrade = true }
+}
+
+// InstallGrantPermissions 安装时授予清单中声明的所有运行时权限，对应 -g（Android 6.0+）。
+func InstallGrantPermissions() InstallOption {
+	return func(o *installOptions) { o.grant = true }
+}
+
+// InstallAllowTest 允许安装 android:testOnly 的测试包，对应 -t。
+func InstallAllowTest() InstallOption {
+	return func(o *installOptions) { o.allowTest = true }
+}
+
+// InstallHTTPClient 指定 InstallFromURL 下载 APK 使用的 HTTP 客户端，
+// 用于配置代理、认证、超时等。默认使用 http.DefaultClient。
+func InstallHTTPClient(client *http.Client) InstallOption {
+	return func(o *installOptions) { o.httpClient = client }
+}
+
+// InstallMaxSize 指定 InstallFromURL 允许下载的最大字节数，默认 1 GiB。
+func InstallMaxSize(n int64) InstallOption {
+	return func(o *installOptions) { o.maxSize = n }
+}
+
+// newInstallOptions 应用所有安装选项。
+func newInstallOptions(opts []InstallOption) installOptions {
+	o := installOptions{httpClient: http.DefaultClient, maxSize: defaultMaxDownloadSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// flags 将安装选项转换为命令行标志。
+func (o installOptions) flags() []string {
+	var flags []string
+	if o.replace {
+		flags = append(flags, "-r")
+	}
+	if o.downgrade {
+		flags = append(flags, "-d")
+	}
+	if o.grant {
+		flags = append(flags, "-g")
+	}
+	if o.allowTest {
+		flags = append(flags, "-t")
+	}
+	return flags
+}
+
+// InstallAPK 安装本地 APK 文件。
+//
+// 参数：
+//   - path: 本地 APK 路径；.apks / .apkm / .xapk 压缩包会交给 InstallMultiple 处理
+//   - opts: 可选的安装参数，例如 InstallReplace()、InstallGrantPermissions()
+//
+// 返回值：
+//   - error: 如果安装失败，返回 error 对象，错误信息中包含 adb 的输出（例如 INSTALL_FAILED_VERSION_DOWNGRADE）
+//
+// 示例：
+//
+//	err := device.InstallAPK("app-debug.apk", adb.InstallReplace(), adb.InstallAllowTest())
+//	if err != nil {
+//	    log.Fatal("安装失败:", err)
+//	}
+func (d *Device) InstallAPK(path string, opts ...InstallOption) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".apks", ".apkm", ".xapk":
+		return d.InstallMultiple([]string{path}, opts...)
+	}
+
+	if err := d.checkStorage("/data", path); err != nil {
+		return err
+	}
+
+	args := append([]string{"install"}, newInstallOptions(opts).flags()...)
+	args = append(args, path)
+	output, err := d.execCommand(args...)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(output, "Success") {
+		return fmt.Errorf("install %s failed: %s", path, output)
+	}
+	return nil
+}
+
+// InstallTimeout 与 InstallAPK 相同，但只对这一次安装使用指定的超时时间，
+// 适用于安装大体积 APK 或在较慢的设备上安装。
+// 超时的优先级与 ShellTimeout 相同：本次调用的 timeout > 默认超时 > 不限制。
+//
+// 示例：
+//
+//	err := device.InstallTimeout("game-release.apk", 5*time.Minute, adb.InstallReplace())
+func (d *Device) InstallTimeout(path string, timeout time.Duration, opts ...InstallOption) error {
+	return d.withTimeout(timeout).InstallAPK(path, opts...)
+}
+
+// InstallFromURL 从 URL 下载 APK 并安装到设备，适用于 CI 中直接安装构建产物。
+//
+// 参数：
+//   - url: APK 的下载地址
+//   - opts: 可选的安装参数；除 InstallReplace() 等安装标志外，
+//     还可以使用 InstallHTTPClient() 配置代理或认证，InstallMaxSize() 限制下载大小
+//
+// 返回值：
+//   - error: 如果下载、校验或安装失败，返回 error 对象
+//
+// 工作原理：
+//  1. 下载到本地临时文件，超过大小限制立即中止
+//  2. 检查 Content-Type，拒绝 text/html 等文本响应（通常是登录页或错误页）
+//  3. 检查文件头是否为 zip 魔数 "PK"
+//  4. 调用 InstallAPK 安装
+//
+// 注意事项：
+//   - 临时文件在返回前删除，无论安装是否成功
+//   - 只支持单个 APK，不支持 .apks 等压缩包
+//
+// 示例：
+//
+//	err := device.InstallFromURL("https://ci.example.com/artifacts/app-debug.apk", adb.InstallReplace())
+//
+//	// 通过带认证的客户端下载
+//	client := &http.Client{Timeout: 5 * time.Minute, Transport: authTransport}
+//	err = device.InstallFromURL(url, adb.InstallHTTPClient(client), adb.InstallMaxSize(200<<20))
+func (d *Device) InstallFromURL(url string, opts ...InstallOption) error {
+	o := newInstallOptions(opts)
+
+	tmp, err := os.CreateTemp("", "adb-download-*.apk")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = download(o.httpClient, url, tmp, o.maxSize)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("download %s: %w", url, err)
+	}
+	return d.InstallAPK(tmp.Name(), opts...)
+}
+
+// download 将 url 的内容写入 w，并校验响应状态、Content-Type、大小和 zip 魔数。
+func download(client *http.Client, url string, w io.Writer, maxSize int64) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") {
+			return fmt.Errorf("unexpected content type %s", mediaType)
+		}
+	}
+	if resp.ContentLength > maxSize {
+		return fmt.Errorf("file too large: %d bytes (limit %d)", resp.ContentLength, maxSize)
+	}
+
+	// 先读取文件头校验 zip 魔数，再写入剩余内容；多读 1 字节用于判断是否超过限制
+	body := io.LimitReader(resp.Body, maxSize+1)
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(body, head); err != nil || !bytes.Equal(head, []byte("PK")) {
+		return fmt.Errorf("not an apk (missing zip header)")
+	}
+	if _, err := w.Write(head); err != nil {
+		return err
+	}
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return err
+	}
+	if n+int64(len(head)) > maxSize {
+		return fmt.Errorf("file too large (limit %d bytes)", maxSize)
+	}
+	return nil
+}
+
+// sessionRe 用于从 pm install-create 的输出中提取会话 ID。
+// 输出格式："Success: created install session [1234567]"
+var sessionRe = regexp.MustCompile(`install session \[(\d+)]`)
+
+// InstallMultiple 以原子方式安装一组拆分 APK（split APKs）。
+// App Bundle 分发的应用由 base.apk 和多个配置拆分包组成，必须在同一个安装会话中一起安装，
+// 普通的 'adb install' 无法处理这种情况。
+//
+// 参数：
+//   - apks: 本地 APK 路径列表，也可以是 .apks / .apkm / .xapk 压缩包（会先解压到临时目录）
+//   - opts: 可选的安装参数，例如 InstallReplace()、InstallGrantPermissions()
+//
+// 返回值：
+//   - error: 如果任意一步失败，返回 error 对象；失败时会放弃（abandon）安装会话
+//
+// 工作原理：
+//  1. pm install-create 创建安装会话
+//  2. 逐个推送 APK 到 /data/local/tmp，并通过 pm install-write 写入会话
+//  3. pm install-commit 提交会话，所有拆分包一起生效
+//
+// 注意事项：
+//   - 压缩包中如果存在 splits/ 目录（bundletool 生成的 .apks），只安装该目录下的 APK
+//   - 压缩包会安装其中的所有拆分包，如需按设备配置筛选，请先使用 bundletool 处理
+//   - 推送到设备的临时文件会在结束后删除
+//   - 设置了 WithStorageCheck 时，在创建会话前按所有拆分包的总大小检查一次 /data 的可用空间
+//
+// 示例：
+//
+//	// 安装拆分包
+//	err := device.InstallMultiple([]string{"base.apk", "split_config.arm64_v8a.apk", "split_config.xxhdpi.apk"})
+//	if err != nil {
+//	    log.Fatal("安装失败:", err)
+//	}
+//
+//	// 安装 .apks 文件并覆盖旧版本
+//	err = device.InstallMultiple([]string{"app.apks"}, adb.InstallReplace())
+func (d *Device) InstallMultiple(apks []string, opts ...InstallOption) error {
+	if len(apks) == 0 {
+		return fmt.Errorf("no apk to install")
+	}
+
+	// 展开压缩包，得到实际要安装的 APK 列表
+	files, cleanup, err := expandApks(apks)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	sizes := make([]int64, len(files))
+	for i, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		sizes[i] = info.Size()
+		total += info.Size()
+	}
+	if err := d.checkStorage("/data", files...); err != nil {
+		return err
+	}
+
+	// 创建安装会话
+	o := newInstallOptions(opts)
+	create := append([]string{"pm", "install-create"}, o.flags()...)
+	create = append(create, "-S", fmt.Sprint(total))
+	output, err := d.Shell(strings.Join(create, " "))
+	if err != nil {
+		return err
+	}
+	m := sessionRe.FindStringSubmatch(output)
+	if m == nil {
+		return fmt.Errorf("install-create failed: %s", output)
+	}
+	session := m[1]
+
+	// 之后任何一步失败（包括提交失败）都放弃会话，避免未完成的会话残留在设备上
+	committed := false
+	defer func() {
+		if !committed {
+			d.Shell("pm install-abandon " + session)
+		}
+	}()
+
+	for i, f := range files {
+		if err := d.installWrite(session, i, f, sizes[i]); err != nil {
+			return err
+		}
+	}
+
+	output, err = d.Shell("pm install-commit " + session)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(output, "Success") {
+		return fmt.Errorf("install-commit failed: %s", output)
+	}
+	committed = true
+	return nil
+}
+
+// installWrite 推送单个 APK 到设备并写入安装会话。
+func (d *Device) installWrite(session string, index int, localPath string, size int64) error {
+	remote := fmt.Sprintf("/data/local/tmp/%s_%d.apk", session, index)
+	// 存储空间已由 InstallMultiple 按总大小检查过一次，这里不再经过 Push 逐个检查
+	if _, err := d.execCommand("push", localPath, remote); err != nil {
+		return err
+	}
+	defer d.Shell("rm -f " + remote)
+
+	name := fmt.Sprintf("%d_%s", index, filepath.Base(localPath))
+	output, err := d.Shell(fmt.Sprintf("pm install-write -S %d %s %s %s", size, session, name, remote))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(output, "Success") {
+		return fmt.Errorf("install-write %s failed: %s", localPath, output)
+	}
+	return nil
+}
+
+// expandApks 展开 APK 列表中的 .apks / .apkm / .xapk 压缩包。
+// 返回的 cleanup 函数用于删除临时目录，调用方必须调用（即使返回了错误）。
+func expandApks(paths []string) (files []string, cleanup func(), err error) {
+	var tmpDirs []string
+	cleanup = func() {
+		for _, dir := range tmpDirs {
+			os.RemoveAll(dir)
+		}
+	}
+
+	for _, p := range paths {
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".apks", ".apkm", ".xapk":
+			dir, err := os.MkdirTemp("", "adb-apks-")
+			if err != nil {
+				return nil, cleanup, err
+			}
+			tmpDirs = append(tmpDirs, dir)
+			extracted, err := extractApks(p, dir)
+			if err != nil {
+				return nil, cleanup, err
+			}
+			files = append(files, extracted...)
+		default:
+			files = append(files, p)
+		}
+	}
+	return files, cleanup, nil
+}
+
+// extractApks 将压缩包中的 APK 解压到 dir，返回解压后的文件路径。
+func extractApks(archive, dir string) ([]string, error) {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", archive, err)
+	}
+	defer r.Close()
+
+	var all, splits []*zip.File
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !strings.HasSuffix(strings.ToLower(f.Name), ".apk") {
+			continue
+		}
+		all = append(all, f)
+		if strings.HasPrefix(f.Name, "splits/") {
+			splits = append(splits, f)
+		}
+	}
+	// bundletool 生成的 .apks 同时包含 splits/ 和 standalones/，只需要 splits/
+	if len(splits) > 0 {
+		all = splits
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("no apk found in %s", archive)
+	}
+
+	var out []string
+	for i, f := range all {
+		// 只使用文件名并加上序号，避免路径穿越和重名
+		dst := filepath.Join(dir, fmt.Sprintf("%d_%s", i, filepath.Base(f.Name)))
+		if err := extractFile(f, dst); err != nil {
+			return nil, err
+		}
+		out = append(out, dst)
+	}
+	return out, nil
+}
+
+// extractFile 将压缩包中的单个文件写入 dst。
+func extractFile(f *zip.File, dst string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, rc); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,99 @@
+package adb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// splitApks 在临时目录中创建若干个指定大小的拆分包。
+func splitApks(t *testing.T, sizes ...int) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for i, size := range sizes {
+		p := filepath.Join(dir, fmt.Sprintf("split_%d.apk", i))
+		if err := os.WriteFile(p, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// installDevice 模拟 pm install-* 会话命令，commit 为 install-commit 的输出。
+func installDevice(available, commit string) (*Device, *fakeRunner) {
+	d, r := newFakeDevice(func(command string) (string, error) {
+		switch {
+		case strings.HasPrefix(command, "df"):
+			return "Filesystem 1B-blocks Used Available Use% Mounted on\n/dev/block/dm-5 100000 0 " + available + " 0% /data", nil
+		case strings.HasPrefix(command, "pm install-create"):
+			return "Success: created install session [1234]", nil
+		case strings.HasPrefix(command, "pm install-write"):
+			return "Success: streamed 100 bytes", nil
+		case strings.HasPrefix(command, "pm install-commit"):
+			return commit, nil
+		}
+		return "", nil
+	})
+	WithStorageCheck()(d)
+	return d, r
+}
+
+func TestInstallMultipleChecksStorageOnce(t *testing.T) {
+	apks := splitApks(t, 300, 200, 100)
+	d, r := installDevice("1000", "Success")
+	if err := d.InstallMultiple(apks); err != nil {
+		t.Fatal(err)
+	}
+	var df int
+	for _, c := range r.shellCommands() {
+		if strings.HasPrefix(c, "df") {
+			df++
+		}
+	}
+	if df != 1 {
+		t.Errorf("checked storage %d times, want once for all splits", df)
+	}
+	if cmds := r.shellCommands(); !slices.Contains(cmds, "pm install-create -S 600") {
+		t.Errorf("commands = %q, want install-create with the total size", cmds)
+	}
+
+	// 总大小超过可用空间时不创建会话
+	d, r = installDevice("500", "Success")
+	if err := d.InstallMultiple(apks); !errors.Is(err, ErrInsufficientStorage) {
+		t.Errorf("InstallMultiple with 500 bytes free = %v, want ErrInsufficientStorage", err)
+	}
+	for _, c := range r.shellCommands() {
+		if strings.HasPrefix(c, "pm ") {
+			t.Errorf("ran %q after the storage check failed", c)
+		}
+	}
+}
+
+func TestInstallMultipleAbandonsFailedSession(t *testing.T) {
+	apks := splitApks(t, 10, 10)
+	d, r := installDevice("1000", "Failure [INSTALL_FAILED_MISSING_SPLIT: Missing split for com.example.app]")
+	err := d.InstallMultiple(apks)
+	if err == nil || !strings.Contains(err.Error(), "INSTALL_FAILED_MISSING_SPLIT") {
+		t.Fatalf("InstallMultiple() = %v, want the commit failure", err)
+	}
+	cmds := r.shellCommands()
+	if last := cmds[len(cmds)-1]; last != "pm install-abandon 1234" {
+		t.Errorf("last command = %q, want the session abandoned", last)
+	}
+
+	d, r = installDevice("1000", "Success")
+	if err := d.InstallMultiple(apks); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range r.shellCommands() {
+		if strings.Contains(c, "install-abandon") {
+			t.Errorf("abandoned a committed session: %q", c)
+		}
+	}
+}
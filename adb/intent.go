@@ -0,0 +1,152 @@
+package adb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// intentExtras 将 extras 转换为 am 命令的附加参数（--es / --ei / --ez 等）。
+// 键按字母顺序排列，保证生成的命令稳定；所有键和值都经过 shell 转义。
+//
+// 支持的值类型：
+//   - string: --es
+//   - bool: --ez
+//   - int / int32: --ei
+//   - int64: --el
+//   - float32 / float64: --ef
+//   - []string: --esa（逗号分隔）
+//   - []int: --eia（逗号分隔）
+//   - nil: --esn（空字符串 extra）
+func intentExtras(extras map[string]interface{}) ([]string, error) {
+	keys := make([]string, 0, len(extras))
+	for k := range extras {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var args []string
+	for _, k := range keys {
+		key := shellQuote(k)
+		switch v := extras[k].(type) {
+		case nil:
+			args = append(args, "--esn", key)
+		case string:
+			args = append(args, "--es", key, shellQuote(v))
+		case bool:
+			args = append(args, "--ez", key, fmt.Sprint(v))
+		case int, int32:
+			args = append(args, "--ei", key, fmt.Sprint(v))
+		case int64:
+			args = append(args, "--el", key, fmt.Sprint(v))
+		case float32, float64:
+			args = append(args, "--ef", key, fmt.Sprint(v))
+		case []string:
+			args = append(args, "--esa", key, shellQuote(strings.Join(v, ",")))
+		case []int:
+			parts := make([]string, len(v))
+			for i, n := range v {
+				parts[i] = fmt.Sprint(n)
+			}
+			args = append(args, "--eia", key, strings.Join(parts, ","))
+		default:
+			return nil, fmt.Errorf("unsupported extra type %T for key %q", v, k)
+		}
+	}
+	return args, nil
+}
+
+// StartService 启动指定应用的服务（Service）。
+// 该方法是 StartActivity 的补充，适用于入口是服务而不是界面的应用。
+//
+// 参数：
+//   - pkg: 应用包名（例如："com.example.app"）
+//   - service: 服务类名，可以是 ".SyncService" 简写或完整类名，内部类名中的 "$" 会被正确转义
+//   - extras: 附加到 Intent 的参数，可以为 nil；支持的类型见 intentExtras
+//
+// 返回值：
+//   - string: am 命令的输出，例如 "Starting service: Intent { cmp=com.example.app/.SyncService }"
+//   - error: 如果启动失败，返回包含 am 输出的 error 对象
+//
+// 启动方式：
+//   - 先以普通服务方式启动（Android 8.0+ 使用 am start-service，更早的版本使用 am startservice）
+//   - Android 8.0+（API 26+）上应用处于后台时，系统会拒绝普通启动并输出 "Not allowed to start service"，
+//     此时改用 am start-foreground-service 以前台服务方式重试
+//
+// 注意事项：
+//   - 以前台服务方式启动时，服务必须在约 5 秒内调用 startForeground()，否则应用会被系统终止；
+//     因此只在普通启动被拒绝时才使用这种方式
+//   - 服务需要是导出的（exported），或者 shell 拥有相应权限
+//
+// 示例：
+//
+//	output, err := device.StartService("com.example.app", ".SyncService", map[string]interface{}{
+//	    "force": true,
+//	    "user":  "test01",
+//	})
+//	if err != nil {
+//	    log.Fatal("启动服务失败:", err)
+//	}
+//	fmt.Println(output)
+func (d *Device) StartService(pkg, service string, extras map[string]interface{}) (string, error) {
+	args, err := intentExtras(extras)
+	if err != nil {
+		return "", err
+	}
+
+	sdk, err := d.SDKLevel()
+	if err != nil {
+		return "", err
+	}
+	verb := "startservice"
+	if sdk >= 26 {
+		verb = "start-service"
+	}
+
+	start := func(verb string) (string, error) {
+		command := append([]string{"am", verb, "-n", shellQuote(pkg + "/" + service)}, args...)
+		return d.Shell(strings.Join(command, " "))
+	}
+	output, err := start(verb)
+	if err != nil {
+		return "", err
+	}
+	if sdk >= 26 && strings.Contains(output, "Not allowed to start service") {
+		// 后台应用不允许启动普通服务，改为以前台服务方式启动
+		if output, err = start("start-foreground-service"); err != nil {
+			return "", err
+		}
+	}
+	if strings.Contains(output, "Not allowed to start service") {
+		return "", fmt.Errorf("start service %s/%s not allowed: %s", pkg, service, output)
+	}
+	if strings.Contains(output, "Error") {
+		return "", fmt.Errorf("start service %s/%s failed: %s", pkg, service, output)
+	}
+	return output, nil
+}
+
+// StopService 停止指定应用的服务。
+//
+// 参数：
+//   - pkg: 应用包名
+//   - service: 服务类名，可以是 ".SyncService" 简写或完整类名
+//
+// 返回值：
+//   - error: 如果服务不存在或未在运行，返回 error 对象
+//
+// 示例：
+//
+//	if err := device.StopService("com.example.app", ".SyncService"); err != nil {
+//	    log.Println("停止服务失败:", err)
+//	}
+func (d *Device) StopService(pkg, service string) error {
+	output, err := d.Shellf("am stopservice -n %s", pkg+"/"+service)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(output, "not stopped") || strings.Contains(output, "Error") {
+		return fmt.Errorf("stop service %s/%s failed: %s", pkg, service, output)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package adb
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// serviceDevice 模拟 am 启动服务：background 为 true 时应用处于后台，普通启动会被拒绝。
+func serviceDevice(sdk string, background bool) (*Device, *fakeRunner) {
+	s := &propStore{props: map[string]string{"ro.build.version.sdk": sdk}}
+	return newFakeDevice(func(command string) (string, error) {
+		if !strings.HasPrefix(command, "am ") {
+			return s.respond(command)
+		}
+		cmp := "com.example.app/.Sync$Job"
+		if !strings.Contains(command, "'"+cmp+"'") {
+			return "Error: Not found; no service started.", nil
+		}
+		if background && !strings.HasPrefix(command, "am start-foreground-service") {
+			return "Starting service: Intent { cmp=" + cmp + " }\nError: Not allowed to start service Intent { cmp=" + cmp + " }: app is in background uid null", nil
+		}
+		return "Starting service: Intent { cmp=" + cmp + " }", nil
+	})
+}
+
+// amCommands 返回执行过的 am 命令。
+func amCommands(r *fakeRunner) []string {
+	var cmds []string
+	for _, c := range r.shellCommands() {
+		if strings.HasPrefix(c, "am ") {
+			cmds = append(cmds, c)
+		}
+	}
+	return cmds
+}
+
+func TestStartService(t *testing.T) {
+	tests := []struct {
+		name       string
+		sdk        string
+		background bool
+		want       []string
+	}{
+		{"API 25", "25", false, []string{"am startservice -n 'com.example.app/.Sync$Job' --ez 'force' true"}},
+		{"API 34 foreground app", "34", false, []string{"am start-service -n 'com.example.app/.Sync$Job' --ez 'force' true"}},
+		{"API 34 background app", "34", true, []string{
+			"am start-service -n 'com.example.app/.Sync$Job' --ez 'force' true",
+			"am start-foreground-service -n 'com.example.app/.Sync$Job' --ez 'force' true",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, r := serviceDevice(tt.sdk, tt.background)
+			output, err := d.StartService("com.example.app", ".Sync$Job", map[string]interface{}{"force": true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if output != "Starting service: Intent { cmp=com.example.app/.Sync$Job }" {
+				t.Errorf("output = %q, want the am output", output)
+			}
+			if got := amCommands(r); !slices.Equal(got, tt.want) {
+				t.Errorf("commands = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartServiceErrors(t *testing.T) {
+	d, _ := serviceDevice("34", false)
+	if _, err := d.StartService("com.example.app", ".Missing", nil); err == nil || !strings.Contains(err.Error(), "no service started") {
+		t.Errorf("StartService(missing) = %v, want the am error", err)
+	}
+	// API 26 以下没有前台服务方式可以重试
+	d, r := serviceDevice("25", true)
+	if _, err := d.StartService("com.example.app", ".Sync$Job", nil); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("StartService(background, API 25) = %v, want not allowed", err)
+	}
+	if n := len(amCommands(r)); n != 1 {
+		t.Errorf("ran %d am commands, want 1", n)
+	}
+}
@@ -0,0 +1,341 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KeyCode 是 Android 按键代码（android.view.KeyEvent 中的 KEYCODE_* 常量）。
+type KeyCode int
+
+// 常用按键代码。
+const (
+	KeyHome       KeyCode = 3   // KEYCODE_HOME
+	KeyBack       KeyCode = 4   // KEYCODE_BACK
+	KeyDpadUp     KeyCode = 19  // KEYCODE_DPAD_UP
+	KeyDpadDown   KeyCode = 20  // KEYCODE_DPAD_DOWN
+	KeyDpadLeft   KeyCode = 21  // KEYCODE_DPAD_LEFT
+	KeyDpadRight  KeyCode = 22  // KEYCODE_DPAD_RIGHT
+	KeyDpadCenter KeyCode = 23  // KEYCODE_DPAD_CENTER
+	KeyVolumeUp   KeyCode = 24  // KEYCODE_VOLUME_UP
+	KeyVolumeDown KeyCode = 25  // KEYCODE_VOLUME_DOWN
+	KeyPower      KeyCode = 26  // KEYCODE_POWER
+	KeyTab        KeyCode = 61  // KEYCODE_TAB
+	KeySpace      KeyCode = 62  // KEYCODE_SPACE
+	KeyEnter      KeyCode = 66  // KEYCODE_ENTER
+	KeyDel        KeyCode = 67  // KEYCODE_DEL（退格）
+	KeyMenu       KeyCode = 82  // KEYCODE_MENU
+	KeySearch     KeyCode = 84  // KEYCODE_SEARCH
+	KeyPageUp     KeyCode = 92  // KEYCODE_PAGE_UP
+	KeyPageDown   KeyCode = 93  // KEYCODE_PAGE_DOWN
+	KeyEscape     KeyCode = 111 // KEYCODE_ESCAPE
+	KeyForwardDel KeyCode = 112 // KEYCODE_FORWARD_DEL
+	KeyMoveHome   KeyCode = 122 // KEYCODE_MOVE_HOME
+	KeyMoveEnd    KeyCode = 123 // KEYCODE_MOVE_END
+	KeyAppSwitch  KeyCode = 187 // KEYCODE_APP_SWITCH
+	KeySleep      KeyCode = 223 // KEYCODE_SLEEP
+	KeyWakeup     KeyCode = 224 // KEYCODE_WAKEUP
+	KeyCut        KeyCode = 277 // KEYCODE_CUT
+	KeyCopy       KeyCode = 278 // KEYCODE_COPY
+	KeyPaste      KeyCode = 279 // KEYCODE_PASTE
+)
+
+// keyNames 是 SendSequence 中可以使用的按键名称（不区分大小写）。
+var keyNames = map[string]KeyCode{
+	"home":       KeyHome,
+	"back":       KeyBack,
+	"up":         KeyDpadUp,
+	"down":       KeyDpadDown,
+	"left":       KeyDpadLeft,
+	"right":      KeyDpadRight,
+	"center":     KeyDpadCenter,
+	"volumeup":   KeyVolumeUp,
+	"volumedown": KeyVolumeDown,
+	"power":      KeyPower,
+	"tab":        KeyTab,
+	"space":      KeySpace,
+	"enter":      KeyEnter,
+	"del":        KeyDel,
+	"backspace":  KeyDel,
+	"menu":       KeyMenu,
+	"search":     KeySearch,
+	"pageup":     KeyPageUp,
+	"pagedown":   KeyPageDown,
+	"esc":        KeyEscape,
+	"escape":     KeyEscape,
+	"delete":     KeyForwardDel,
+	"movehome":   KeyMoveHome,
+	"moveend":    KeyMoveEnd,
+	"appswitch":  KeyAppSwitch,
+	"sleep":      KeySleep,
+	"wakeup":     KeyWakeup,
+	"cut":        KeyCut,
+	"copy":       KeyCopy,
+	"paste":      KeyPaste,
+}
+
+// SendKeys 依次发送多个按键，所有按键合并为一条 'input keyevent k1 k2 ...' 命令。
+//
+// 参数：
+//   - keys: 按键代码，例如 KeyDpadDown、KeyEnter
+//
+// 返回值：
+//   - error: 如果发送失败，返回 error 对象
+//
+// 注意事项：
+//   - 循环调用 KeyEvent 时每个按键都要启动一次 adb 和 input 进程（通常 300ms 以上），
+//     SendKeys 只启动一次，发送 N 个按键的耗时接近单个按键
+//   - 一次发送多个按键需要 Android 4.3+
+//
+// 示例：
+//
+//	// 下移两项后确认
+//	err := device.SendKeys(adb.KeyDpadDown, adb.KeyDpadDown, adb.KeyDpadCenter)
+//	// 实际执行：input keyevent 20 20 23
+func (d *Device) SendKeys(keys ...KeyCode) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := d.Shell(keyeventCommand(keys))
+	return err
+}
+
+// maxKeysPerCommand 是一条 input keyevent 命令最多携带的按键数量。
+// 每个按键约占 3-4 个字符，200 个按键不到 1KB，远低于旧版 adb 4KB 的命令长度限制。
+const maxKeysPerCommand = 200
+
+// RepeatKey 重复按下同一个按键，例如连续按删除键清空输入框，或连续按方向键调整数字选择器。
+//
+// 参数：
+//   - code: 按键代码，例如 KeyDel、KeyDpadDown
+//   - times: 按键次数，必须大于 0
+//   - delay: 两次按键之间的间隔，为 0 时尽可能快地发送
+//
+// 返回值：
+//   - error: 如果 times 不大于 0 或发送失败，返回 error 对象
+//
+// 工作原理：
+//   - delay 为 0 时按键合并为 'input keyevent 67 67 67 ...'，每条命令最多 200 个按键，
+//     超过时分多条命令发送，避免命令行过长
+//   - delay 大于 0 时每次按键单独执行一条命令，命令之间等待 delay
+//
+// 注意事项：
+//   - 合并发送时按键之间没有间隔，部分应用（例如带防抖的选择器）可能会丢失按键，此时应设置 delay
+//   - 合并发送需要 Android 4.3+
+//
+// 示例：
+//
+//	// 清空最多 50 个字符的输入框
+//	err := device.RepeatKey(adb.KeyDel, 50, 0)
+//
+//	// 数字选择器向下滚动 5 项，每次间隔 200 毫秒
+//	err = device.RepeatKey(adb.KeyDpadDown, 5, 200*time.Millisecond)
+func (d *Device) RepeatKey(code KeyCode, times int, delay time.Duration) error {
+	if times <= 0 {
+		return fmt.Errorf("repeat key %d: times must be positive, got %d", code, times)
+	}
+
+	if delay <= 0 {
+		for times > 0 {
+			n := min(times, maxKeysPerCommand)
+			keys := make([]KeyCode, n)
+			for i := range keys {
+				keys[i] = code
+			}
+			if err := d.SendKeys(keys...); err != nil {
+				return err
+			}
+			times -= n
+		}
+		return nil
+	}
+
+	for i := 0; i < times; i++ {
+		if i > 0 {
+			time.Sleep(delay)
+		}
+		if err := d.SendKeys(code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendSequence 按顺序发送一组按键和文本，所有步骤合并为一条 shell 命令执行。
+//
+// 参数：
+//   - steps: 每一项是按键名称或要输入的文本
+//     按键名称（不区分大小写）：home、back、up、down、left、right、center、tab、space、enter、
+//     del / backspace、delete、esc / escape、menu、search、pageup、pagedown、movehome、moveend、
+//     appswitch、power、wakeup、sleep、volumeup、volumedown、cut、copy、paste
+//     其他内容按文本输入（与 Input 相同，需要 ADB Keyboard）
+//     以 "text:" 开头时强制按文本输入，用于输入与按键名称相同的文字
+//
+// 返回值：
+//   - error: 如果执行失败，返回 error 对象
+//
+// 注意事项：
+//   - 相邻的按键合并为一条 input keyevent 命令
+//   - 与 SendKeys 一样，比逐个调用 KeyEvent / Input 快得多
+//
+// 示例：
+//
+//	// 填写表单：用户名、Tab 切换、密码、回车提交
+//	err := device.SendSequence("alice", "tab", "secret", "enter")
+//
+//	// 输入单词 "back" 而不是按返回键
+//	err = device.SendSequence("text:back", "enter")
+func (d *Device) SendSequence(steps ...string) error {
+	command := sequenceCommand(steps)
+	if command == "" {
+		return nil
+	}
+	_, err := d.Shell(command)
+	return err
+}
+
+// sequenceCommand 将按键和文本序列转换为一条 shell 命令。
+func sequenceCommand(steps []string) string {
+	var cmds []string
+	var keys []KeyCode
+	flush := func() {
+		if len(keys) > 0 {
+			cmds = append(cmds, keyeventCommand(keys))
+			keys = nil
+		}
+	}
+	for _, step := range steps {
+		if text, ok := strings.CutPrefix(step, "text:"); ok {
+			flush()
+			cmds = append(cmds, inputCommand(text))
+		} else if key, ok := keyNames[strings.ToLower(step)]; ok {
+			keys = append(keys, key)
+		} else {
+			flush()
+			cmds = append(cmds, inputCommand(step))
+		}
+	}
+	flush()
+	return strings.Join(cmds, "; ")
+}
+
+// keyeventCommand 构造发送多个按键的 input keyevent 命令。
+func keyeventCommand(keys []KeyCode) string {
+	codes := make([]string, len(keys))
+	for i, k := range keys {
+		codes[i] = fmt.Sprint(int(k))
+	}
+	return "input keyevent " + strings.Join(codes, " ")
+}
+
+// keyShiftLeft 是 KEYCODE_SHIFT_LEFT，与其他按键组合输入大写字母和符号。
+const keyShiftLeft KeyCode = 59
+
+// charKey 描述输入一个字符需要的按键。
+type charKey struct {
+	code  KeyCode
+	shift bool // 是否需要同时按住 Shift
+}
+
+// charKeys 是 InputChar 支持的字符（美式键盘布局）。
+var charKeys = map[rune]charKey{
+	' ': {KeySpace, false}, '\t': {KeyTab, false}, '\n': {KeyEnter, false},
+	',': {55, false}, '.': {56, false}, '`': {68, false}, '-': {69, false}, '=': {70, false},
+	'[': {71, false}, ']': {72, false}, '\\': {73, false}, ';': {74, false}, '\'': {75, false},
+	'/': {76, false}, '@': {77, false}, '+': {81, false}, '*': {17, false}, '#': {18, false},
+	'<': {55, true}, '>': {56, true}, '~': {68, true}, '_': {69, true},
+	'{': {71, true}, '}': {72, true}, '|': {73, true}, ':': {74, true}, '"': {75, true}, '?': {76, true},
+	'!': {8, true}, '$': {11, true}, '%': {12, true}, '^': {13, true}, '&': {14, true},
+	'(': {16, true}, ')': {7, true},
+}
+
+// lookupCharKey 返回输入字符 r 需要的按键。
+func lookupCharKey(r rune) (charKey, bool) {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return charKey{KeyCode(29 + r - 'a'), false}, true // KEYCODE_A = 29
+	case r >= 'A' && r <= 'Z':
+		return charKey{KeyCode(29 + r - 'A'), true}, true
+	case r >= '0' && r <= '9':
+		return charKey{KeyCode(7 + r - '0'), false}, true // KEYCODE_0 = 7
+	}
+	k, ok := charKeys[r]
+	return k, ok
+}
+
+// InputChar 通过按键事件输入单个字符。
+// 用于不接受 'input text' 和广播输入的控件（例如部分游戏、终端模拟器），这些控件只处理按键事件。
+//
+// 参数：
+//   - r: 要输入的字符，支持字母、数字、空格、Tab、换行和美式键盘上的常用标点
+//
+// 返回值：
+//   - error: 字符无法映射为按键时返回 error 对象；需要 Shift 但系统不支持组合键时包装 ErrUnsupported
+//
+// 工作原理：
+//   - 小写字母、数字和不需要 Shift 的标点直接发送 'input keyevent <code>'
+//   - 大写字母和需要 Shift 的符号发送 'input keycombination 59 <code>'（Shift + 按键）
+//
+// 兼容性：
+//   - 'input keycombination' 需要 Android 13+，更早的版本只能输入不需要 Shift 的字符
+//
+// 示例：
+//
+//	err := device.InputChar('a')
+func (d *Device) InputChar(r rune) error {
+	return d.InputChars(string(r))
+}
+
+// InputChars 通过按键事件逐个输入字符串中的字符，规则与 InputChar 相同。
+// 所有字符合并为一条 shell 命令发送，相邻的不需要 Shift 的字符合并为一条 input keyevent。
+//
+// 参数：
+//   - s: 要输入的字符串
+//
+// 返回值：
+//   - error: 如果有字符无法映射（此时不会输入任何字符）或发送失败，返回 error 对象
+//
+// 示例：
+//
+//	// 向终端模拟器输入命令
+//	err := device.InputChars("ls -la /sdcard\n")
+func (d *Device) InputChars(s string) error {
+	var cmds []string
+	var keys []KeyCode
+	needShift := false
+	for _, r := range s {
+		k, ok := lookupCharKey(r)
+		if !ok {
+			return fmt.Errorf("input char %q: no key mapping", r)
+		}
+		if !k.shift {
+			keys = append(keys, k.code)
+			continue
+		}
+		needShift = true
+		if len(keys) > 0 {
+			cmds = append(cmds, keyeventCommand(keys))
+			keys = nil
+		}
+		cmds = append(cmds, fmt.Sprintf("input keycombination %d %d", keyShiftLeft, k.code))
+	}
+	if len(keys) > 0 {
+		cmds = append(cmds, keyeventCommand(keys))
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	if needShift {
+		sdk, err := d.SDKLevel()
+		if err != nil {
+			return err
+		}
+		if sdk < 33 {
+			return fmt.Errorf("input shifted char on API %d: %w", sdk, ErrUnsupported)
+		}
+	}
+	_, err := d.Shell(strings.Join(cmds, "; "))
+	return err
+}
@@ -0,0 +1,44 @@
+package adb
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSendKeysSingleCommand(t *testing.T) {
+	d, r := newFakeDevice(nil)
+	if err := d.SendKeys(KeyDpadUp, KeyDpadUp, KeyDpadCenter); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"input keyevent 19 19 23"}
+	if got := r.shellCommands(); !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
+
+func TestSendSequenceSingleCommand(t *testing.T) {
+	d, r := newFakeDevice(nil)
+	if err := d.SendSequence("tab", "tab", "hello world", "text:enter", "enter"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"input keyevent 61 61; " +
+		"am broadcast -a ADB_INPUT_TEXT --es msg 'hello%sworld'; " +
+		"am broadcast -a ADB_INPUT_TEXT --es msg 'enter'; " +
+		"input keyevent 66"}
+	if got := r.shellCommands(); !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
+
+func TestSendSequenceQuotesText(t *testing.T) {
+	d, r := newFakeDevice(nil)
+	if err := d.SendSequence("it's", "enter", "a'; reboot; echo '"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`am broadcast -a ADB_INPUT_TEXT --es msg 'it'\''s'; ` +
+		`input keyevent 66; ` +
+		`am broadcast -a ADB_INPUT_TEXT --es msg 'a'\'';%sreboot;%secho%s'\'''`}
+	if got := r.shellCommands(); !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
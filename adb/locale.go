@@ -0,0 +1,227 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// localeRe 用于校验 BCP 47 语言标签，例如 "en"、"zh-CN"、"sr-Latn-RS"。
+var localeRe = regexp.MustCompile(`^[a-zA-Z]{2,8}(-[a-zA-Z0-9]{1,8})*$`)
+
+// appLocaleMinSDK 是 'cmd locale' 支持应用语言（per-app language）的最低 API 级别（Android 13）。
+const appLocaleMinSDK = 33
+
+// GetLocale 获取设备当前的系统语言（BCP 47 格式，例如 "zh-CN"）。
+//
+// 返回值：
+//   - string: 当前语言标签
+//   - error: 如果读取失败或无法确定语言，返回 error 对象
+//
+// 读取顺序：
+//  1. persist.sys.locale（Android 6.0+，用户设置过语言时存在）
+//  2. persist.sys.language + persist.sys.country（Android 5.x 及更早）
+//  3. ro.product.locale（出厂默认语言）
+//  4. ro.product.locale.language + ro.product.locale.region（更早的出厂属性）
+//
+// 示例：
+//
+//	locale, err := device.GetLocale()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println("当前语言:", locale)
+func (d *Device) GetLocale() (string, error) {
+	if locale, err := d.GetProp("persist.sys.locale"); err != nil || locale != "" {
+		return locale, err
+	}
+
+	// 旧版本将语言和地区分成两个属性保存
+	if locale, err := d.joinedLocale("persist.sys.language", "persist.sys.country"); err != nil || locale != "" {
+		return locale, err
+	}
+
+	if locale, err := d.GetProp("ro.product.locale"); err != nil || locale != "" {
+		return locale, err
+	}
+
+	locale, err := d.joinedLocale("ro.product.locale.language", "ro.product.locale.region")
+	if err != nil {
+		return "", err
+	}
+	if locale == "" {
+		return "", fmt.Errorf("locale not found")
+	}
+	return locale, nil
+}
+
+// joinedLocale 读取语言和地区两个属性并拼接为 "语言-地区" 格式。
+// 语言属性为空时返回空字符串。
+func (d *Device) joinedLocale(languageKey, regionKey string) (string, error) {
+	language, err := d.GetProp(languageKey)
+	if err != nil || language == "" {
+		return "", err
+	}
+	region, err := d.GetProp(regionKey)
+	if err != nil {
+		return "", err
+	}
+	if region == "" {
+		return language, nil
+	}
+	return language + "-" + region, nil
+}
+
+// SetLocale 切换设备的系统语言。
+// 修改系统语言需要 CHANGE_CONFIGURATION 权限，shell 用户在任何 API 级别下都没有可以直接修改系统语言的命令，
+// 因此该方法只能在 root 设备上使用，按 API 级别选择要写入的属性。
+//
+// 参数：
+//   - bcp47: BCP 47 语言标签，例如 "en-US"、"zh-CN"、"ja-JP"
+//
+// 返回值：
+//   - error: 如果标签格式错误或切换失败，返回 error 对象；
+//     设备未 root 时返回的错误包装了 ErrUnsupported，并注明设备的 API 级别
+//
+// 切换方式（需要 root）：
+//   - Android 6.0+（API 23+）：setprop persist.sys.locale，然后重启 zygote
+//   - Android 5.x 及更早：setprop persist.sys.language / persist.sys.country，然后重启 zygote
+//
+// 注意事项：
+//   - 会重启 zygote，设备会进行一次软重启，调用后需要等待系统重新就绪
+//   - am broadcast LOCALE_CHANGED 只通知应用语言已变化，并不会修改语言，因此不作为非 root 的替代方式
+//   - 非 root 设备上只测试单个应用时，Android 13+ 可以使用 SetAppLocale 切换应用语言
+//   - 切换成功后会记录原来的语言，调用 Cleanup 时恢复
+//
+// 示例：
+//
+//	err := device.SetLocale("en-US")
+//	if errors.Is(err, adb.ErrUnsupported) {
+//	    // 非 root 设备改为只切换被测应用的语言
+//	    err = device.SetAppLocale("com.example.app", "en-US")
+//	}
+func (d *Device) SetLocale(bcp47 string) error {
+	prev, _ := d.GetLocale()
+	if err := d.setLocale(bcp47); err != nil {
+		return err
+	}
+	if prev != "" && prev != bcp47 {
+		d.pushUndo("locale", func() error { return d.setLocale(prev) })
+	}
+	return nil
+}
+
+// setLocale 切换系统语言，不记录恢复操作。
+func (d *Device) setLocale(bcp47 string) error {
+	if !localeRe.MatchString(bcp47) {
+		return fmt.Errorf("bad locale %q", bcp47)
+	}
+
+	sdk, err := d.SDKLevel()
+	if err != nil {
+		return err
+	}
+	if root, _ := d.IsRoot(); !root {
+		return fmt.Errorf("set system locale on API %d: no shell command without root: %w", sdk, ErrUnsupported)
+	}
+
+	if sdk >= 23 {
+		_, err = d.Shell(fmt.Sprintf("setprop persist.sys.locale %s && setprop ctl.restart zygote", bcp47))
+		return err
+	}
+	// 旧版本只认语言和地区两个属性
+	parts := strings.SplitN(bcp47, "-", 2)
+	command := "setprop persist.sys.language " + parts[0]
+	if len(parts) == 2 {
+		command += " && setprop persist.sys.country " + parts[1]
+	}
+	_, err = d.Shell(command + " && setprop ctl.restart zygote")
+	return err
+}
+
+// AppLocale 获取应用单独设置的语言（Android 13+ 的应用语言），通过 'cmd locale get-app-locales' 读取。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//
+// 返回值：
+//   - string: 应用语言的 BCP 47 标签，多个语言以逗号分隔；应用跟随系统语言时返回空字符串
+//   - error: 如果读取失败返回 error 对象；API 33 以下的设备返回的错误包装了 ErrUnsupported
+func (d *Device) AppLocale(pkg string) (string, error) {
+	pkg = d.packageOr(pkg)
+	if err := d.requireAppLocale(); err != nil {
+		return "", err
+	}
+	// 输出格式：Locales for com.example.app for user 0 are [en-US,fr-FR]
+	output, err := d.Cmd("locale", "get-app-locales", pkg)
+	if err != nil {
+		return "", err
+	}
+	start, end := strings.LastIndex(output, "["), strings.LastIndex(output, "]")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("unexpected get-app-locales output: %s", truncate(output, 200))
+	}
+	return output[start+1 : end], nil
+}
+
+// SetAppLocale 切换单个应用的语言（Android 13+ 的应用语言），通过 'cmd locale set-app-locales' 实现，不需要 root。
+// 适用于只关心被测应用界面语言的本地化测试。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用默认包名
+//   - bcp47: BCP 47 语言标签，例如 "ja-JP"；为空字符串时恢复为跟随系统语言
+//
+// 返回值：
+//   - error: 如果标签格式错误或切换失败返回 error 对象；API 33 以下的设备返回的错误包装了 ErrUnsupported
+//
+// 注意事项：
+//   - 系统会重新创建应用正在显示的 Activity，之后需要等待界面刷新
+//   - 切换成功后会记录原来的应用语言，调用 Cleanup 时恢复
+//
+// 示例：
+//
+//	if err := device.SetAppLocale("com.example.app", "ja-JP"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer device.Cleanup()
+func (d *Device) SetAppLocale(pkg, bcp47 string) error {
+	pkg = d.packageOr(pkg)
+	if bcp47 != "" && !localeRe.MatchString(bcp47) {
+		return fmt.Errorf("bad locale %q", bcp47)
+	}
+	prev, err := d.AppLocale(pkg)
+	if err != nil {
+		return err
+	}
+	if err := d.setAppLocale(pkg, bcp47); err != nil {
+		return err
+	}
+	if prev != bcp47 {
+		d.pushUndo("app locale "+pkg, func() error { return d.setAppLocale(pkg, prev) })
+	}
+	return nil
+}
+
+// setAppLocale 切换应用语言，不记录恢复操作。
+func (d *Device) setAppLocale(pkg, locales string) error {
+	output, err := d.Cmd("locale", "set-app-locales", pkg, "--locales", locales)
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		return fmt.Errorf("set app locale %s: %s", pkg, truncate(output, 200))
+	}
+	return nil
+}
+
+// requireAppLocale 检查设备是否支持应用语言。
+func (d *Device) requireAppLocale() error {
+	sdk, err := d.SDKLevel()
+	if err != nil {
+		return err
+	}
+	if sdk < appLocaleMinSDK {
+		return fmt.Errorf("app locale on API %d (needs %d+): %w", sdk, appLocaleMinSDK, ErrUnsupported)
+	}
+	return nil
+}
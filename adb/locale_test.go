@@ -0,0 +1,91 @@
+package adb
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSetLocaleByAPILevel(t *testing.T) {
+	tests := []struct {
+		sdk  string
+		root bool
+		want string // 为空表示不应执行 setprop
+	}{
+		{"34", true, "setprop persist.sys.locale ja-JP && setprop ctl.restart zygote"},
+		{"21", true, "setprop persist.sys.language ja && setprop persist.sys.country JP && setprop ctl.restart zygote"},
+		{"34", false, ""},
+		{"21", false, ""},
+	}
+	for _, tt := range tests {
+		s := &propStore{root: tt.root, props: map[string]string{"ro.build.version.sdk": tt.sdk}}
+		d, r := newFakeDevice(s.respond)
+		err := d.SetLocale("ja-JP")
+
+		var setprops []string
+		for _, c := range r.shellCommands() {
+			if strings.HasPrefix(c, "setprop") {
+				setprops = append(setprops, c)
+			}
+		}
+		if tt.want == "" {
+			if !errors.Is(err, ErrUnsupported) || !strings.Contains(err.Error(), "API "+tt.sdk) {
+				t.Errorf("API %s without root: error = %v, want ErrUnsupported naming the API level", tt.sdk, err)
+			}
+			if len(setprops) != 0 {
+				t.Errorf("API %s without root ran %q", tt.sdk, setprops)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("API %s as root: %v", tt.sdk, err)
+		}
+		if !slices.Equal(setprops, []string{tt.want}) {
+			t.Errorf("API %s as root ran %q, want %q", tt.sdk, setprops, tt.want)
+		}
+	}
+
+	d, _ := newPropDevice(true)
+	if err := d.SetLocale("en_US; reboot"); err == nil {
+		t.Error("SetLocale accepted a malformed tag")
+	}
+}
+
+func TestSetAppLocale(t *testing.T) {
+	const get = "cmd 'locale' 'get-app-locales' 'com.example.app'"
+	s := &propStore{props: map[string]string{"ro.build.version.sdk": "34"}}
+	d, r := newFakeDevice(func(command string) (string, error) {
+		if command == get {
+			return "Locales for com.example.app for user 0 are []", nil
+		}
+		return s.respond(command)
+	})
+
+	if err := d.SetAppLocale("com.example.app", "ja-JP"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		get,
+		"cmd 'locale' 'set-app-locales' 'com.example.app' '--locales' 'ja-JP'",
+		"cmd 'locale' 'set-app-locales' 'com.example.app' '--locales' ''",
+	}
+	var got []string
+	for _, c := range r.shellCommands() {
+		if strings.HasPrefix(c, "cmd") {
+			got = append(got, c)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+
+	s.props["ro.build.version.sdk"] = "30"
+	d.RefreshProps()
+	if err := d.SetAppLocale("com.example.app", "ja-JP"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetAppLocale on API 30 = %v, want ErrUnsupported", err)
+	}
+}
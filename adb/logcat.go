@@ -0,0 +1,319 @@
+package adb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEntry 表示一条 logcat 日志（threadtime 格式）。
+type LogEntry struct {
+	Time    time.Time // 时间（logcat 不输出年份，按当前年份解析）
+	PID     int       // 进程 ID
+	TID     int       // 线程 ID
+	Level   string    // 级别：V、D、I、W、E、F
+	Tag     string    // 标签
+	Message string    // 日志内容
+}
+
+// LogcatOptions 是读取 logcat 时的可选参数，零值表示读取默认缓冲区的全部日志。
+type LogcatOptions struct {
+	Buffer  string   // 缓冲区，例如 "main"、"crash"、"all"，对应 -b；为空时使用默认缓冲区
+	Filters []string // 过滤表达式，例如 []string{"ActivityManager:I", "*:S"}，由 logcat 在设备端过滤
+	PID     int      // 只显示指定进程的日志，对应 --pid（Android 7.0+）
+	Grep    string   // 正则表达式，在本地对整行日志过滤
+}
+
+// args 将选项转换为 logcat 命令行参数（不含 -d 等模式参数）。
+func (o LogcatOptions) args() string {
+	args := []string{"-v", "threadtime"}
+	if o.Buffer != "" {
+		args = append(args, "-b", o.Buffer)
+	}
+	if o.PID > 0 {
+		args = append(args, "--pid", strconv.Itoa(o.PID))
+	}
+	// 过滤表达式中的 * 需要引用，避免被设备端 shell 展开
+	for _, f := range o.Filters {
+		args = append(args, shellQuote(f))
+	}
+	return strings.Join(args, " ")
+}
+
+// grep 按 Grep 选项过滤日志行，Grep 为空时返回原始行。
+func (o LogcatOptions) grep(lines []string) ([]string, error) {
+	if o.Grep == "" {
+		return lines, nil
+	}
+	re, err := regexp.Compile(o.Grep)
+	if err != nil {
+		return nil, fmt.Errorf("bad grep pattern: %w", err)
+	}
+	var out []string
+	for _, line := range lines {
+		if re.MatchString(line) {
+			out = append(out, line)
+		}
+	}
+	return out, nil
+}
+
+// logcatRe 匹配 threadtime 格式的日志行，例如：
+// "01-15 10:23:45.678  1234  5678 I ActivityManager: Start proc ..."
+var logcatRe = regexp.MustCompile(`^(\d\d-\d\d \d\d:\d\d:\d\d\.\d{3})\s+(\d+)\s+(\d+)\s+([VDIWEFA])\s+(.*?)\s*: (.*)$`)
+
+// parseLogLine 解析一行 threadtime 格式的日志。
+// 分隔行（"--------- beginning of main"）等非日志行返回 false。
+func parseLogLine(line string) (LogEntry, bool) {
+	m := logcatRe.FindStringSubmatch(strings.TrimRight(line, "\r"))
+	if m == nil {
+		return LogEntry{}, false
+	}
+	t, err := time.ParseInLocation("01-02 15:04:05.000", m[1], time.Local)
+	if err != nil {
+		return LogEntry{}, false
+	}
+	pid, _ := strconv.Atoi(m[2])
+	tid, _ := strconv.Atoi(m[3])
+	return LogEntry{
+		Time:    t.AddDate(time.Now().Year(), 0, 0),
+		PID:     pid,
+		TID:     tid,
+		Level:   m[4],
+		Tag:     m[5],
+		Message: m[6],
+	}, true
+}
+
+// LogcatClear 清空 logcat 缓冲区（logcat -c）。
+// 常见流程是 "清空日志 -> 执行测试场景 -> 读取日志"。
+//
+// 返回值：
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 示例：
+//
+//	device.LogcatClear()
+//	device.ClickNode("", "提交")
+//	entries, _ := device.LogcatDump(adb.LogcatOptions{Filters: []string{"*:E"}})
+func (d *Device) LogcatClear() error {
+	_, err := d.Shell("logcat -c")
+	return err
+}
+
+// LogcatDump 读取当前缓冲区中的日志（logcat -d）并解析为结构化的日志条目。
+//
+// 参数：
+//   - opts: 缓冲区、过滤表达式和 Grep 等选项
+//
+// 返回值：
+//   - []LogEntry: 解析后的日志条目，无法解析的行（例如分隔行）会被忽略
+//   - error: 如果命令执行失败或 Grep 表达式非法，返回 error 对象
+//
+// 注意事项：
+//   - 多行日志（例如异常堆栈）每一行都是独立的条目
+//
+// 示例：
+//
+//	entries, err := device.LogcatDump(adb.LogcatOptions{
+//	    Buffer: "crash",
+//	    Grep:   "com\\.example\\.app",
+//	})
+//	for _, e := range entries {
+//	    fmt.Printf("%s %s/%s: %s\n", e.Time.Format("15:04:05"), e.Level, e.Tag, e.Message)
+//	}
+func (d *Device) LogcatDump(opts LogcatOptions) ([]LogEntry, error) {
+	lines, err := d.logcatLines(opts)
+	if err != nil {
+		return nil, err
+	}
+	var entries []LogEntry
+	for _, line := range lines {
+		if e, ok := parseLogLine(line); ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// LogcatSave 读取当前缓冲区中的日志并原样写入本地文件，适合作为测试失败时的附件。
+//
+// 参数：
+//   - path: 本地文件路径，已存在时会被覆盖
+//   - opts: 与 LogcatDump 相同的选项
+//
+// 返回值：
+//   - error: 如果读取或写入失败，返回 error 对象
+//
+// 示例：
+//
+//	if testFailed {
+//	    device.LogcatSave("artifacts/logcat.txt", adb.LogcatOptions{Buffer: "all"})
+//	}
+func (d *Device) LogcatSave(path string, opts LogcatOptions) error {
+	lines, err := d.logcatLines(opts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// logcatLines 执行 logcat -d 并按 Grep 过滤，返回日志行。
+func (d *Device) logcatLines(opts LogcatOptions) ([]string, error) {
+	output, err := d.ShellRaw("logcat -d " + opts.args())
+	if err != nil {
+		return nil, err
+	}
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	return opts.grep(strings.Split(strings.TrimRight(output, "\n"), "\n"))
+}
+
+// LogcatStream 实时读取从现在开始产生的日志，通过通道逐条返回。
+//
+// 参数：
+//   - ctx: 控制读取时长，ctx 取消后结束 logcat 进程
+//   - opts: 缓冲区、过滤表达式、进程、Grep 等选项
+//
+// 返回值：
+//   - <-chan LogEntry: 日志通道，logcat 进程结束（包括 ctx 取消、设备断开）后关闭
+//   - error: 如果 Grep 不是合法的正则表达式或无法启动 adb 进程，返回 error 对象
+//
+// 注意事项：
+//   - 调用时先同步读取设备当前时间（毫秒精度），再通过 'logcat -T <该时间>' 读取此后的日志，
+//     不会返回缓冲区中的旧日志，也不会漏掉调用返回之后、logcat 进程启动之前产生的日志
+//   - 调用方需要持续读取通道，否则 logcat 的输出会被阻塞
+//   - 不经过 Runner，也不应用 WithTimeout 设置的超时
+//
+// 示例：
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	entries, err := device.LogcatStream(ctx, adb.LogcatOptions{Filters: []string{"OkHttp:D", "*:S"}})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for e := range entries {
+//	    fmt.Println(e.Tag, e.Message)
+//	}
+func (d *Device) LogcatStream(ctx context.Context, opts LogcatOptions) (<-chan LogEntry, error) {
+	var re *regexp.Regexp
+	if opts.Grep != "" {
+		var err error
+		if re, err = regexp.Compile(opts.Grep); err != nil {
+			return nil, fmt.Errorf("bad grep pattern: %w", err)
+		}
+	}
+
+	// 起始时间在返回之前从设备读取，避免本机与设备的时钟和时区差异；
+	// 如果在 logcat 启动时才计算，调用之后、进程启动之前产生的日志会被漏掉
+	since, err := d.logcatNow()
+	if err != nil {
+		return nil, err
+	}
+	command := "logcat -T " + shellQuote(since) + " " + opts.args()
+	cmd := d.commandContext(ctx, "shell", command)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	entries := make(chan LogEntry)
+	go func() {
+		defer close(entries)
+		defer cmd.Wait()
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if re != nil && !re.MatchString(line) {
+				continue
+			}
+			entry, ok := parseLogLine(line)
+			if !ok {
+				continue
+			}
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return entries, nil
+}
+
+// logcatNow 读取设备当前时间，格式为 'logcat -T' 使用的 "MM-DD hh:mm:ss.mmm"。
+// 旧版 toolbox 的 date 不支持 %N，此时毫秒部分为 000。
+func (d *Device) logcatNow() (string, error) {
+	output, err := d.Shell("date '+%m-%d %H:%M:%S.%N'")
+	if err != nil {
+		return "", err
+	}
+	stamp, frac, _ := strings.Cut(output, ".")
+	if len(stamp) != len("01-02 15:04:05") {
+		return "", fmt.Errorf("unexpected date output: %s", truncate(output, 200))
+	}
+	ms := "000"
+	if len(frac) >= 3 && strings.Trim(frac[:3], "0123456789") == "" {
+		ms = frac[:3]
+	}
+	return stamp + "." + ms, nil
+}
+
+// WaitForLogLine 等待第一条内容匹配正则表达式的新日志，用于确认界面操作在后台确实生效。
+//
+// 参数：
+//   - ctx: 可以提前取消等待
+//   - pattern: 匹配日志内容（Message）的正则表达式
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - LogEntry: 第一条匹配的日志
+//   - error: 如果 pattern 不是合法的正则表达式、超时或 ctx 被取消，返回 error 对象
+//
+// 注意事项：
+//   - 只匹配调用之后产生的日志，应在执行操作之前调用，或在另一个 goroutine 中提前开始等待
+//
+// 示例：
+//
+//	done := make(chan error, 1)
+//	go func() {
+//	    _, err := device.WaitForLogLine(context.Background(), `Upload finished: id=\d+`, 30*time.Second)
+//	    done <- err
+//	}()
+//	device.ClickButton("上传")
+//	if err := <-done; err != nil {
+//	    log.Fatal(err)
+//	}
+func (d *Device) WaitForLogLine(ctx context.Context, pattern string, timeout time.Duration) (LogEntry, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return LogEntry{}, fmt.Errorf("bad log pattern: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	entries, err := d.LogcatStream(ctx, LogcatOptions{})
+	if err != nil {
+		return LogEntry{}, err
+	}
+	for entry := range entries {
+		if re.MatchString(entry.Message) {
+			return entry, nil
+		}
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return LogEntry{}, fmt.Errorf("wait for log line %q: timeout after %s", pattern, timeout)
+	}
+	if ctx.Err() != nil {
+		return LogEntry{}, ctx.Err()
+	}
+	return LogEntry{}, fmt.Errorf("wait for log line %q: logcat exited", pattern)
+}
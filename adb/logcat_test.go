@@ -0,0 +1,26 @@
+package adb
+
+import "testing"
+
+func TestLogcatNow(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+		ok     bool
+	}{
+		{"10-15 09:30:12.123456789", "10-15 09:30:12.123", true},
+		{"10-15 09:30:12.%N", "10-15 09:30:12.000", true}, // toolbox date 不支持 %N
+		{"10-15 09:30:12.N", "10-15 09:30:12.000", true},
+		{"date: bad format", "", false},
+	}
+	for _, tt := range tests {
+		d, r := newFakeDevice(func(command string) (string, error) { return tt.output, nil })
+		got, err := d.logcatNow()
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("logcatNow() with %q = %q, %v; want %q", tt.output, got, err, tt.want)
+		}
+		if cmds := r.shellCommands(); len(cmds) != 1 || cmds[0] != "date '+%m-%d %H:%M:%S.%N'" {
+			t.Errorf("commands = %q", cmds)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// ByHint 返回匹配输入框提示文本（hint 属性）的查找函数。
+// 空的 EditText 通常没有 text，只能通过 hint 定位（需要 Android 8.0+ 的 dump 才包含该属性）。
+//
+// 参数：
+//   - s: 要匹配的提示文本（完全相等）
+//
+// 返回值：
+//   - FindNodeFunc: 可直接传给 FindNode / FindNodes 的查找函数
+//
+// 示例：
+//
+//	// 找到提示为 "请输入手机号" 的输入框并点击
+//	node, err := device.FindNode(adb.ByHint("请输入手机号"))
+//	if err == nil {
+//	    device.ClickNodeBy(node)
+//	}
+func ByHint(s string) FindNodeFunc {
+	return func(n, pn uixml.Node) bool {
+		return n.Hint == s
+	}
+}
+
+// ByResourceIDContains 返回 resource-id 包含指定子串的查找函数，
+// 用于 RecyclerView 列表项等带动态后缀的 ID（例如 "com.example:id/item_42"）。
+//
+// 参数：
+//   - substr: resource-id 中稳定的部分，例如 ":id/item_"
+//
+// 返回值：
+//   - FindNodeFunc: 可直接传给 FindNode / FindNodes 的查找函数
+//
+// 示例：
+//
+//	items, err := device.FindNodes(adb.ByResourceIDContains(":id/item_"))
+func ByResourceIDContains(substr string) FindNodeFunc {
+	return func(n, pn uixml.Node) bool {
+		return strings.Contains(n.ResourceID, substr)
+	}
+}
+
+// ByResourceIDRegex 返回 resource-id 匹配正则表达式的查找函数。
+//
+// 参数：
+//   - pattern: 正则表达式（Go regexp 语法），匹配 resource-id 的任意部分，需要完整匹配时加上 ^ 和 $
+//
+// 返回值：
+//   - FindNodeFunc: 可直接传给 FindNode / FindNodes 的查找函数
+//   - error: 如果正则表达式无法编译，返回 error 对象
+//
+// 示例：
+//
+//	fn, err := adb.ByResourceIDRegex(`:id/item_\d+$`)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	items, err := device.FindNodes(fn)
+func ByResourceIDRegex(pattern string) (FindNodeFunc, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compile resource-id pattern: %w", err)
+	}
+	return func(n, pn uixml.Node) bool {
+		return re.MatchString(n.ResourceID)
+	}, nil
+}
+
+// MatchType 是文本匹配的方式。
+type MatchType string
+
+const (
+	MatchExact    MatchType = "exact"    // 完全相等（默认）
+	MatchContains MatchType = "contains" // 包含
+	MatchPrefix   MatchType = "prefix"   // 以其开头
+	MatchSuffix   MatchType = "suffix"   // 以其结尾
+)
+
+// TextMatchOptions 是 ByTextMatch / FindByText 的匹配选项。
+// 零值表示去掉首尾空白后完全相等，区分大小写。
+type TextMatchOptions struct {
+	Match           MatchType // 匹配方式，为空时使用 MatchExact
+	IgnoreCase      bool      // 忽略大小写
+	NormalizeSpace  bool      // 把连续的空白（包括不换行空格 U+00A0）合并为一个空格
+	DisableTrimming bool      // 不去掉首尾空白（默认会去掉，包括不换行空格）
+}
+
+// normalize 按选项处理文本。
+func (o TextMatchOptions) normalize(s string) string {
+	if o.NormalizeSpace {
+		// strings.Fields 会把 U+00A0 等 Unicode 空白都当作分隔符
+		s = strings.Join(strings.Fields(s), " ")
+	} else if !o.DisableTrimming {
+		s = strings.TrimSpace(s)
+	}
+	if o.IgnoreCase {
+		s = strings.ToLower(s)
+	}
+	return s
+}
+
+// matches 判断节点文本是否与期望文本匹配，expected 需要已经过 normalize 处理。
+func (o TextMatchOptions) matches(actual, expected string) bool {
+	actual = o.normalize(actual)
+	switch o.Match {
+	case MatchContains:
+		return strings.Contains(actual, expected)
+	case MatchPrefix:
+		return strings.HasPrefix(actual, expected)
+	case MatchSuffix:
+		return strings.HasSuffix(actual, expected)
+	default:
+		return actual == expected
+	}
+}
+
+// ByTextMatch 返回按 text 或 content-desc 宽松匹配的查找函数，两者任一匹配即可。
+// 界面上的文本常带有尾随空格、不换行空格或大小写差异，完全相等的匹配（例如 ClickNode 的 desc）会莫名失败，
+// 此时使用该函数。
+//
+// 参数：
+//   - text: 期望的文本，按同样的选项处理后再比较
+//   - opts: 匹配选项，零值表示去掉首尾空白后完全相等
+//
+// 返回值：
+//   - FindNodeFunc: 可直接传给 FindNode / FindNodes 的查找函数
+//
+// 示例：
+//
+//	// 匹配 "Sign In "、"SIGN IN"、"Sign In" 等
+//	node, err := device.FindNode(adb.ByTextMatch("sign in", adb.TextMatchOptions{IgnoreCase: true, NormalizeSpace: true}))
+func ByTextMatch(text string, opts TextMatchOptions) FindNodeFunc {
+	expected := opts.normalize(text)
+	return func(n, pn uixml.Node) bool {
+		return opts.matches(n.Text, expected) || opts.matches(n.ContentDesc, expected)
+	}
+}
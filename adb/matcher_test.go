@@ -0,0 +1,98 @@
+package adb
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// indexedIDDump 是一个 RecyclerView 列表，列表项的 resource-id 带有序号后缀。
+func indexedIDDump() string {
+	return hierarchy(
+		`<node resource-id="com.example:id/list" class="androidx.recyclerview.widget.RecyclerView" bounds="[0,0][1080,2000]">`+
+			`<node resource-id="com.example:id/item_1" text="first" class="android.widget.TextView" bounds="[0,0][1080,100]" />`+
+			`<node resource-id="com.example:id/item_2" text="second" class="android.widget.TextView" bounds="[0,100][1080,200]" />`+
+			`<node resource-id="com.example:id/item_10" text="tenth" class="android.widget.TextView" bounds="[0,200][1080,300]" />`+
+			`<node resource-id="com.example:id/item_header" text="header" class="android.widget.TextView" bounds="[0,300][1080,400]" />`+
+			`</node>`,
+		`<node resource-id="com.other:id/item_1" text="other app" class="android.widget.TextView" bounds="[0,2000][1080,2100]" />`,
+	)
+}
+
+func texts(nodes []uixml.Node) []string {
+	var out []string
+	for _, n := range nodes {
+		out = append(out, n.Text)
+	}
+	return out
+}
+
+func TestByResourceIDContains(t *testing.T) {
+	x, err := uixml.NewXml(indexedIDDump())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := texts(x.FindAll(ByResourceIDContains("com.example:id/item_")))
+	if want := []string{"first", "second", "tenth", "header"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got = texts(x.FindAll(ByResourceIDContains(":id/item_1")))
+	if want := []string{"first", "tenth", "other app"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestByResourceIDRegex(t *testing.T) {
+	x, err := uixml.NewXml(indexedIDDump())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn, err := ByResourceIDRegex(`^com\.example:id/item_\d+$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := texts(x.FindAll(fn)), []string{"first", "second", "tenth"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := ByResourceIDRegex(`item_(\d+`); err == nil {
+		t.Error("ByResourceIDRegex accepted an invalid pattern")
+	}
+}
+
+func TestFindByIDContains(t *testing.T) {
+	d, _ := newFakeDevice(func(command string) (string, error) { return indexedIDDump(), nil })
+	node, err := d.FindByIDContains(":id/item_2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Text != "second" {
+		t.Errorf("got %q, want second", node.Text)
+	}
+	if _, err := d.FindByIDContains(":id/item_99"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindByIDContains(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestByHint(t *testing.T) {
+	x, err := uixml.LoadFile("uixml/testdata/android14_login.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := x.Find(ByHint("请输入手机号"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.ShortID() != "phone" {
+		t.Errorf("ByHint found %q, want the phone field", node.ResourceID)
+	}
+	// 提示文本需要完全相等
+	if _, err := x.Find(ByHint("请输入")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ByHint(prefix) error = %v, want ErrNotFound", err)
+	}
+	if got := len(x.FindAll(ByHint("Password"))); got != 1 {
+		t.Errorf("ByHint(Password) matched %d nodes, want 1", got)
+	}
+}
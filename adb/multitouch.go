@@ -0,0 +1,285 @@
+package adb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point 表示屏幕上的一个坐标点。
+type Point struct {
+	X, Y int
+}
+
+// touchDevice 描述支持多点触控的输入设备。
+type touchDevice struct {
+	path             string // 设备节点，例如 /dev/input/event2
+	minX, maxX       int    // ABS_MT_POSITION_X 的取值范围
+	minY, maxY       int    // ABS_MT_POSITION_Y 的取值范围
+	hasTrackingSlots bool   // 是否支持 ABS_MT_SLOT（B 类多点触控协议）
+	maxPressure      int    // ABS_MT_PRESSURE 的最大值，为 0 表示不支持压力
+	hasToolType      bool   // 是否支持 ABS_MT_TOOL_TYPE
+}
+
+// absRangeRe 匹配 'getevent -pl' 输出中的坐标轴范围，例如：
+// "ABS_MT_POSITION_X     : value 0, min 0, max 1079, fuzz 0, flat 0, resolution 0"
+var absRangeRe = regexp.MustCompile(`(ABS_MT_POSITION_X|ABS_MT_POSITION_Y)\s*: value -?\d+, min (-?\d+), max (-?\d+)`)
+
+// pressureRangeRe 匹配 'getevent -pl' 输出中的压力范围，例如：
+// "ABS_MT_PRESSURE       : value 0, min 0, max 255, fuzz 0, flat 0, resolution 0"
+var pressureRangeRe = regexp.MustCompile(`ABS_MT_PRESSURE\s*: value -?\d+, min -?\d+, max (\d+)`)
+
+// Linux 输入事件常量（linux/input-event-codes.h）
+const (
+	evSyn           = 0
+	evKey           = 1
+	evAbs           = 3
+	btnTouch        = 330 // BTN_TOUCH
+	absMTSlot       = 47  // ABS_MT_SLOT
+	absMTPositionX  = 53  // ABS_MT_POSITION_X
+	absMTPositionY  = 54  // ABS_MT_POSITION_Y
+	absMTToolType   = 55  // ABS_MT_TOOL_TYPE
+	absMTTrackingID = 57  // ABS_MT_TRACKING_ID
+	absMTPressure   = 58  // ABS_MT_PRESSURE
+	absMTTouchMajor = 48  // ABS_MT_TOUCH_MAJOR
+)
+
+// TapMultiple 多个手指同时点击屏幕，例如游戏中的双指操作或需要两指同时按下的手势。
+// 连续调用 Tap 无法做到多个触点同时按下，该方法直接向触摸屏设备写入多点触控事件。
+//
+// 参数：
+//   - points: 各个手指的坐标（竖屏/自然方向下的屏幕坐标），至少一个
+//
+// 返回值：
+//   - error: 如果坐标超出屏幕、找不到多点触控设备或写入事件失败，返回 error 对象；
+//     没有写入触摸屏的权限时错误包装了 ErrRootRequired
+//
+// 工作原理：
+//  1. 通过 'getevent -pl' 找到支持 ABS_MT_POSITION_X/Y 的触摸屏设备和坐标范围
+//  2. 将屏幕坐标换算为触摸屏坐标
+//  3. 通过 SendEvent 按 B 类多点触控协议依次写入所有触点的按下事件并同步，
+//     停留 50 毫秒后写入所有触点的抬起事件
+//
+// 注意事项：
+//   - 'input tap' 只能产生单个触点；Android 10 (API 29) 起的 'input motionevent DOWN|MOVE|UP x y'
+//     每次也只构造只有一个 pointer 的 MotionEvent，无法产生 ACTION_POINTER_DOWN，
+//     因此多指按下只能通过 sendevent 实现，没有基于 input 的替代方案
+//   - 需要 shell 用户对 /dev/input/eventX 有写权限（大多数设备和模拟器默认满足），否则需要 root
+//   - 坐标不受屏幕旋转影响，始终按自然方向理解
+//   - 所有事件在一条 shell 命令中写入，保证触点几乎同时按下
+//
+// 示例：
+//
+//	// 双指同时点击
+//	err := device.TapMultiple(adb.Point{X: 300, Y: 1200}, adb.Point{X: 780, Y: 1200})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (d *Device) TapMultiple(points ...Point) error {
+	if len(points) == 0 {
+		return fmt.Errorf("no point to tap")
+	}
+
+	w, h, err := d.ScreenSize()
+	if err != nil {
+		return err
+	}
+	for _, p := range points {
+		if p.X < 0 || p.Y < 0 || p.X >= w || p.Y >= h {
+			return fmt.Errorf("point (%d,%d) out of screen %dx%d", p.X, p.Y, w, h)
+		}
+	}
+
+	dev, err := d.touchDevice()
+	if err != nil {
+		return err
+	}
+	if !dev.hasTrackingSlots && len(points) > 1 {
+		return fmt.Errorf("touch device %s has no multitouch slots: %w", dev.path, ErrUnsupported)
+	}
+
+	// 所有触点按下
+	var events []InputEvent
+	for i, p := range points {
+		events = append(events,
+			InputEvent{Type: "EV_ABS", Code: "ABS_MT_SLOT", Value: int32(i)},
+			InputEvent{Type: "EV_ABS", Code: "ABS_MT_TRACKING_ID", Value: int32(i + 1)},
+			InputEvent{Type: "EV_ABS", Code: "ABS_MT_POSITION_X", Value: int32(scaleAxis(p.X, w, dev.minX, dev.maxX))},
+			InputEvent{Type: "EV_ABS", Code: "ABS_MT_POSITION_Y", Value: int32(scaleAxis(p.Y, h, dev.minY, dev.maxY))},
+		)
+	}
+	events = append(events,
+		InputEvent{Type: "EV_KEY", Code: "BTN_TOUCH", Value: 1},
+		InputEvent{Type: "EV_SYN", Code: "SYN_REPORT", Value: 0},
+	)
+
+	// 停留 50 毫秒后所有触点抬起（tracking id 为 -1 表示抬起）
+	for i := range points {
+		events = append(events,
+			InputEvent{Time: 50 * time.Millisecond, Type: "EV_ABS", Code: "ABS_MT_SLOT", Value: int32(i)},
+			InputEvent{Time: 50 * time.Millisecond, Type: "EV_ABS", Code: "ABS_MT_TRACKING_ID", Value: -1},
+		)
+	}
+	events = append(events,
+		InputEvent{Time: 50 * time.Millisecond, Type: "EV_KEY", Code: "BTN_TOUCH", Value: 0},
+		InputEvent{Time: 50 * time.Millisecond, Type: "EV_SYN", Code: "SYN_REPORT", Value: 0},
+	)
+	return d.SendEvent(dev.path, events)
+}
+
+// ToolType 是触摸事件的工具类型，对应 ABS_MT_TOOL_TYPE 的取值。
+type ToolType int
+
+// 工具类型（linux/input.h 中的 MT_TOOL_*）。
+const (
+	ToolFinger ToolType = 0 // 手指
+	ToolStylus ToolType = 1 // 触控笔（MT_TOOL_PEN）
+	ToolPalm   ToolType = 2 // 手掌
+)
+
+// fingerPressure 是 TapWithPressure 在 pressure 为 0 时使用的压力，接近普通手指点击。
+const fingerPressure = 0.5
+
+// TapWithPressure 带压力和工具类型点击屏幕，用于会检查 MotionEvent 压力或工具类型、
+// 忽略 'input tap' 合成点击的应用（例如手写板、部分游戏和风控严格的应用）。
+//
+// 参数：
+//   - x, y: 点击坐标（竖屏/自然方向下的屏幕坐标）
+//   - pressure: 压力，取值 0-1，按触摸屏的 ABS_MT_PRESSURE 范围换算；为 0 时使用 0.5，接近普通手指
+//   - tool: 工具类型，ToolFinger 为手指，ToolStylus 为触控笔
+//
+// 返回值：
+//   - error: 如果坐标超出屏幕、压力超出范围或点击失败，返回 error 对象
+//
+// 工作原理：
+//  1. 通过 'getevent -pl' 找到触摸屏设备，读取坐标和压力范围
+//  2. 使用 sendevent 写入按下事件，同时设置 ABS_MT_PRESSURE、ABS_MT_TOUCH_MAJOR 和 ABS_MT_TOOL_TYPE，
+//     短暂停留后写入抬起事件
+//  3. 找不到触摸屏设备或没有写入权限时，退回到普通的 'input tap'（压力和工具类型为系统默认值）
+//
+// 注意事项：
+//   - 需要 shell 用户对 /dev/input/eventX 有写权限（大多数设备和模拟器默认满足），否则需要 root
+//   - 触摸屏不支持 ABS_MT_PRESSURE 时压力被忽略，不支持 ABS_MT_TOOL_TYPE 时工具类型被忽略；
+//     很多触摸屏不上报触控笔类型，此时应用看到的仍是手指
+//   - 坐标不受屏幕旋转影响，始终按自然方向理解
+//
+// 兼容性：
+//   - sendevent 在所有 Android 版本可用；'input tap' 从 Android 4.3 起可用
+//
+// 示例：
+//
+//	// 模拟手指轻按
+//	err := device.TapWithPressure(540, 1200, 0, adb.ToolFinger)
+//
+//	// 模拟触控笔用力点击
+//	err = device.TapWithPressure(540, 1200, 0.9, adb.ToolStylus)
+func (d *Device) TapWithPressure(x, y int, pressure float64, tool ToolType) error {
+	if pressure < 0 || pressure > 1 {
+		return fmt.Errorf("pressure %.2f out of range 0-1", pressure)
+	}
+	if pressure == 0 {
+		pressure = fingerPressure
+	}
+	w, h, err := d.cachedScreenSize()
+	if err != nil {
+		return err
+	}
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return fmt.Errorf("point (%d,%d) out of screen %dx%d", x, y, w, h)
+	}
+
+	dev, err := d.touchDevice()
+	if err == nil {
+		err = d.sendPressureTap(dev, scaleAxis(x, w, dev.minX, dev.maxX), scaleAxis(y, h, dev.minY, dev.maxY), pressure, tool)
+	}
+	if err == nil || !(errors.Is(err, ErrNotFound) || errors.Is(err, ErrRootRequired)) {
+		return err
+	}
+
+	// 退回到 input tap，坐标需要转换到当前方向
+	rx, ry, err := d.RotatePoint(x, y)
+	if err != nil {
+		return err
+	}
+	return d.tap(rx, ry)
+}
+
+// sendPressureTap 向触摸屏写入一次带压力和工具类型的点击，x、y 为触摸屏原始坐标。
+func (d *Device) sendPressureTap(dev touchDevice, x, y int, pressure float64, tool ToolType) error {
+	events := []InputEvent{
+		{Type: "EV_ABS", Code: "ABS_MT_SLOT", Value: 0},
+		{Type: "EV_ABS", Code: "ABS_MT_TRACKING_ID", Value: 1},
+	}
+	if dev.hasToolType {
+		events = append(events, InputEvent{Type: "EV_ABS", Code: "ABS_MT_TOOL_TYPE", Value: int32(tool)})
+	}
+	events = append(events,
+		InputEvent{Type: "EV_ABS", Code: "ABS_MT_POSITION_X", Value: int32(x)},
+		InputEvent{Type: "EV_ABS", Code: "ABS_MT_POSITION_Y", Value: int32(y)},
+	)
+	if dev.maxPressure > 0 {
+		events = append(events,
+			InputEvent{Type: "EV_ABS", Code: "ABS_MT_PRESSURE", Value: int32(pressure * float64(dev.maxPressure))},
+			InputEvent{Type: "EV_ABS", Code: "ABS_MT_TOUCH_MAJOR", Value: int32(pressure * 10)},
+		)
+	}
+	events = append(events,
+		InputEvent{Type: "EV_KEY", Code: "BTN_TOUCH", Value: 1},
+		InputEvent{Type: "EV_SYN", Code: "SYN_REPORT", Value: 0},
+		// 抬起前停留 50 毫秒
+		InputEvent{Time: 50 * time.Millisecond, Type: "EV_ABS", Code: "ABS_MT_TRACKING_ID", Value: -1},
+		InputEvent{Time: 50 * time.Millisecond, Type: "EV_KEY", Code: "BTN_TOUCH", Value: 0},
+		InputEvent{Time: 50 * time.Millisecond, Type: "EV_SYN", Code: "SYN_REPORT", Value: 0},
+	)
+	return d.SendEvent(dev.path, events)
+}
+
+// touchDevice 查找支持多点触控坐标的输入设备。
+func (d *Device) touchDevice() (touchDevice, error) {
+	output, err := d.Shell("getevent -pl")
+	if err != nil {
+		return touchDevice{}, err
+	}
+
+	// 输出按设备分段，每段以 "add device N: /dev/input/eventX" 开头
+	for _, section := range strings.Split(output, "add device ")[1:] {
+		lines := strings.SplitN(section, "\n", 2)
+		i := strings.Index(lines[0], "/dev/")
+		if i < 0 || len(lines) < 2 {
+			continue
+		}
+		dev := touchDevice{path: strings.TrimSpace(lines[0][i:])}
+
+		matches := absRangeRe.FindAllStringSubmatch(lines[1], -1)
+		if len(matches) < 2 {
+			continue
+		}
+		for _, m := range matches {
+			min, _ := strconv.Atoi(m[2])
+			max, _ := strconv.Atoi(m[3])
+			if m[1] == "ABS_MT_POSITION_X" {
+				dev.minX, dev.maxX = min, max
+			} else {
+				dev.minY, dev.maxY = min, max
+			}
+		}
+		dev.hasTrackingSlots = strings.Contains(lines[1], "ABS_MT_SLOT")
+		dev.hasToolType = strings.Contains(lines[1], "ABS_MT_TOOL_TYPE")
+		if m := pressureRangeRe.FindStringSubmatch(lines[1]); m != nil {
+			dev.maxPressure, _ = strconv.Atoi(m[1])
+		}
+		return dev, nil
+	}
+	return touchDevice{}, fmt.Errorf("multitouch device: %w", ErrNotFound)
+}
+
+// scaleAxis 将屏幕坐标 v（0 到 size-1）换算为触摸屏坐标（min 到 max）。
+func scaleAxis(v, size, min, max int) int {
+	if size <= 1 {
+		return min
+	}
+	return min + v*(max-min)/(size-1)
+}
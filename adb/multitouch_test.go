@@ -0,0 +1,108 @@
+package adb
+
+import (
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// geteventPL 是 'getevent -pl' 的节选：event1 是按键，event2 是 0-4095 坐标范围的触摸屏。
+const geteventPL = `add device 1: /dev/input/event1
+  name:     "gpio-keys"
+  events:
+    KEY (0001): KEY_VOLUMEDOWN        KEY_VOLUMEUP          KEY_POWER
+  input props:
+    <none>
+add device 2: /dev/input/event2
+  name:     "synaptics_dsx"
+  events:
+    KEY (0001): BTN_TOUCH
+    ABS (0003): ABS_MT_SLOT           : value 0, min 0, max 9, fuzz 0, flat 0, resolution 0
+                ABS_MT_TOUCH_MAJOR    : value 0, min 0, max 255, fuzz 0, flat 0, resolution 0
+                ABS_MT_POSITION_X     : value 0, min 0, max 4095, fuzz 0, flat 0, resolution 0
+                ABS_MT_POSITION_Y     : value 0, min 0, max 4095, fuzz 0, flat 0, resolution 0
+                ABS_MT_TRACKING_ID    : value 0, min 0, max 65535, fuzz 0, flat 0, resolution 0
+                ABS_MT_PRESSURE       : value 0, min 0, max 255, fuzz 0, flat 0, resolution 0
+  input props:
+    INPUT_PROP_DIRECT
+`
+
+// touchscreenDevice 返回一台 1080x2400、带 geteventPL 中触摸屏的伪造设备，sendevent 的输出由 sendevent 决定。
+func touchscreenDevice(sendevent func(command string) (string, error)) (*Device, *fakeRunner) {
+	return newFakeDevice(func(command string) (string, error) {
+		switch {
+		case command == "wm size":
+			return "Physical size: 1080x2400", nil
+		case command == "getevent -pl":
+			return geteventPL, nil
+		case strings.Contains(command, "sendevent"):
+			return sendevent(command)
+		}
+		return "", nil
+	})
+}
+
+func TestTapMultiple(t *testing.T) {
+	d, r := touchscreenDevice(func(string) (string, error) { return "", nil })
+	if err := d.TapMultiple(Point{X: 0, Y: 0}, Point{X: 1079, Y: 2399}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range r.shellCommands() {
+		if strings.Contains(c, "sendevent") {
+			got = strings.Split(c, "; ")
+		}
+	}
+	const dev = "sendevent '/dev/input/event2' "
+	want := []string{
+		dev + "3 47 0", dev + "3 57 1", dev + "3 53 0", dev + "3 54 0",
+		dev + "3 47 1", dev + "3 57 2", dev + "3 53 4095", dev + "3 54 4095",
+		dev + "1 330 1", dev + "0 0 0",
+		sleepCommand(50 * time.Millisecond),
+		dev + "3 47 0", dev + "3 57 -1", dev + "3 47 1", dev + "3 57 -1",
+		dev + "1 330 0", dev + "0 0 0",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("events =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if err := d.TapMultiple(Point{X: 1080, Y: 0}); err == nil {
+		t.Error("TapMultiple accepted a point outside the screen")
+	}
+}
+
+func TestTapMultiplePermissionDenied(t *testing.T) {
+	d, _ := touchscreenDevice(func(string) (string, error) {
+		return "could not open /dev/input/event2, Permission denied", nil
+	})
+	if err := d.TapMultiple(Point{X: 100, Y: 100}, Point{X: 200, Y: 200}); !errors.Is(err, ErrRootRequired) {
+		t.Errorf("TapMultiple = %v, want ErrRootRequired", err)
+	}
+}
+
+func TestSleepCommandFallsBack(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("needs /bin/sh")
+	}
+	cmd := sleepCommand(20 * time.Millisecond)
+	if cmd != "(sleep 0.020 || usleep 20000) 2>/dev/null" {
+		t.Errorf("sleepCommand = %q", cmd)
+	}
+
+	// 模拟只接受整数秒的 toolbox sleep：失败时退回到 usleep，且不产生任何输出
+	script := `sleep() { echo "sleep: invalid number '$1'" >&2; return 1; }; usleep() { echo "usleep $1"; }; ` + cmd
+	out, err := exec.Command("sh", "-c", script).CombinedOutput()
+	if err != nil || string(out) != "usleep 20000\n" {
+		t.Errorf("toolbox fallback printed %q, %v", out, err)
+	}
+
+	// 两者都不可用时不暂停，也不输出错误
+	script = `sleep() { echo "sleep: invalid number '$1'" >&2; return 1; }; PATH=/nonexistent; ` + cmd
+	if out, _ := exec.Command("sh", "-c", script).CombinedOutput(); len(out) != 0 {
+		t.Errorf("missing usleep printed %q", out)
+	}
+}
@@ -0,0 +1,237 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Notification 表示通知栏中的一条通知。
+type Notification struct {
+	Package string // 发出通知的应用包名
+	ID      int    // 通知 ID
+	Key     string // 通知的唯一 key（旧版本 Android 没有该字段，为空）
+	Title   string // 标题（android.title）
+	Text    string // 正文（android.text）
+	Raw     string // 该条通知在 dumpsys 中的原始文本，字段未能解析时可以自行处理
+}
+
+var (
+	// notificationStartRe 匹配一条通知记录的开头。
+	// 新版本为 "NotificationRecord(0x0d4b3a4e: pkg=..."，旧版本为 "NotificationRecord{41e0c1a8 pkg=..."
+	notificationStartRe = regexp.MustCompile(`^\s*NotificationRecord[({]`)
+	notificationPkgRe   = regexp.MustCompile(`\bpkg=(\S+)`)
+	notificationIDRe    = regexp.MustCompile(`\bid=(-?\d+)`)
+	notificationKeyRe   = regexp.MustCompile(`\bkey=(\S+)`)
+	// notificationExtraRe 匹配 extras 中的标题和正文，例如 "android.title=String (Hello)"
+	notificationExtraRe = regexp.MustCompile(`^\s*android\.(title|text)=(.*)$`)
+	// notificationValueRe 匹配带类型前缀的值，例如 "String (Hello)"、"SpannableString (Hello)"
+	notificationValueRe = regexp.MustCompile(`^[A-Za-z]+ \((.*)\)$`)
+	// notificationRedactedRe 匹配被隐藏的值，例如 "String [length=5]"
+	notificationRedactedRe = regexp.MustCompile(`^[A-Za-z]+ \[length=\d+\]$`)
+)
+
+// Notifications 获取通知栏中当前的所有通知。
+// 通过 'dumpsys notification --noredact' 解析出包名、标题和正文。
+//
+// 返回值：
+//   - []Notification: 通知列表，顺序与 dumpsys 输出一致
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 兼容性：
+//   - Android 7.0+ 的记录以 "NotificationRecord(0x...: pkg=..." 开头，extras 值形如 "String (文本)"
+//   - 更早的版本以 "NotificationRecord{... pkg=..." 开头，部分版本的值没有类型前缀
+//   - 不支持 --noredact 的版本会把文本显示为 "String [length=N]"，此时 Title / Text 为空
+//   - 无论是否解析成功，Raw 字段都保存了原始文本
+//
+// 注意事项：
+//   - 只解析当前显示的通知，历史通知（mArchive）和已延后的通知会被忽略
+//
+// 示例：
+//
+//	notifications, err := device.Notifications()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, n := range notifications {
+//	    if n.Package == "com.example.app" && strings.Contains(n.Text, "验证码") {
+//	        fmt.Println("收到验证码通知:", n.Text)
+//	    }
+//	}
+func (d *Device) Notifications() ([]Notification, error) {
+	output, err := d.Shell("dumpsys notification --noredact")
+	if err != nil {
+		return nil, err
+	}
+	return parseNotifications(output), nil
+}
+
+// parseNotifications 从 dumpsys notification 的输出中解析通知列表。
+func parseNotifications(output string) []Notification {
+	var notifications []Notification
+	var block []string
+	indent := 0
+
+	flush := func() {
+		if len(block) > 0 {
+			notifications = append(notifications, parseNotification(block))
+			block = nil
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		// 历史记录和延后通知的段落中也可能出现通知信息，遇到后停止解析
+		if strings.HasPrefix(trimmed, "mArchive") || strings.HasPrefix(trimmed, "Snoozed") {
+			break
+		}
+		if notificationStartRe.MatchString(line) {
+			flush()
+			block = append(block, line)
+			indent = len(line) - len(strings.TrimLeft(line, " "))
+			continue
+		}
+		if len(block) > 0 {
+			// 缩进回到记录开头的层级，说明这条记录已经结束
+			if trimmed != "" && len(line)-len(strings.TrimLeft(line, " ")) <= indent {
+				flush()
+				continue
+			}
+			block = append(block, line)
+		}
+	}
+	flush()
+
+	return notifications
+}
+
+// parseNotification 解析单条通知记录。
+func parseNotification(lines []string) Notification {
+	n := Notification{Raw: strings.Join(lines, "\n")}
+
+	header := lines[0]
+	if m := notificationPkgRe.FindStringSubmatch(header); m != nil {
+		n.Package = m[1]
+	}
+	if m := notificationIDRe.FindStringSubmatch(header); m != nil {
+		n.ID, _ = strconv.Atoi(m[1])
+	}
+	if m := notificationKeyRe.FindStringSubmatch(n.Raw); m != nil {
+		n.Key = strings.TrimRight(m[1], ":)}")
+	}
+
+	for _, line := range lines[1:] {
+		m := notificationExtraRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value := notificationValue(m[2])
+		// 同一字段可能出现多次（例如 publicVersion 中），只取第一次
+		switch {
+		case m[1] == "title" && n.Title == "":
+			n.Title = value
+		case m[1] == "text" && n.Text == "":
+			n.Text = value
+		}
+	}
+	return n
+}
+
+// notificationValue 去掉 extras 值的类型前缀，被隐藏的值返回空字符串。
+func notificationValue(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "null" || notificationRedactedRe.MatchString(s) {
+		return ""
+	}
+	if m := notificationValueRe.FindStringSubmatch(s); m != nil {
+		return m[1]
+	}
+	return s
+}
+
+// ClearNotifications 清除通知栏中所有可清除的通知，效果等同于点击 "全部清除"。
+//
+// 返回值：
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - 通过 'service call notification 1' 调用 NotificationManager 的 cancelAllNotifications
+//   - 常驻通知（前台服务等）不会被清除
+//
+// 示例：
+//
+//	device.ClearNotifications()
+//	// 触发应用发送通知...
+//	notifications, _ := device.Notifications()
+func (d *Device) ClearNotifications() error {
+	output, err := d.Shell("service call notification 1")
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(output, "Result: Parcel") {
+		return fmt.Errorf("clear notifications failed: %s", output)
+	}
+	return nil
+}
+
+// ExpandNotifications 下拉展开通知栏。
+//
+// 返回值：
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - Android 7.0+ 使用 'cmd statusbar expand-notifications'
+//   - 更早的版本不支持 cmd statusbar，回退为 'service call statusbar 1'
+//
+// 示例：
+//
+//	device.ExpandNotifications()
+//	device.ClickNode("android.widget.TextView", "新消息") // 点击通知
+func (d *Device) ExpandNotifications() error {
+	output, err := d.Shell("cmd statusbar expand-notifications")
+	if err == nil && output == "" {
+		return nil
+	}
+
+	// 旧版本没有 cmd 命令或 statusbar 服务不支持 cmd
+	output, err = d.Shell("service call statusbar 1")
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(output, "Result: Parcel") {
+		return fmt.Errorf("expand notifications failed: %s", output)
+	}
+	return nil
+}
+
+// CollapseNotifications 收起通知栏和快捷设置面板，面板本就收起时不做任何事。
+//
+// 返回值：
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - Android 7.0+ 使用 'cmd statusbar collapse'
+//   - 更早的版本回退为 'service call statusbar 2'
+//
+// 示例：
+//
+//	device.ExpandNotifications()
+//	// 检查通知...
+//	device.CollapseNotifications()
+func (d *Device) CollapseNotifications() error {
+	output, err := d.Shell("cmd statusbar collapse")
+	if err == nil && output == "" {
+		return nil
+	}
+
+	output, err = d.Shell("service call statusbar 2")
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(output, "Result: Parcel") {
+		return fmt.Errorf("collapse notifications failed: %s", output)
+	}
+	return nil
+}
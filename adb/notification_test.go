@@ -0,0 +1,115 @@
+package adb
+
+import (
+	"strings"
+	"testing"
+)
+
+// notificationsAPI28 是 Android 9 上 dumpsys notification --noredact 的节选。
+const notificationsAPI28 = `Current Notification Manager state:
+  Notification List:
+    NotificationRecord(0x0b2a6c3e: pkg=com.example.chat user=UserHandle{0} id=7 tag=null importance=3 key=0|com.example.chat|7|null|10087: Notification(channel=messages pri=0 contentView=null vibrate=null sound=null defaults=0x0 flags=0x10 color=0x00000000 vis=PRIVATE))
+      uid=10087 userId=0
+      icon=Icon(typ=RESOURCE pkg=com.example.chat id=0x7f080063)
+      pri=0
+      key=0|com.example.chat|7|null|10087
+      seen=false
+      extras={
+        android.title=String (Alice)
+        android.text=String (Your code is 123456)
+        android.showWhen=Boolean (true)
+      }
+    NotificationRecord(0x09f1d2a7: pkg=android user=UserHandle{-1} id=17041083 tag=null importance=1 key=-1|android|17041083|null|1000: Notification(channel=DEVELOPER pri=-2 contentView=null vibrate=null sound=null defaults=0x0 flags=0x2 color=0xff607d8b vis=PUBLIC))
+      uid=1000 userId=-1
+      extras={
+        android.title=String (USB debugging connected)
+        android.text=null
+      }
+  mArchive (2 notifications):
+    NotificationRecord(0x01234567: pkg=com.example.old user=UserHandle{0} id=1 tag=null importance=3 key=0|com.example.old|1|null|10050: Notification(channel=default))
+      extras={
+        android.title=String (Archived)
+      }
+`
+
+// notificationsAPI33 是 Android 13 上的节选，包含 SpannableString、publicVersion 和延后通知。
+const notificationsAPI33 = `Current Notification Manager state:
+  Notification List:
+    NotificationRecord(0x0d4b3a4e: pkg=com.example.app user=UserHandle{0} id=1 tag=null importance=3 key=0|com.example.app|1|null|10123: Notification(channel=default shortcut=null contentView=null contentIntent=PendingIntent{1b2c3d4: PendingIntentRecord{5e6f708 com.example.app startActivity}} deleteIntent=null tickerText=null contentTitle=13 contentText=20 number=0 flags=0x10 color=0x00000000 vis=PRIVATE publicVersion=Notification(channel=null shortcut=null contentView=null contentIntent=null deleteIntent=null tickerText=null contentTitle=0 contentText=0 number=0 flags=0x0 color=0x00000000 vis=PRIVATE semFlags=0x0 semPriority=0 semMissedCount=0)))
+      uid=10123 userId=0
+      opPkg=com.example.app
+      icon=Icon(typ=RESOURCE pkg=com.example.app id=0x7f0800a1)
+      flags=AUTO_CANCEL
+      pri=0
+      key=0|com.example.app|1|null|10123
+      extras={
+          android.title=String (验证码)
+          android.reduced.images=Boolean (true)
+          android.text=SpannableString (Your code is 654321)
+          android.appInfo=ApplicationInfo (ApplicationInfo{4a5b6c7 com.example.app})
+      }
+      publicVersion.extras={
+          android.title=String (New message)
+      }
+    NotificationRecord(0x02c1f9e0: pkg=com.example.mail user=UserHandle{0} id=42 tag=inbox importance=4 key=0|com.example.mail|42|inbox|10130: Notification(channel=mail shortcut=null contentView=null contentIntent=null deleteIntent=null tickerText=null contentTitle=13 contentText=5 number=0 flags=0x0 color=0x00000000 vis=PRIVATE))
+      uid=10130 userId=0
+      extras={
+          android.title=String [length=13]
+          android.text=String [length=5]
+      }
+  mEnabledListeners:
+    ComponentInfo{com.android.systemui/com.android.systemui.statusbar.NotificationListener}
+  Snoozed notifications:
+    0:
+      com.example.snoozed:
+        0|com.example.snoozed|3|null|10140
+`
+
+// notificationsAPI23 是 Android 6.0 上的节选，记录以 "NotificationRecord{" 开头。
+const notificationsAPI23 = `Current Notification Manager state:
+  Notification List:
+    NotificationRecord{41e0c1a8 pkg=com.example.legacy user=UserHandle{0} id=3 tag=null score=0 key=0|com.example.legacy|3|null|10061: Notification(pri=0 contentView=com.example.legacy/0x1090077 vibrate=null sound=null defaults=0x0 flags=0x0 color=0x00000000 vis=PRIVATE)}
+      uid=10061 userId=0
+      extras={
+        android.title=String (Legacy title)
+        android.text=String (Legacy text)
+      }
+  mSoundNotification=null
+`
+
+func TestParseNotifications(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []Notification
+	}{
+		{"API 28", notificationsAPI28, []Notification{
+			{Package: "com.example.chat", ID: 7, Key: "0|com.example.chat|7|null|10087", Title: "Alice", Text: "Your code is 123456"},
+			{Package: "android", ID: 17041083, Key: "-1|android|17041083|null|1000", Title: "USB debugging connected"},
+		}},
+		{"API 33", notificationsAPI33, []Notification{
+			{Package: "com.example.app", ID: 1, Key: "0|com.example.app|1|null|10123", Title: "验证码", Text: "Your code is 654321"},
+			{Package: "com.example.mail", ID: 42, Key: "0|com.example.mail|42|inbox|10130"},
+		}},
+		{"API 23", notificationsAPI23, []Notification{
+			{Package: "com.example.legacy", ID: 3, Key: "0|com.example.legacy|3|null|10061", Title: "Legacy title", Text: "Legacy text"},
+		}},
+		{"empty", "Current Notification Manager state:\n  Notification List:\n", nil},
+	}
+	for _, tt := range tests {
+		got := parseNotifications(tt.output)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: parsed %d notifications, want %d: %+v", tt.name, len(got), len(tt.want), got)
+			continue
+		}
+		for i, n := range got {
+			if !strings.HasPrefix(n.Raw, "    NotificationRecord") || !strings.Contains(n.Raw, "extras={") {
+				t.Errorf("%s: notification %d Raw = %q", tt.name, i, n.Raw)
+			}
+			n.Raw = ""
+			if n != tt.want[i] {
+				t.Errorf("%s: notification %d = %+v, want %+v", tt.name, i, n, tt.want[i])
+			}
+		}
+	}
+}
@@ -1,7 +1,12 @@
 package adb
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/LucaHhx/adb/adb/uixml"
 )
@@ -58,6 +63,28 @@ func (d *Device) XML() (*uixml.Xml, error) {
 	return uixml.NewXml(data)
 }
 
+// SaveDump 把当前屏幕的 UI dump 原样保存到本地文件，用于在没有设备的情况下复现查找失败。
+// 保存的文件可以用 uixml.LoadFile 重新加载，或用 RunSelector 检查选择器。
+//
+// 参数：
+//   - path: 本地文件路径，例如 "artifacts/login_failed.xml"
+//
+// 返回值：
+//   - error: 如果获取 UI 结构或写文件失败，返回 error 对象
+//
+// 示例：
+//
+//	if _, err := device.FindNode(fn); err != nil {
+//	    device.SaveDump("artifacts/" + t.Name() + ".xml")
+//	}
+func (d *Device) SaveDump(path string) error {
+	data, err := d.UiautomatorDump()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(data), 0644)
+}
+
 // ClickButton 根据按钮的 content-desc 属性查找并点击按钮。
 // 该方法会自动查找可点击的按钮元素，并点击其中心位置。
 //
@@ -106,7 +133,7 @@ func (d *Device) ClickButton(name string) error {
 		return err
 	}
 	// 点击按钮的中心位置
-	return d.Tap(button.Middle())
+	return d.tap(button.Middle())
 }
 
 // ClickNodeBy 点击指定的 UI 节点对象。
@@ -151,7 +178,131 @@ func (d *Device) ClickButton(name string) error {
 //	}
 func (d *Device) ClickNodeBy(node uixml.Node) error {
 	// 点击节点的中心位置
-	return d.Tap(node.Middle())
+	return d.tap(node.Middle())
+}
+
+// LongClickNode 查找节点并在其中心长按，适用于 long-clickable 的列表项、图片等。
+//
+// 参数：
+//   - fn: 节点查找函数
+//   - duration: 按住的时间，为 0 时使用 800 毫秒
+//
+// 返回值：
+//   - error: 如果找不到节点、节点边界无效或长按失败，返回 error 对象
+//
+// 示例：
+//
+//	// 长按消息弹出菜单
+//	err := device.LongClickNode(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/message" && n.IsLongClickable()
+//	}, 0)
+func (d *Device) LongClickNode(fn FindNodeFunc, duration time.Duration) error {
+	node, err := d.FindNode(fn)
+	if err != nil {
+		return err
+	}
+	return d.LongClickNodeBy(node, duration)
+}
+
+// LongClickNodeBy 在已经找到的节点中心长按，与 ClickNodeBy 对应。
+func (d *Device) LongClickNodeBy(node uixml.Node, duration time.Duration) error {
+	x, y, err := nodeCenter(node)
+	if err != nil {
+		return err
+	}
+	return d.longPress(x, y, duration)
+}
+
+// DoubleClickNode 查找节点并在其中心双击，例如双击图片放大、双击点赞。
+//
+// 参数：
+//   - fn: 节点查找函数
+//
+// 返回值：
+//   - error: 如果找不到节点、节点边界无效或双击失败，返回 error 对象
+func (d *Device) DoubleClickNode(fn FindNodeFunc) error {
+	node, err := d.FindNode(fn)
+	if err != nil {
+		return err
+	}
+	return d.DoubleClickNodeBy(node)
+}
+
+// DoubleClickNodeBy 在已经找到的节点中心双击，与 ClickNodeBy 对应。
+func (d *Device) DoubleClickNodeBy(node uixml.Node) error {
+	x, y, err := nodeCenter(node)
+	if err != nil {
+		return err
+	}
+	return d.doubleTap(x, y)
+}
+
+// TapByTextClickable 点击包含指定文本的可点击区域。
+// 文本标签本身通常不可点击，可点击的是它所在的整行（父节点或更上层的节点）；
+// 直接点击文字中心只有在行的点击区域覆盖该位置时才有效，该方法改为点击最近的可点击祖先节点的中心。
+//
+// 参数：
+//   - text: 标签的文本，与节点的 text 或 content-desc 完全相等
+//
+// 返回值：
+//   - error: 如果找不到文本节点（错误包装了 ErrNotFound）、文本节点及其祖先都不可点击，
+//     或点击失败，返回 error 对象
+//
+// 注意事项：
+//   - 文本节点本身可点击时直接点击它
+//   - 受 ImplicitWait 影响
+//
+// 示例：
+//
+//	// 点击设置列表中 "通知" 所在的行
+//	if err := device.TapByTextClickable("通知"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (d *Device) TapByTextClickable(text string) error {
+	var target uixml.Node
+	err := d.implicitly(func() error {
+		xml, err := d.XML()
+		if err != nil {
+			return err
+		}
+		node, err := xml.Find(func(n, pn uixml.Node) bool {
+			return n.Text == text || n.ContentDesc == text
+		})
+		if err != nil {
+			return fmt.Errorf("find text %q: %w", text, err)
+		}
+		if node.IsClickable() {
+			target = node
+			return nil
+		}
+		for _, a := range xml.Ancestors(node) {
+			if a.IsClickable() {
+				target = a
+				return nil
+			}
+		}
+		return fmt.Errorf("no clickable ancestor for text %q", text)
+	})
+	if err != nil {
+		return err
+	}
+	x, y, err := nodeCenter(target)
+	if err != nil {
+		return err
+	}
+	return d.tap(x, y)
+}
+
+// nodeCenter 返回节点的中心坐标，边界无法解析或面积为 0 时返回 error 对象。
+func nodeCenter(node uixml.Node) (x, y int, err error) {
+	r, err := uixml.ParseBounds(node.Bounds)
+	if err != nil {
+		return 0, 0, err
+	}
+	if r.X2 <= r.X1 || r.Y2 <= r.Y1 {
+		return 0, 0, fmt.Errorf("node %s has empty bounds %s", node.Key(), node.Bounds)
+	}
+	return (r.X1 + r.X2) / 2, (r.Y1 + r.Y2) / 2, nil
 }
 
 // ClickNode 根据类名和描述/文本查找并点击 UI 节点。
@@ -220,7 +371,56 @@ func (d *Device) ClickNode(class, desc string) error {
 		return err
 	}
 	// 点击找到的节点
-	return d.Tap(node.Middle())
+	return d.tap(node.Middle())
+}
+
+// TapIfExists 节点存在时点击，不存在时什么也不做，用于 "有弹窗就关掉" 这类可选步骤。
+//
+// 参数：
+//   - fn: 节点查找函数
+//
+// 返回值：
+//   - bool: 找到并点击了节点返回 true，节点不存在返回 false
+//   - error: 只在获取 UI 结构失败或点击失败时返回 error 对象；节点不存在不是错误
+//
+// 注意事项：
+//   - 只检查当前界面一次，不受 ImplicitWait 影响，需要等待时使用 WaitForElement
+//
+// 示例：
+//
+//	// 有升级提示就点 "以后再说"
+//	tapped, err := device.TapIfExists(func(n, pn uixml.Node) bool {
+//	    return n.Text == "以后再说"
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if tapped {
+//	    log.Println("关闭了升级提示")
+//	}
+func (d *Device) TapIfExists(fn FindNodeFunc) (bool, error) {
+	node, err := d.findNode(fn)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := d.ClickNodeBy(node); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ClickByIDIfExists 指定 resource-id 的节点存在时点击，规则与 TapIfExists 相同。
+//
+// 示例：
+//
+//	device.ClickByIDIfExists("com.example:id/banner_close")
+func (d *Device) ClickByIDIfExists(resourceID string) (bool, error) {
+	return d.TapIfExists(func(n, pn uixml.Node) bool {
+		return n.ResourceID == resourceID
+	})
 }
 
 // FindNodeFunc 是用于查找 UI 节点的自定义条件函数类型。
@@ -276,6 +476,8 @@ type FindNodeFunc func(n, pn uixml.Node) bool
 //   - 每次调用都会重新获取屏幕 UI 结构
 //   - 只返回第一个匹配的节点
 //   - 如果没有匹配的节点，返回错误
+//   - 设置了 ImplicitWait 时，找不到节点会重新获取 UI 结构并重试，直到超过 ImplicitWait；
+//     WaitForElement 等显式等待方法不叠加这段时间，按各自的 timeout 轮询
 //
 // 示例：
 //
@@ -304,6 +506,16 @@ type FindNodeFunc func(n, pn uixml.Node) bool
 //	           n.Password == "false"
 //	})
 func (d *Device) FindNode(fn FindNodeFunc) (uixml.Node, error) {
+	var node uixml.Node
+	err := d.implicitly(func() (err error) {
+		node, err = d.findNode(fn)
+		return err
+	})
+	return node, err
+}
+
+// findNode 获取一次 UI 结构并查找节点，不受 ImplicitWait 影响。
+func (d *Device) findNode(fn FindNodeFunc) (uixml.Node, error) {
 	// 获取当前屏幕的 UI 结构
 	xml, err := d.XML()
 	if err != nil {
@@ -313,6 +525,18 @@ func (d *Device) FindNode(fn FindNodeFunc) (uixml.Node, error) {
 	return xml.Find(fn)
 }
 
+// implicitly 执行查找操作，设置了 ImplicitWait 且结果为 ErrNotFound 时轮询重试，直到超时。
+func (d *Device) implicitly(find func() error) error {
+	deadline := time.Now().Add(d.ImplicitWait)
+	for {
+		err := find()
+		if err == nil || d.ImplicitWait <= 0 || !errors.Is(err, ErrNotFound) || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
 // FindNodes 使用自定义条件函数查找所有匹配的 UI 节点。
 // 该方法遍历整个 UI 树，返回所有满足条件的节点列表。
 //
@@ -335,6 +559,7 @@ func (d *Device) FindNode(fn FindNodeFunc) (uixml.Node, error) {
 //   - 返回所有匹配的节点（可能很多）
 //   - 如果没有匹配的节点，返回错误（不是空数组）
 //   - 节点顺序与 UI 树遍历顺序相同
+//   - 设置了 ImplicitWait 时，一个都找不到会轮询重试，找到至少一个即返回
 //
 // 示例：
 //
@@ -371,18 +596,171 @@ func (d *Device) FindNode(fn FindNodeFunc) (uixml.Node, error) {
 //	    fmt.Printf("屏幕上有 %d 个非空文本元素\n", len(textViews))
 //	}
 func (d *Device) FindNodes(fn FindNodeFunc) ([]uixml.Node, error) {
-	// 获取当前屏幕的 UI 结构
+	var list []uixml.Node
+	err := d.implicitly(func() error {
+		// 获取当前屏幕的 UI 结构
+		xml, err := d.XML()
+		if err != nil {
+			return err
+		}
+		// 使用自定义条件查找所有匹配的节点
+		list = xml.FindAll(fn)
+		// 如果没有找到任何节点，返回错误
+		if len(list) == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// CountElements 统计当前屏幕上满足条件的节点数量，不构建节点列表。
+// 适合"列表是否为空""有多少项"这类只关心数量的检查。
+//
+// 参数：
+//   - fn: 自定义的节点匹配函数
+//
+// 返回值：
+//   - int: 匹配的节点数量，没有匹配时为 0（不返回 ErrNotFound）
+//   - error: 如果获取 UI 结构失败，返回 error 对象
+//
+// 示例：
+//
+//	n, err := device.CountElements(func(n, pn uixml.Node) bool {
+//	    return n.Class == "android.widget.CheckBox"
+//	})
+//	if err == nil && n == 0 {
+//	    fmt.Println("列表为空")
+//	}
+func (d *Device) CountElements(fn FindNodeFunc) (int, error) {
+	xml, err := d.XML()
+	if err != nil {
+		return 0, err
+	}
+	return xml.Count(fn), nil
+}
+
+// FindNodesByClass 查找所有类名为 class 的节点，相当于 FindNodes 配合按 Class 比较的查找函数。
+//
+// 参数：
+//   - class: 完整类名，例如 "android.widget.Button"
+//
+// 返回值：
+//   - []uixml.Node: 匹配的节点，按 UI 树遍历顺序排列
+//   - error: 如果没有匹配的节点（错误包装了 ErrNotFound）或获取 UI 失败，返回 error 对象
+func (d *Device) FindNodesByClass(class string) ([]uixml.Node, error) {
+	return d.FindNodes(func(n, pn uixml.Node) bool {
+		return n.Class == class
+	})
+}
+
+// ClassHistogram 统计当前屏幕上每个类名的节点数量，例如 "12 个 TextView、3 个 Button"，
+// 用于衡量界面复杂度或快速了解 "屏幕上有什么"。
+//
+// 返回值：
+//   - map[string]int: 类名到节点数量的映射（只 dump 一次）
+//   - error: 如果获取 UI 结构失败，返回 error 对象
+//
+// 示例：
+//
+//	hist, err := device.ClassHistogram()
+//	if err == nil {
+//	    for _, c := range adb.SortHistogram(hist) {
+//	        fmt.Printf("%4d %s\n", c.Count, c.Class)
+//	    }
+//	}
+func (d *Device) ClassHistogram() (map[string]int, error) {
 	xml, err := d.XML()
 	if err != nil {
 		return nil, err
 	}
-	// 使用自定义条件查找所有匹配的节点
-	list := xml.FindAll(fn)
-	// 如果没有找到任何节点，返回错误
-	if len(list) == 0 {
-		return nil, fmt.Errorf("not found")
+	hist := map[string]int{}
+	for _, node := range xml.Nodes {
+		uixml.Walk(node, uixml.Node{}, func(n, pn uixml.Node) {
+			if n.Class != "" {
+				hist[n.Class]++
+			}
+		})
 	}
-	return list, nil
+	return hist, nil
+}
+
+// ClassCount 是 SortHistogram 返回的一项。
+type ClassCount struct {
+	Class string
+	Count int
+}
+
+// SortHistogram 把 ClassHistogram 的结果按数量从多到少排序，数量相同时按类名排序，保证输出顺序稳定。
+func SortHistogram(hist map[string]int) []ClassCount {
+	counts := make([]ClassCount, 0, len(hist))
+	for class, n := range hist {
+		counts = append(counts, ClassCount{Class: class, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Class < counts[j].Class
+	})
+	return counts
+}
+
+// FindChildren 查找第一个匹配 parent 的节点，返回它的直接子节点，便于按下标操作列表项。
+//
+// 参数：
+//   - parent: 父节点（例如 RecyclerView、ListView）的匹配函数
+//
+// 返回值：
+//   - []uixml.Node: 父节点的直接子节点，顺序与界面上的顺序一致
+//   - error: 如果父节点不存在，返回 ErrNotFound
+//
+// 注意事项：
+//   - 只返回直接子节点，需要在父节点的整个子树中查找时使用 uixml.Xml.FindIn
+//
+// 示例：
+//
+//	items, err := device.FindChildren(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/list"
+//	})
+//	if err == nil && len(items) > 1 {
+//	    device.ClickNodeBy(items[1]) // 点击第二项
+//	}
+func (d *Device) FindChildren(parent FindNodeFunc) ([]uixml.Node, error) {
+	node, err := d.FindNode(parent)
+	if err != nil {
+		return nil, err
+	}
+	return node.Children, nil
+}
+
+// FindNodeByIndex 返回第一个匹配 parent 的节点的第 index 个直接子节点（从 0 开始）。
+//
+// 参数：
+//   - parent: 父节点的匹配函数
+//   - index: 子节点下标
+//
+// 返回值：
+//   - uixml.Node: 对应的子节点
+//   - error: 如果父节点不存在或下标越界，返回的错误包装了 ErrNotFound
+//
+// 示例：
+//
+//	item, err := device.FindNodeByIndex(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/list"
+//	}, 1)
+func (d *Device) FindNodeByIndex(parent FindNodeFunc, index int) (uixml.Node, error) {
+	children, err := d.FindChildren(parent)
+	if err != nil {
+		return uixml.Node{}, err
+	}
+	if index < 0 || index >= len(children) {
+		return uixml.Node{}, fmt.Errorf("child %d of %d: %w", index, len(children), ErrNotFound)
+	}
+	return children[index], nil
 }
 
 // FindButton 根据 content-desc 查找可点击的按钮节点。
@@ -448,3 +826,213 @@ func (d *Device) FindButton(name string) (uixml.Node, error) {
 		return n.ContentDesc == name && n.Clickable == "true"
 	})
 }
+
+// FindByText 按 text 或 content-desc 宽松匹配查找节点，解决界面上明明有该按钮却因为
+// 尾随空格、不换行空格或大小写差异而找不到的问题。
+//
+// 参数：
+//   - text: 期望的文本
+//   - opts: 匹配选项，零值表示去掉首尾空白后完全相等、区分大小写
+//     可以设置 IgnoreCase（忽略大小写）、NormalizeSpace（合并连续空白）、DisableTrimming（保留首尾空白）
+//     以及 Match（MatchExact / MatchContains / MatchPrefix / MatchSuffix）
+//
+// 返回值：
+//   - uixml.Node: 第一个匹配的节点
+//   - error: 如果没有匹配的节点（错误包装了 ErrNotFound）或获取 UI 失败，返回 error 对象
+//
+// 示例：
+//
+//	// 匹配 "Sign In "、"sign  in" 等
+//	node, err := device.FindByText("Sign In", adb.TextMatchOptions{IgnoreCase: true, NormalizeSpace: true})
+//	if err == nil {
+//	    device.ClickNodeBy(node)
+//	}
+//
+//	// 匹配以 "共 " 开头的统计文本
+//	node, err = device.FindByText("共", adb.TextMatchOptions{Match: adb.MatchPrefix})
+func (d *Device) FindByText(text string, opts TextMatchOptions) (uixml.Node, error) {
+	node, err := d.FindNode(ByTextMatch(text, opts))
+	if err != nil {
+		return uixml.Node{}, fmt.Errorf("find text %q: %w", text, err)
+	}
+	return node, nil
+}
+
+// FindByIDContains 查找 resource-id 包含指定子串的第一个节点，相当于 FindNode(ByResourceIDContains(substr))。
+//
+// 参数：
+//   - substr: resource-id 中稳定的部分，例如 ":id/item_"
+//
+// 返回值：
+//   - uixml.Node: 第一个匹配的节点
+//   - error: 如果没有匹配的节点（错误包装了 ErrNotFound）或获取 UI 失败，返回 error 对象
+func (d *Device) FindByIDContains(substr string) (uixml.Node, error) {
+	node, err := d.FindNode(ByResourceIDContains(substr))
+	if err != nil {
+		return uixml.Node{}, fmt.Errorf("find resource-id containing %q: %w", substr, err)
+	}
+	return node, nil
+}
+
+// NodeAt 返回覆盖屏幕坐标 (x, y) 的最内层节点（包含该点且面积最小的节点），规则见 uixml.Xml.NodeAt。
+// 用于把 OCR、图像识别得到的坐标对应到 UI 元素，或排查 Tap 实际点到了哪个元素。
+//
+// 参数：
+//   - x, y: 屏幕坐标；设置了 RotateCoords 时与 Tap 一样按竖屏坐标解释，否则为当前方向的坐标
+//
+// 返回值：
+//   - uixml.Node: 最内层的节点
+//   - error: 如果没有节点包含该点（错误包装了 ErrNotFound）或获取 UI 失败，返回 error 对象
+//
+// 示例：
+//
+//	node, err := device.NodeAt(540, 1200)
+//	if err == nil {
+//	    fmt.Println("该位置是:", node.Class, node.ResourceID, node.Text)
+//	}
+func (d *Device) NodeAt(x, y int) (uixml.Node, error) {
+	if d.RotateCoords {
+		var err error
+		if x, y, err = d.RotatePoint(x, y); err != nil {
+			return uixml.Node{}, err
+		}
+	}
+	xml, err := d.XML()
+	if err != nil {
+		return uixml.Node{}, err
+	}
+	node, ok := xml.NodeAt(x, y)
+	if !ok {
+		return uixml.Node{}, fmt.Errorf("node at (%d, %d): %w", x, y, ErrNotFound)
+	}
+	return node, nil
+}
+
+// Paste 通过剪贴板把文本粘贴到指定输入框。
+// 粘贴可以绕开输入法和 'input text' 对 Unicode 字符的限制，是输入中文、emoji 等内容的可靠方式。
+//
+// 参数：
+//   - node: 目标输入框节点（通常通过 FindNode 获取）
+//   - text: 要粘贴的文本
+//
+// 返回值：
+//   - error: 如果设置剪贴板、点击或粘贴失败，返回 error 对象
+//     粘贴后输入框中找不到该文本时，返回 "paste not supported" 错误
+//
+// 工作原理：
+//  1. 通过 SetClipboard 写入剪贴板
+//  2. 点击输入框中心使其获得焦点
+//  3. 发送 KEYCODE_PASTE（279）
+//  4. 重新 dump 屏幕，确认获得焦点的节点中包含该文本
+//
+// 注意事项：
+//   - 部分输入框（例如禁止粘贴的密码框）不响应粘贴，此时会返回错误
+//   - 密码框的 text 会被掩码，验证会失败，请改用其他输入方式
+//
+// 示例：
+//
+//	node, err := device.FindNode(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/comment"
+//	})
+//	if err == nil {
+//	    err = device.Paste(node, "很棒 👍")
+//	}
+func (d *Device) Paste(node uixml.Node, text string) error {
+	if err := d.SetClipboard(text); err != nil {
+		return err
+	}
+
+	// SetClipboard 会把 Clipper 切到前台，返回原界面
+	if err := d.PressBack(); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	if err := d.tap(node.Middle()); err != nil {
+		return err
+	}
+	time.Sleep(300 * time.Millisecond)
+	if err := d.KeyEvent(279); err != nil { // KEYCODE_PASTE
+		return err
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	// 验证获得焦点的输入框中已经包含粘贴的文本
+	focused, err := d.FindNode(func(n, pn uixml.Node) bool {
+		return n.Focused == "true"
+	})
+	if err != nil {
+		return fmt.Errorf("paste not supported by field: no focused node")
+	}
+	if !strings.Contains(focused.Text, text) {
+		return fmt.Errorf("paste not supported by field: got %q", focused.Text)
+	}
+	return nil
+}
+
+// GetText 读取匹配节点的文本，Text 为空时返回 ContentDesc。
+// 用于读取屏幕上显示的单个值，例如余额、订单号。
+//
+// 参数：
+//   - fn: 节点查找函数
+//
+// 返回值：
+//   - string: 节点的文本
+//   - error: 没有匹配的节点时返回 ErrNotFound
+//
+// 示例：
+//
+//	balance, err := device.GetText(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/balance"
+//	})
+//	if errors.Is(err, adb.ErrNotFound) {
+//	    log.Fatal("余额未显示")
+//	}
+func (d *Device) GetText(fn FindNodeFunc) (string, error) {
+	node, err := d.FindNode(fn)
+	if err != nil {
+		return "", err
+	}
+	if node.Text != "" {
+		return node.Text, nil
+	}
+	return node.ContentDesc, nil
+}
+
+// GetDesc 读取匹配节点的 ContentDesc，为空时返回 Text。
+// 与 GetText 相反，适用于图标按钮等主要信息放在 content-desc 中的元素。
+//
+// 参数：
+//   - fn: 节点查找函数
+//
+// 返回值：
+//   - string: 节点的描述
+//   - error: 没有匹配的节点时返回 ErrNotFound
+func (d *Device) GetDesc(fn FindNodeFunc) (string, error) {
+	node, err := d.FindNode(fn)
+	if err != nil {
+		return "", err
+	}
+	if node.ContentDesc != "" {
+		return node.ContentDesc, nil
+	}
+	return node.Text, nil
+}
+
+// GetTextByID 按 resource-id 读取节点的文本，规则与 GetText 相同。
+//
+// 参数：
+//   - id: 完整的资源 ID（"com.example:id/balance"）或简短 ID（"balance"）
+//
+// 返回值：
+//   - string: 节点的文本
+//   - error: 没有匹配的节点时返回 ErrNotFound
+//
+// 示例：
+//
+//	orderNo, err := device.GetTextByID("order_number")
+func (d *Device) GetTextByID(id string) (string, error) {
+	return d.GetText(func(n, pn uixml.Node) bool {
+		return n.ResourceID == id || (n.ResourceID != "" && n.ShortID() == id)
+	})
+}
@@ -0,0 +1,121 @@
+package adb
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// appearsOnDump 返回一台伪造的设备：第 n 次 dump 开始界面上才出现 "登录" 按钮。
+// dumps 记录 dump 的次数。
+func appearsOnDump(n int) (d *Device, r *fakeRunner, dumps *int) {
+	dumps = new(int)
+	d, r = newFakeDevice(func(command string) (string, error) {
+		if command != dumpCommand {
+			return "", nil
+		}
+		*dumps++
+		if *dumps < n {
+			return hierarchy(`<node text="加载中" class="android.widget.ProgressBar" bounds="[0,0][100,100]" />`), nil
+		}
+		return hierarchy(`<node text="" content-desc="登录" class="android.widget.Button" clickable="true" bounds="[100,200][300,400]" />`), nil
+	})
+	return d, r, dumps
+}
+
+var loginButton FindNodeFunc = func(n, pn uixml.Node) bool { return n.ContentDesc == "登录" }
+
+func TestImplicitWaitZero(t *testing.T) {
+	d, _, dumps := appearsOnDump(3)
+	if _, err := d.FindNode(loginButton); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindNode() error = %v, want ErrNotFound", err)
+	}
+	if _, err := d.FindNodes(loginButton); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindNodes() error = %v, want ErrNotFound", err)
+	}
+	// 不设置 ImplicitWait 时每次查找只 dump 一次
+	if *dumps != 2 {
+		t.Errorf("dumped %d times, want 2", *dumps)
+	}
+}
+
+func TestImplicitWaitFindsOnThirdDump(t *testing.T) {
+	d, _, dumps := appearsOnDump(3)
+	d.ImplicitWait = 5 * time.Second
+	node, err := d.FindNode(loginButton)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.ContentDesc != "登录" || *dumps != 3 {
+		t.Errorf("found %q after %d dumps, want 登录 after 3", node.ContentDesc, *dumps)
+	}
+
+	d, _, dumps = appearsOnDump(3)
+	d.ImplicitWait = 5 * time.Second
+	if nodes, err := d.FindNodes(loginButton); err != nil || len(nodes) != 1 || *dumps != 3 {
+		t.Errorf("FindNodes() = %d nodes, %v after %d dumps", len(nodes), err, *dumps)
+	}
+
+	d, r, dumps := appearsOnDump(3)
+	d.ImplicitWait = 5 * time.Second
+	if err := d.ClickButton("登录"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(r.shellCommands(), "input tap 200 300") || *dumps != 3 {
+		t.Errorf("commands = %q after %d dumps, want a tap on the button after 3", r.shellCommands(), *dumps)
+	}
+}
+
+func TestImplicitWaitTimeout(t *testing.T) {
+	d, _, dumps := appearsOnDump(1000)
+	d.ImplicitWait = 20 * time.Millisecond
+	start := time.Now()
+	if _, err := d.FindNode(loginButton); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindNode() error = %v, want ErrNotFound", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || *dumps < 2 {
+		t.Errorf("gave up after %s and %d dumps, want at least 20ms and several dumps", elapsed, *dumps)
+	}
+}
+
+func TestImplicitWaitStopsOnOtherErrors(t *testing.T) {
+	calls := 0
+	d, _ := newFakeDevice(func(command string) (string, error) {
+		calls++
+		return "", errors.New("device offline")
+	})
+	d.ImplicitWait = 5 * time.Second
+	if _, err := d.FindNode(loginButton); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("FindNode() error = %v, want the dump error", err)
+	}
+	if calls != 1 {
+		t.Errorf("retried %d times after a non-ErrNotFound error", calls-1)
+	}
+}
+
+func TestNodeAtOverlapping(t *testing.T) {
+	d, _ := newFakeDevice(func(command string) (string, error) {
+		return hierarchy(
+			`<node class="android.widget.FrameLayout" bounds="[0,0][1080,2400]">` +
+				`<node class="android.widget.TextView" text="背景" bounds="[0,0][1080,1200]" />` +
+				`<node class="android.widget.LinearLayout" text="弹窗" bounds="[100,500][980,1500]">` +
+				`<node class="android.widget.Button" text="确定" bounds="[600,1300][900,1450]" />` +
+				`</node></node>`,
+		), nil
+	})
+	for _, tt := range []struct {
+		x, y int
+		want string
+	}{{50, 50, "背景"}, {500, 700, "弹窗"}, {700, 1400, "确定"}} {
+		node, err := d.NodeAt(tt.x, tt.y)
+		if err != nil || node.Text != tt.want {
+			t.Errorf("NodeAt(%d, %d) = %q, %v; want %q", tt.x, tt.y, node.Text, err, tt.want)
+		}
+	}
+	if _, err := d.NodeAt(2000, 3000); !errors.Is(err, ErrNotFound) {
+		t.Errorf("NodeAt(outside) error = %v, want ErrNotFound", err)
+	}
+}
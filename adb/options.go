@@ -0,0 +1,190 @@
+package adb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// Option 是创建 Device 时的可选配置，配合 NewDeviceWithOptions 使用。
+//
+// 示例：
+//
+//	device := adb.NewDeviceWithOptions(
+//	    adb.WithSerial("emulator-5554"),
+//	    adb.WithTimeout(30*time.Second),
+//	    adb.WithAdbPath("/opt/android-sdk/platform-tools/adb"),
+//	)
+type Option func(*Device)
+
+// Runner 负责执行 adb 命令。默认实现直接启动本地进程，
+// 可以替换为远程执行、记录命令或在测试中返回预设输出的实现。
+//
+// 参数：
+//   - ctx: 设置了 WithTimeout 时带有超时
+//   - name: adb 可执行文件路径
+//   - args: 完整的命令参数（已包含 "-s serial"）
+//   - stdin: 命令的标准输入，可能为 nil
+//
+// 返回值：
+//   - stdout, stderr: 命令的标准输出和标准错误
+//   - err: 命令启动失败或以非 0 状态退出时返回 error 对象
+type Runner interface {
+	Run(ctx context.Context, name string, args []string, stdin io.Reader) (stdout, stderr []byte, err error)
+}
+
+// execRunner 是默认的 Runner，通过 os/exec 执行本地进程。
+type execRunner struct{}
+
+// Run 执行本地进程，ctx 取消时结束进程。
+func (execRunner) Run(ctx context.Context, name string, args []string, stdin io.Reader) ([]byte, []byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// NewDeviceWithOptions 使用函数式选项创建 Device 实例。
+// 不传任何选项时等同于 NewDevice()。
+//
+// 参数：
+//   - opts: 可选配置，例如 WithSerial()、WithTimeout()
+//
+// 返回值：
+//   - *Device: 新创建的设备实例指针
+//
+// 示例：
+//
+//	device := adb.NewDeviceWithOptions(
+//	    adb.WithSerial("192.168.1.100:5555"),
+//	    adb.WithTimeout(20*time.Second),
+//	    adb.WithDefaultPackage("com.example.app"),
+//	)
+func NewDeviceWithOptions(opts ...Option) *Device {
+	d := &Device{}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// WithSerial 返回一个使用相同配置、但指向另一台设备的新实例，用于在多台设备上执行相同的操作。
+//
+// 参数：
+//   - serial: 新实例的设备序列号
+//
+// 返回值：
+//   - *Device: 新的设备实例，原实例不受影响
+//
+// 注意事项：
+//   - 复制 adb 路径、超时、Runner、默认包名、RotateCoords、ImplicitWait 等配置
+//   - 屏幕尺寸、API 级别等缓存属于具体设备，不会复制，新实例会重新查询
+//
+// 示例：
+//
+//	base := adb.NewDeviceWithOptions(adb.WithTimeout(30*time.Second), adb.WithDefaultPackage("com.example.app"))
+//	for _, serial := range []string{"emulator-5554", "emulator-5556"} {
+//	    out, err := base.WithSerial(serial).Shell("getprop ro.product.model")
+//	    fmt.Println(serial, out, err)
+//	}
+func (d *Device) WithSerial(serial string) *Device {
+	return &Device{
+		Serial:         serial,
+		RotateCoords:   d.RotateCoords,
+		ImplicitWait:   d.ImplicitWait,
+		adbPath:        d.adbPath,
+		timeout:        d.timeout,
+		runner:         d.runner,
+		defaultPackage: d.defaultPackage,
+		storageCheck:   d.storageCheck,
+		pollInterval:   d.pollInterval,
+	}
+}
+
+// withTimeout 返回使用另一个命令超时时间的副本，用于 ShellTimeout 等单次调用的超时覆盖。
+// timeout 不大于 0 时直接返回 d（使用设备默认的超时）。
+// 副本复制了 d 的查询结果缓存，但 Cleanup 的恢复操作栈不共享，因此只能用于不修改设备设置的操作。
+func (d *Device) withTimeout(timeout time.Duration) *Device {
+	if timeout <= 0 {
+		return d
+	}
+	c := d.WithSerial(d.Serial)
+	c.timeout = timeout
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c.screenW, c.screenH = d.screenW, d.screenH
+	c.sdk = d.sdk
+	c.props = d.props
+	return c
+}
+
+// WithSerial 指定设备序列号，效果与 NewDevice(serial) 相同。
+func WithSerial(serial string) Option {
+	return func(d *Device) { d.Serial = serial }
+}
+
+// WithTimeout 设置单条 adb 命令的超时时间，超时后结束 adb 进程并返回错误。
+// 不设置时命令没有超时限制。
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *Device) { d.timeout = timeout }
+}
+
+// WithAdbPath 指定 adb 可执行文件路径，适用于 adb 不在 PATH 中或需要使用特定版本的情况。
+func WithAdbPath(path string) Option {
+	return func(d *Device) { d.adbPath = path }
+}
+
+// WithRunner 替换命令执行器，详见 Runner。
+func WithRunner(runner Runner) Option {
+	return func(d *Device) { d.runner = runner }
+}
+
+// WithDefaultPackage 设置默认应用包名。
+// 设置后，StartActivity、ForceStopApp、MemInfo、CPUInfo 等方法的包名参数传空字符串时使用该包名。
+func WithDefaultPackage(pkg string) Option {
+	return func(d *Device) { d.defaultPackage = pkg }
+}
+
+// WithRotateCoords 设置 RotateCoords 字段，详见 RotatePoint。
+func WithRotateCoords(on bool) Option {
+	return func(d *Device) { d.RotateCoords = on }
+}
+
+// WithStorageCheck 开启传输前的存储空间检查。
+// 开启后，Push 和 InstallAPK / InstallMultiple 会先用 DiskUsage 检查目标分区的可用空间，
+// 不足以容纳本地文件时立即返回包装了 ErrInsufficientStorage 的错误，而不是传输到一半才失败。
+// 每次传输会多执行一到两条 df 命令。
+func WithStorageCheck() Option {
+	return func(d *Device) { d.storageCheck = true }
+}
+
+// WithImplicitWait 设置 ImplicitWait 字段，查找节点失败时最多等待 d 后再返回 ErrNotFound。
+func WithImplicitWait(d time.Duration) Option {
+	return func(dev *Device) { dev.ImplicitWait = d }
+}
+
+// WithPollInterval 设置 WaitForElement、WaitForScreenState、ImplicitWait 等轮询等待类方法
+// 两次检查之间的间隔，默认 500 毫秒。每次检查都要执行 dump 或 dumpsys，
+// 在较慢的设备或同时控制很多设备时可以适当调大。
+func WithPollInterval(interval time.Duration) Option {
+	return func(d *Device) { d.pollInterval = interval }
+}
+
+// DefaultPackage 返回通过 WithDefaultPackage 设置的默认应用包名。
+func (d *Device) DefaultPackage() string {
+	return d.defaultPackage
+}
+
+// packageOr 在 pkg 为空时返回默认应用包名。
+func (d *Device) packageOr(pkg string) string {
+	if pkg == "" {
+		return d.defaultPackage
+	}
+	return pkg
+}
@@ -0,0 +1,185 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDeviceSerial(t *testing.T) {
+	if d := NewDevice(); d.Serial != "" {
+		t.Errorf("NewDevice().Serial = %q", d.Serial)
+	}
+	if d := NewDevice("emulator-5554"); d.Serial != "emulator-5554" {
+		t.Errorf("NewDevice(serial).Serial = %q", d.Serial)
+	}
+}
+
+func TestWithSerial(t *testing.T) {
+	r := &fakeRunner{}
+	d := NewDeviceWithOptions(WithSerial("emulator-5554"), WithRunner(r))
+	if _, err := d.Shell("true"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"-s", "emulator-5554", "shell", "true"}; !slices.Equal(r.calls[0], want) {
+		t.Errorf("args = %q, want %q", r.calls[0], want)
+	}
+}
+
+func TestWithRunnerAndAdbPath(t *testing.T) {
+	r := &fakeRunner{}
+	d := NewDeviceWithOptions(WithRunner(r))
+	d.Shell("true")
+	if r.count() != 1 || r.name != "adb" {
+		t.Errorf("runner called %d times with %q, want once with adb", r.count(), r.name)
+	}
+
+	d = NewDeviceWithOptions(WithRunner(r), WithAdbPath("/opt/platform-tools/adb"))
+	d.Shell("true")
+	if r.name != "/opt/platform-tools/adb" {
+		t.Errorf("adb path = %q, want /opt/platform-tools/adb", r.name)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	var deadline bool
+	r := &fakeRunner{respond: func(ctx context.Context, args []string) (string, error) {
+		_, deadline = ctx.Deadline()
+		<-ctx.Done()
+		return "", ctx.Err()
+	}}
+	d := NewDeviceWithOptions(WithRunner(r), WithTimeout(10*time.Millisecond))
+	start := time.Now()
+	_, err := d.Shell("sleep 10")
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timeout after 10ms") {
+		t.Errorf("Shell() error = %v, want timeout after 10ms", err)
+	}
+	if !deadline || time.Since(start) > 5*time.Second {
+		t.Errorf("deadline set %v, took %s", deadline, time.Since(start))
+	}
+}
+
+func TestShellTimeoutOverridesDefault(t *testing.T) {
+	var remaining time.Duration
+	r := &fakeRunner{respond: func(ctx context.Context, args []string) (string, error) {
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		if slices.Contains(args, "true") {
+			return "", nil
+		}
+		<-ctx.Done()
+		return "", ctx.Err()
+	}}
+	d := NewDeviceWithOptions(WithRunner(r), WithTimeout(time.Hour))
+
+	start := time.Now()
+	_, err := d.ShellTimeout("sleep 10", 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timeout after 10ms") {
+		t.Errorf("ShellTimeout() error = %v, want timeout after 10ms", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("ShellTimeout() took %s", time.Since(start))
+	}
+	if err := d.PullTimeout("/sdcard/big.bin", filepath.Join(t.TempDir(), "big.bin"), 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PullTimeout() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// 单次调用的超时不会修改设备的默认超时
+	if _, err := d.Shell("true"); err != nil {
+		t.Fatal(err)
+	}
+	if remaining < time.Minute {
+		t.Errorf("Shell() after ShellTimeout ran with %s left, want the device default of 1h", remaining)
+	}
+	// timeout <= 0 时沿用设备默认超时
+	if _, err := d.ShellTimeout("true", 0); err != nil || remaining < time.Minute {
+		t.Errorf("ShellTimeout(0) ran with %s left, err %v; want the device default", remaining, err)
+	}
+}
+
+func TestWithDefaultPackage(t *testing.T) {
+	d, r := newFakeDevice(nil)
+	WithDefaultPackage("com.example.app")(d)
+	if d.DefaultPackage() != "com.example.app" {
+		t.Errorf("DefaultPackage() = %q", d.DefaultPackage())
+	}
+	if err := d.ForceStopApp(""); err != nil {
+		t.Fatal(err)
+	}
+	cmds := r.shellCommands()
+	if last := cmds[len(cmds)-1]; !strings.HasSuffix(last, "force-stop com.example.app") {
+		t.Errorf("ForceStopApp(\"\") ran %q, want the default package", last)
+	}
+}
+
+func TestWithRotateCoords(t *testing.T) {
+	d, r := newFakeDevice(func(command string) (string, error) {
+		switch command {
+		case "wm size":
+			return "Physical size: 1080x2400", nil
+		case dumpCommand:
+			return strings.Replace(hierarchy(), `rotation="0"`, `rotation="1"`, 1), nil
+		}
+		return "", nil
+	})
+	WithRotateCoords(true)(d)
+	if err := d.Tap(100, 200); err != nil {
+		t.Fatal(err)
+	}
+	if cmds := r.shellCommands(); !slices.Contains(cmds, "input tap 200 980") {
+		t.Errorf("commands = %q, want the rotated tap", cmds)
+	}
+}
+
+func TestWithStorageCheck(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(local, make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+	df := func(command string) (string, error) {
+		if strings.HasPrefix(command, "df") {
+			return "Filesystem 1B-blocks Used Available Use% Mounted on\n/dev/block/dm-5 8192 6144 2048 75% /data", nil
+		}
+		return "", nil
+	}
+
+	d, _ := newFakeDevice(df)
+	if err := d.Push(local, "/data/local/tmp/big.bin"); err != nil {
+		t.Errorf("Push without storage check = %v", err)
+	}
+	WithStorageCheck()(d)
+	if err := d.Push(local, "/data/local/tmp/big.bin"); !errors.Is(err, ErrInsufficientStorage) {
+		t.Errorf("Push with storage check = %v, want ErrInsufficientStorage", err)
+	}
+}
+
+func TestWithImplicitWaitAndPollInterval(t *testing.T) {
+	d := NewDeviceWithOptions(WithImplicitWait(2*time.Second), WithPollInterval(50*time.Millisecond))
+	if d.ImplicitWait != 2*time.Second {
+		t.Errorf("ImplicitWait = %s", d.ImplicitWait)
+	}
+	if d.pollEvery() != 50*time.Millisecond {
+		t.Errorf("pollEvery() = %s", d.pollEvery())
+	}
+	if NewDevice().pollEvery() != defaultPollInterval {
+		t.Errorf("default pollEvery() = %s", NewDevice().pollEvery())
+	}
+}
+
+func TestDeviceWithSerialCopiesOptions(t *testing.T) {
+	r := &fakeRunner{}
+	d := NewDeviceWithOptions(WithRunner(r), WithAdbPath("/opt/adb"), WithTimeout(time.Second),
+		WithDefaultPackage("com.example.app"), WithRotateCoords(true), WithStorageCheck(),
+		WithImplicitWait(time.Second), WithPollInterval(time.Millisecond))
+	c := d.WithSerial("emulator-5556")
+	if c.Serial != "emulator-5556" || c.runner != d.runner || c.adbPath != d.adbPath || c.timeout != d.timeout ||
+		c.defaultPackage != d.defaultPackage || c.RotateCoords != d.RotateCoords || c.storageCheck != d.storageCheck ||
+		c.ImplicitWait != d.ImplicitWait || c.pollInterval != d.pollInterval {
+		t.Errorf("WithSerial copy = %+v, want the options of %+v", c, d)
+	}
+}
@@ -0,0 +1,291 @@
+package adb
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MemInfo 表示应用的内存占用，数值单位均为 KB。
+// 各字段对应 'dumpsys meminfo <包名>' 中 App Summary 部分的 PSS 值。
+type MemInfo struct {
+	TotalPSS     int // 总 PSS
+	JavaHeap     int // Java 堆
+	NativeHeap   int // Native 堆
+	Code         int // 代码（dex、so、资源等映射）
+	Stack        int // 线程栈
+	Graphics     int // 图形缓冲区（GL、Gfx）
+	PrivateOther int // 其他私有内存
+	System       int // 系统共享部分
+}
+
+var (
+	// memTotalRe 匹配表格中的 "TOTAL    48123 ..."，以及 App Summary 中的
+	// "TOTAL PSS:    48123"（Android 10+）或 "TOTAL:    48123"（Android 6.0-9）
+	memTotalRe = regexp.MustCompile(`(?m)^\s*TOTAL(?: PSS)?:?\s+(\d+)`)
+	// memSummaryRe 匹配 App Summary 中的各项，例如 "Java Heap:     6512"
+	memSummaryRe = regexp.MustCompile(`(?m)^\s*(Java Heap|Native Heap|Code|Stack|Graphics|Private Other|System):\s+(\d+)`)
+	// memTableRe 匹配旧版（Android 6.0 以前）没有 App Summary 时表格中的堆内存行
+	memTableRe = regexp.MustCompile(`(?m)^\s*(Dalvik Heap|Native Heap)\s+(\d+)`)
+)
+
+// MemInfo 获取应用当前的内存占用。
+//
+// 参数：
+//   - pkg: 应用包名，为空时使用 WithDefaultPackage 设置的默认包名
+//
+// 返回值：
+//   - MemInfo: 内存占用（KB）
+//   - error: 如果应用未运行（错误包装了 ErrNotRunning）或输出无法解析，返回 error 对象
+//
+// 兼容性：
+//   - Android 6.0+ 从 App Summary 读取所有字段
+//   - 更早的版本没有 App Summary，只能得到 TotalPSS、JavaHeap（Dalvik Heap）和 NativeHeap
+//
+// 示例：
+//
+//	info, err := device.MemInfo("com.example.app")
+//	if errors.Is(err, adb.ErrNotRunning) {
+//	    fmt.Println("应用未运行")
+//	} else if err == nil {
+//	    fmt.Printf("PSS: %d KB, Java: %d KB, Native: %d KB\n", info.TotalPSS, info.JavaHeap, info.NativeHeap)
+//	}
+func (d *Device) MemInfo(pkg string) (MemInfo, error) {
+	pkg = d.packageOr(pkg)
+	output, err := d.Shellf("dumpsys meminfo %s", pkg)
+	if err != nil {
+		return MemInfo{}, err
+	}
+	if strings.Contains(output, "No process found") {
+		return MemInfo{}, fmt.Errorf("meminfo %s: %w", pkg, ErrNotRunning)
+	}
+	return parseMemInfo(output)
+}
+
+// parseMemInfo 解析 dumpsys meminfo 的输出。
+func parseMemInfo(output string) (MemInfo, error) {
+	var info MemInfo
+	m := memTotalRe.FindStringSubmatch(output)
+	if m == nil {
+		return info, fmt.Errorf("unexpected meminfo output: %s", truncate(output, 200))
+	}
+	info.TotalPSS, _ = strconv.Atoi(m[1])
+
+	summary := memSummaryRe.FindAllStringSubmatch(output, -1)
+	if len(summary) == 0 {
+		// 旧版本只有表格，第一列数字为 Pss Total
+		for _, m := range memTableRe.FindAllStringSubmatch(output, -1) {
+			value, _ := strconv.Atoi(m[2])
+			if m[1] == "Dalvik Heap" {
+				info.JavaHeap = value
+			} else {
+				info.NativeHeap = value
+			}
+		}
+		return info, nil
+	}
+
+	for _, m := range summary {
+		value, _ := strconv.Atoi(m[2])
+		switch m[1] {
+		case "Java Heap":
+			info.JavaHeap = value
+		case "Native Heap":
+			info.NativeHeap = value
+		case "Code":
+			info.Code = value
+		case "Stack":
+			info.Stack = value
+		case "Graphics":
+			info.Graphics = value
+		case "Private Other":
+			info.PrivateOther = value
+		case "System":
+			info.System = value
+		}
+	}
+	return info, nil
+}
+
+// cpuSampleInterval 是 CPUInfo 两次采样之间的间隔。
+const cpuSampleInterval = time.Second
+
+// CPUInfo 获取应用在一段时间内的 CPU 占用率。
+// 通过两次读取 /proc/stat 和 /proc/<pid>/stat，计算采样间隔内应用进程使用的 CPU 时间占比。
+//
+// 参数：
+//   - pkg: 应用包名（主进程名），为空时使用默认包名
+//
+// 返回值：
+//   - float64: CPU 占用率（百分比），相对于整机所有核心的总 CPU 时间，取值 0-100
+//   - error: 如果应用未运行（错误包装了 ErrNotRunning）或读取失败，返回 error 对象
+//
+// 注意事项：
+//   - 调用会阻塞约 1 秒（采样间隔）
+//   - 与 top 不同，结果不乘以核心数：8 核设备上单线程跑满约为 12.5%
+//   - 应用有多个同名进程时累加所有进程
+//
+// 示例：
+//
+//	usage, err := device.CPUInfo("com.example.app")
+//	if err == nil {
+//	    fmt.Printf("CPU: %.1f%%\n", usage)
+//	}
+func (d *Device) CPUInfo(pkg string) (float64, error) {
+	pkg = d.packageOr(pkg)
+	pids, err := d.PidOf(pkg)
+	if err != nil {
+		return 0, err
+	}
+
+	total1, proc1, err := d.cpuTimes(pids)
+	if err != nil {
+		return 0, err
+	}
+	time.Sleep(cpuSampleInterval)
+	total2, proc2, err := d.cpuTimes(pids)
+	if err != nil {
+		return 0, err
+	}
+
+	if total2 <= total1 {
+		return 0, nil
+	}
+	return float64(proc2-proc1) / float64(total2-total1) * 100, nil
+}
+
+// cpuTimes 读取整机的总 CPU 时间和指定进程的 CPU 时间（单位：jiffies）。
+// 两者在同一条命令中读取，保证采样时刻一致。
+func (d *Device) cpuTimes(pids []int) (total, proc int64, err error) {
+	files := make([]string, len(pids))
+	for i, pid := range pids {
+		files[i] = fmt.Sprintf("/proc/%d/stat", pid)
+	}
+	output, err := d.Shell("head -n 1 /proc/stat; cat " + strings.Join(files, " "))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lines := strings.Split(output, "\n")
+	// 第一行："cpu  user nice system idle iowait irq softirq steal ..."
+	fields := strings.Fields(lines[0])
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("unexpected /proc/stat output: %s", lines[0])
+	}
+	for _, f := range fields[1:] {
+		v, _ := strconv.ParseInt(f, 10, 64)
+		total += v
+	}
+
+	found := false
+	for _, line := range lines[1:] {
+		// 进程名可能包含空格，从最后一个 ')' 之后开始按空格分割
+		i := strings.LastIndex(line, ")")
+		if i < 0 {
+			continue
+		}
+		fields := strings.Fields(line[i+1:])
+		// 第 14、15 个字段为 utime、stime，去掉 pid 和进程名后的下标为 11、12
+		if len(fields) < 13 {
+			continue
+		}
+		utime, _ := strconv.ParseInt(fields[11], 10, 64)
+		stime, _ := strconv.ParseInt(fields[12], 10, 64)
+		proc += utime + stime
+		found = true
+	}
+	if !found {
+		return 0, 0, fmt.Errorf("read cpu time: %w", ErrNotRunning)
+	}
+	return total, proc, nil
+}
+
+// heapDumpTimeout 是 DumpHeap 等待堆转储文件写完的最长时间。
+const heapDumpTimeout = 2 * time.Minute
+
+// DumpHeap 生成应用的 Java 堆转储（hprof）并拉取到本地，用于分析内存泄漏。
+//
+// 参数：
+//   - pkg: 应用包名（需要正在运行），为空时使用默认包名
+//   - localHprofPath: 本地保存路径，例如 "artifacts/app.hprof"
+//
+// 返回值：
+//   - error: 如果应用未运行（错误包装了 ErrNotRunning）、应用不可调试且没有 root
+//     （错误包装了 ErrRootRequired）、超时或拉取失败，返回 error 对象
+//
+// 工作原理：
+//  1. 执行 'am dumpheap <包名> /data/local/tmp/<文件>'，由应用进程写入堆转储
+//  2. am 在部分版本上不等待写入完成，因此轮询文件大小，连续两次不变才认为写完（最多 2 分钟）
+//  3. 拉取到本地并删除设备上的文件
+//
+// 注意事项：
+//   - 只有可调试（debuggable）的应用或 root / userdebug 设备才能生成堆转储
+//   - 堆转储期间应用会暂停，文件可能有数百 MB
+//   - 得到的是 Android 格式的 hprof，需要用 ConvertHprof（hprof-conv）转换后才能在 MAT 等通用工具中打开；
+//     Android Studio 可以直接打开
+//
+// 示例：
+//
+//	if err := device.DumpHeap("com.example.app", "artifacts/app.hprof"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	adb.ConvertHprof("artifacts/app.hprof", "artifacts/app-mat.hprof")
+func (d *Device) DumpHeap(pkg, localHprofPath string) error {
+	pkg = d.packageOr(pkg)
+	remote := fmt.Sprintf("/data/local/tmp/%s-%s.hprof", pkg, time.Now().Format("20060102150405"))
+	defer d.Shellf("rm -f %s", remote)
+
+	output, err := d.Shellf("am dumpheap %s %s", pkg, remote)
+	text := output + errString(err)
+	switch {
+	case strings.Contains(text, "No process found") || strings.Contains(text, "Unknown process"):
+		return fmt.Errorf("dump heap %s: %w", pkg, ErrNotRunning)
+	case strings.Contains(text, "not debuggable") || strings.Contains(text, "SecurityException"):
+		return fmt.Errorf("dump heap %s: app is not debuggable: %w", pkg, ErrRootRequired)
+	case err != nil:
+		return err
+	}
+
+	// 等待文件大小稳定
+	deadline := time.Now().Add(heapDumpTimeout)
+	last := int64(-1)
+	for {
+		time.Sleep(d.pollEvery())
+		size := int64(-1)
+		if info, err := d.Stat(remote); err == nil {
+			size = info.Size
+		}
+		if size > 0 && size == last {
+			break
+		}
+		last = size
+		if time.Now().After(deadline) {
+			return fmt.Errorf("dump heap %s: file not complete after %s", pkg, heapDumpTimeout)
+		}
+	}
+	return d.Pull(remote, localHprofPath)
+}
+
+// ConvertHprof 使用 Android SDK 的 hprof-conv 把 Android 格式的 hprof 转换为标准格式，
+// 转换后可以用 Eclipse MAT 等通用工具分析。
+//
+// 参数：
+//   - src: DumpHeap 得到的 hprof 文件
+//   - dst: 转换后的文件路径
+//
+// 返回值：
+//   - error: 如果 PATH 中没有 hprof-conv（错误包装了 ErrUnsupported）或转换失败，返回 error 对象
+func ConvertHprof(src, dst string) error {
+	tool, err := exec.LookPath("hprof-conv")
+	if err != nil {
+		return fmt.Errorf("hprof-conv not found in PATH: %w", ErrUnsupported)
+	}
+	output, err := exec.Command(tool, src, dst).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("hprof-conv failed: %w, output: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package adb
+
+import (
+	"errors"
+	"testing"
+)
+
+// meminfoAPI21 是 Android 5.0 上 dumpsys meminfo 的节选，没有 App Summary。
+const meminfoAPI21 = `Applications Memory Usage (kB):
+Uptime: 1234567 Realtime: 1234567
+
+** MEMINFO in pid 4321 [com.example.app] **
+                   Pss  Private  Private  Swapped     Heap     Heap     Heap
+                 Total    Dirty    Clean    Dirty     Size    Alloc     Free
+                ------   ------   ------   ------   ------   ------   ------
+  Native Heap     3120     3076        0        0     9216     8697      518
+  Dalvik Heap     7821     7712        0        0    20224    15362     4862
+ Dalvik Other      392      392        0        0
+        Stack      164      164        0        0
+     .so mmap     1039      256       24        0
+    .dex mmap     1864        0     1864        0
+      Unknown      636      632        0        0
+        TOTAL    15229    12236     1928        0    29440    24059     5380
+
+ Objects
+               Views:       42         ViewRootImpl:        1
+`
+
+// meminfoAPI28 是 Android 9 上的节选，App Summary 的合计行为 "TOTAL:"。
+const meminfoAPI28 = `Applications Memory Usage (in Kilobytes):
+Uptime: 7654321 Realtime: 7654321
+
+** MEMINFO in pid 4321 [com.example.app] **
+                   Pss  Private  Private  SwapPss     Heap     Heap     Heap
+                 Total    Dirty    Clean    Dirty     Size    Alloc     Free
+                ------   ------   ------   ------   ------   ------   ------
+  Native Heap     8512     8452        0       12    16384    13520     2863
+  Dalvik Heap     4304     4252        0       29     6654     3327     3327
+        Stack      272      272        0        0
+      Unknown      612      608        0        0
+        TOTAL    28680    20596     3904       41    23038    16847     6190
+
+ App Summary
+                       Pss(KB)
+                        ------
+           Java Heap:     4928
+         Native Heap:     8512
+                Code:     5364
+               Stack:      272
+            Graphics:     2496
+       Private Other:     2236
+              System:     4872
+
+               TOTAL:    28680       TOTAL SWAP PSS:       41
+`
+
+// meminfoAPI28Summary 是 Android 9 上 'dumpsys meminfo -s' 的输出，只有 App Summary。
+const meminfoAPI28Summary = `Applications Memory Usage (in Kilobytes):
+Uptime: 7654321 Realtime: 7654321
+
+** MEMINFO in pid 4321 [com.example.app] **
+
+ App Summary
+                       Pss(KB)
+                        ------
+           Java Heap:     4928
+         Native Heap:     8512
+                Code:     5364
+               Stack:      272
+            Graphics:     2496
+       Private Other:     2236
+              System:     4872
+
+               TOTAL:    28680       TOTAL SWAP PSS:       41
+`
+
+// meminfoAPI33Summary 是 Android 13 上 'dumpsys meminfo -s' 的输出，只有 App Summary，
+// 合计行为 "TOTAL PSS:"。
+const meminfoAPI33Summary = `Applications Memory Usage (in Kilobytes):
+Uptime: 9876543 Realtime: 9876543
+
+** MEMINFO in pid 4321 [com.example.app] **
+
+ App Summary
+                       Pss(KB)                        Rss(KB)
+                        ------                         ------
+           Java Heap:     6512                          18452
+         Native Heap:    12036                          13216
+                Code:     8820                          40908
+               Stack:      612                            616
+            Graphics:     3420                           3420
+       Private Other:     2864
+              System:    10231
+             Unknown:                                    4576
+
+           TOTAL PSS:    44495            TOTAL RSS:    81188       TOTAL SWAP PSS:       21
+`
+
+func TestParseMemInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   MemInfo
+	}{
+		{"API 21 table", meminfoAPI21, MemInfo{TotalPSS: 15229, JavaHeap: 7821, NativeHeap: 3120}},
+		{"API 28 TOTAL:", meminfoAPI28, MemInfo{
+			TotalPSS: 28680, JavaHeap: 4928, NativeHeap: 8512, Code: 5364,
+			Stack: 272, Graphics: 2496, PrivateOther: 2236, System: 4872,
+		}},
+		{"API 28 summary TOTAL:", meminfoAPI28Summary, MemInfo{
+			TotalPSS: 28680, JavaHeap: 4928, NativeHeap: 8512, Code: 5364,
+			Stack: 272, Graphics: 2496, PrivateOther: 2236, System: 4872,
+		}},
+		{"API 33 TOTAL PSS:", meminfoAPI33Summary, MemInfo{
+			TotalPSS: 44495, JavaHeap: 6512, NativeHeap: 12036, Code: 8820,
+			Stack: 612, Graphics: 3420, PrivateOther: 2864, System: 10231,
+		}},
+	}
+	for _, tt := range tests {
+		got, err := parseMemInfo(tt.output)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: parsed %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := parseMemInfo("Applications Memory Usage (in Kilobytes):\n"); err == nil {
+		t.Error("output without a total parsed successfully")
+	}
+}
+
+func TestMemInfoNotRunning(t *testing.T) {
+	d, _ := newFakeDevice(func(string) (string, error) {
+		return "No process found for: com.example.app", nil
+	})
+	if _, err := d.MemInfo("com.example.app"); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("MemInfo = %v, want ErrNotRunning", err)
+	}
+}
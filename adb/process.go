@@ -0,0 +1,209 @@
+package adb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Process 表示设备上的一个进程。
+type Process struct {
+	PID  int    // 进程 ID
+	PPID int    // 父进程 ID
+	User string // 运行用户，例如 root、u0_a123
+	Name string // 进程名，应用进程通常为包名（或 "包名:子进程名"）
+}
+
+// PidOf 获取指定进程名（通常是应用包名）对应的所有进程 ID。
+//
+// 参数：
+//   - pkg: 进程名，应用的主进程名即包名
+//
+// 返回值：
+//   - []int: 进程 ID 列表，通常只有一个
+//   - error: 如果命令执行失败，返回 error 对象；进程未运行时返回的错误包装了 ErrNotRunning
+//
+// 工作原理：
+//   - 优先使用 'pidof'（Android 7.0+ 自带）
+//   - pidof 不可用时回退到解析 ps 的输出，按进程名精确匹配
+//
+// 注意事项：
+//   - 只匹配完整的进程名，"com.example.app:remote" 这样的子进程需要传入完整名称
+//
+// 示例：
+//
+//	pids, err := device.PidOf("com.example.app")
+//	if err != nil {
+//	    fmt.Println("应用未运行")
+//	    return
+//	}
+//	fmt.Println("PID:", pids[0])
+func (d *Device) PidOf(pkg string) ([]int, error) {
+	output, err := d.Shellf("pidof %s", pkg)
+	if err == nil && output != "" {
+		var pids []int
+		for _, field := range strings.Fields(output) {
+			pid, err := strconv.Atoi(field)
+			if err != nil {
+				// 输出不是数字，说明 pidof 不可用（例如 "pidof: not found"）
+				pids = nil
+				break
+			}
+			pids = append(pids, pid)
+		}
+		if len(pids) > 0 {
+			return pids, nil
+		}
+	}
+
+	// 回退：从进程列表中查找
+	processes, err := d.Processes()
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, p := range processes {
+		if p.Name == pkg {
+			pids = append(pids, p.PID)
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("process %s: %w", pkg, ErrNotRunning)
+	}
+	return pids, nil
+}
+
+// Processes 获取设备上的所有进程。
+//
+// 返回值：
+//   - []Process: 进程列表
+//   - error: 如果命令执行失败或输出无法解析，返回 error 对象
+//
+// 兼容性：
+//   - Android 8.0+ 的 toybox ps 默认只显示当前会话的进程，需要 'ps -A'
+//   - 更早版本的 ps 不支持 -A，直接使用 'ps'
+//   - 两种 ps 的列不同（VSZ / VSIZE、ADDR / PC，旧版还有一列没有表头的状态），
+//     这里根据表头定位 USER、PID、PPID 列，进程名固定取最后一列
+//
+// 示例：
+//
+//	processes, err := device.Processes()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, p := range processes {
+//	    if strings.HasPrefix(p.Name, "com.example") {
+//	        fmt.Printf("%d %s %s\n", p.PID, p.User, p.Name)
+//	    }
+//	}
+func (d *Device) Processes() ([]Process, error) {
+	output, err := d.Shell("ps -A")
+	// 旧版 ps 不认识 -A，会报错或只输出表头
+	if err != nil || strings.Count(output, "\n") < 2 {
+		if output, err = d.Shell("ps"); err != nil {
+			return nil, err
+		}
+	}
+	return parseProcesses(output)
+}
+
+// parseProcesses 解析 ps 的输出。
+func parseProcesses(output string) ([]Process, error) {
+	lines := strings.Split(output, "\n")
+	header := strings.Fields(lines[0])
+	userCol, pidCol, ppidCol := -1, -1, -1
+	for i, name := range header {
+		switch name {
+		case "USER":
+			userCol = i
+		case "PID":
+			pidCol = i
+		case "PPID":
+			ppidCol = i
+		}
+	}
+	if pidCol < 0 {
+		return nil, fmt.Errorf("unexpected ps output: %s", lines[0])
+	}
+
+	var processes []Process
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		// 至少要包含表头中 USER/PID/PPID 所在的列和最后的进程名
+		if len(fields) <= pidCol || len(fields) <= ppidCol {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[pidCol])
+		if err != nil {
+			continue
+		}
+		p := Process{PID: pid, Name: fields[len(fields)-1]}
+		if userCol >= 0 {
+			p.User = fields[userCol]
+		}
+		if ppidCol >= 0 {
+			p.PPID, _ = strconv.Atoi(fields[ppidCol])
+		}
+		processes = append(processes, p)
+	}
+	return processes, nil
+}
+
+// Kill 向指定进程发送 SIGTERM 信号。
+//
+// 参数：
+//   - pid: 进程 ID
+//
+// 返回值：
+//   - error: 如果进程不存在或没有权限，返回 error 对象
+//
+// 注意事项：
+//   - 非 root 的 shell 用户只能结束自己启动的进程，结束应用进程通常需要 root
+//   - 只想停止应用时，ForceStopApp 不需要 root
+//
+// 示例：
+//
+//	pids, _ := device.PidOf("com.example.app")
+//	for _, pid := range pids {
+//	    device.Kill(pid)
+//	}
+func (d *Device) Kill(pid int) error {
+	output, err := d.Shell(fmt.Sprintf("kill %d", pid))
+	if err != nil {
+		return err
+	}
+	// kill 成功时没有输出，失败时输出类似 "kill: 1234: Operation not permitted"
+	if output != "" {
+		return fmt.Errorf("kill %d failed: %s", pid, output)
+	}
+	return nil
+}
+
+// KillByName 结束指定进程名（通常是应用包名）的所有进程。
+//
+// 参数：
+//   - pkg: 进程名
+//
+// 返回值：
+//   - error: 如果进程未运行或任意一个进程结束失败，返回 error 对象
+//
+// 注意事项：
+//   - 与 Kill 一样，结束应用进程通常需要 root
+//
+// 示例：
+//
+//	if err := device.KillByName("com.example.app"); err != nil {
+//	    device.ForceStopApp("com.example.app")
+//	}
+func (d *Device) KillByName(pkg string) error {
+	pids, err := d.PidOf(pkg)
+	if err != nil {
+		return err
+	}
+	for _, pid := range pids {
+		if err := d.Kill(pid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
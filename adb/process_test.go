@@ -0,0 +1,90 @@
+package adb
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// psToybox 是 Android 8.0+ 上 'ps -A' 的节选。
+const psToybox = `USER           PID  PPID     VSZ    RSS WCHAN            ADDR S NAME
+root             1     0 10782796  9684 SyS_epoll_wait      0 S init
+root             2     0       0      0 kthreadd            0 S [kthreadd]
+system         612     1 12345678 45678 binder_ioctl_write_read 0 S system_server
+u0_a123       4321   612 14567890 98765 SyS_epoll_wait      0 S com.example.app
+u0_a123       4350   612 14000000 54321 SyS_epoll_wait      0 S com.example.app:remote
+shell         5001  4990 10800000  3456 0                   0 R ps
+`
+
+// psLegacy 是 Android 7.x 及更早版本 'ps' 的节选，状态列没有表头。
+const psLegacy = `USER      PID   PPID  VSIZE  RSS   WCHAN              PC  NAME
+root      1     0     8904   644   SyS_epoll_ 0000000000 S /init
+root      2     0     0      0       kthreadd 0000000000 S kthreadd
+system    612   243   1524592 98740 SyS_epoll_ 0000000000 S system_server
+u0_a123   4321  243   1012344 61232 SyS_epoll_ 0000000000 S com.example.app
+shell     5001  4990  4012   1212           0 7f8c2e4a30 R ps
+`
+
+func TestParseProcesses(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []Process
+	}{
+		{"toybox ps -A", psToybox, []Process{
+			{PID: 1, PPID: 0, User: "root", Name: "init"},
+			{PID: 2, PPID: 0, User: "root", Name: "[kthreadd]"},
+			{PID: 612, PPID: 1, User: "system", Name: "system_server"},
+			{PID: 4321, PPID: 612, User: "u0_a123", Name: "com.example.app"},
+			{PID: 4350, PPID: 612, User: "u0_a123", Name: "com.example.app:remote"},
+			{PID: 5001, PPID: 4990, User: "shell", Name: "ps"},
+		}},
+		{"legacy ps", psLegacy, []Process{
+			{PID: 1, PPID: 0, User: "root", Name: "/init"},
+			{PID: 2, PPID: 0, User: "root", Name: "kthreadd"},
+			{PID: 612, PPID: 243, User: "system", Name: "system_server"},
+			{PID: 4321, PPID: 243, User: "u0_a123", Name: "com.example.app"},
+			{PID: 5001, PPID: 4990, User: "shell", Name: "ps"},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := parseProcesses(tt.output)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: parsed %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := parseProcesses("ps: bad -A\n"); err == nil {
+		t.Error("output without a PID column parsed successfully")
+	}
+}
+
+func TestPidOfFallsBackToLegacyPs(t *testing.T) {
+	d, r := newFakeDevice(func(command string) (string, error) {
+		switch command {
+		case "ps -A":
+			// 旧版 ps 把 -A 当作进程名过滤，只输出表头
+			return "USER      PID   PPID  VSIZE  RSS   WCHAN              PC  NAME\n", nil
+		case "ps":
+			return psLegacy, nil
+		}
+		return "/system/bin/sh: pidof: not found", nil
+	})
+
+	pids, err := d.PidOf("com.example.app")
+	if err != nil || !slices.Equal(pids, []int{4321}) {
+		t.Errorf("PidOf = %v, %v; want [4321]", pids, err)
+	}
+	want := []string{"pidof 'com.example.app'", "ps -A", "ps"}
+	if got := r.shellCommands(); !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+
+	if _, err := d.PidOf("com.example.missing"); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("PidOf(missing) = %v, want ErrNotRunning", err)
+	}
+}
@@ -0,0 +1,251 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// propLineRe 匹配 getprop 输出中的一条属性，例如 "[ro.product.model]: [Pixel 7]"。
+// 属性值中可能包含换行，因此值一直匹配到行尾的 "]"。
+var propLineRe = regexp.MustCompile(`(?ms)^\[([^\]]+)\]: \[(.*?)\]$`)
+
+// GetProp 读取设备的系统属性（getprop）。
+//
+// 参数：
+//   - key: 属性名，例如 "ro.build.version.sdk"、"persist.sys.locale"
+//
+// 返回值：
+//   - string: 属性值；属性不存在时返回空字符串
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - ro.* 属性在开机后不会再变化，从 GetAllProps 缓存的完整属性表中读取，不再单独执行 getprop
+//   - 其他属性随时可能被修改，每次都从设备实时读取
+//
+// 示例：
+//
+//	model, err := device.GetProp("ro.product.model")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println("设备型号:", model)
+func (d *Device) GetProp(key string) (string, error) {
+	if strings.HasPrefix(key, "ro.") {
+		props, err := d.cachedProps()
+		if err != nil {
+			return "", err
+		}
+		if value, ok := props[key]; ok {
+			return value, nil
+		}
+	}
+	return d.Shellf("getprop %s", key)
+}
+
+// GetAllProps 一次读取设备的全部系统属性。
+// 结果会被缓存，之后 GetProp、SDKLevel、Model、AndroidVersion 等读取 ro.* 属性时直接使用缓存，
+// 多个查询只需要执行一次 getprop。
+//
+// 返回值：
+//   - map[string]string: 属性名到属性值的映射（每次返回新的副本，可以随意修改）
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - 每次调用都会重新执行 getprop 并刷新缓存，得到的是当前时刻的全部属性
+//   - 设备重启或刷机后 ro.* 属性可能变化，需要调用 RefreshProps 清除缓存
+//
+// 示例：
+//
+//	props, err := device.GetAllProps()
+//	if err == nil {
+//	    fmt.Println(props["ro.product.brand"], props["ro.build.fingerprint"])
+//	}
+func (d *Device) GetAllProps() (map[string]string, error) {
+	output, err := d.Shell("getprop")
+	if err != nil {
+		return nil, err
+	}
+	props := parseProps(output)
+
+	d.mu.Lock()
+	d.props = props
+	d.mu.Unlock()
+
+	all := make(map[string]string, len(props))
+	for k, v := range props {
+		all[k] = v
+	}
+	return all, nil
+}
+
+// RefreshProps 清除 GetAllProps 的属性缓存和 SDKLevel 的缓存，下次读取 ro.* 属性时重新执行 getprop。
+// 在设备重启、刷机或通过 SetProp 以外的方式修改了 ro.* 属性之后调用。
+func (d *Device) RefreshProps() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.props = nil
+	d.sdk = 0
+}
+
+// cachedProps 返回缓存的属性表，尚未缓存时执行一次 getprop。返回的 map 不能修改。
+func (d *Device) cachedProps() (map[string]string, error) {
+	d.mu.Lock()
+	props := d.props
+	d.mu.Unlock()
+	if props != nil {
+		return props, nil
+	}
+	if _, err := d.GetAllProps(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.props, nil
+}
+
+// parseProps 解析 getprop 的输出。
+func parseProps(output string) map[string]string {
+	props := make(map[string]string)
+	for _, m := range propLineRe.FindAllStringSubmatch(output, -1) {
+		props[m[1]] = m[2]
+	}
+	return props
+}
+
+// SDKLevel 返回设备的 Android API 级别（ro.build.version.sdk）。
+// 许多命令在不同 API 级别下行为不同，调用方可据此选择实现方式。
+// 结果在首次读取成功后缓存，之后的调用不再执行 adb 命令。
+//
+// 返回值：
+//   - int: API 级别，例如 Android 10 为 29，Android 14 为 34
+//   - error: 如果读取失败或属性值不是数字，返回 error 对象
+//
+// 示例：
+//
+//	sdk, err := device.SDKLevel()
+//	if err == nil && sdk >= 30 {
+//	    fmt.Println("Android 11 及以上")
+//	}
+func (d *Device) SDKLevel() (int, error) {
+	d.mu.Lock()
+	sdk := d.sdk
+	d.mu.Unlock()
+	if sdk > 0 {
+		return sdk, nil
+	}
+
+	value, err := d.GetProp("ro.build.version.sdk")
+	if err != nil {
+		return 0, err
+	}
+	sdk, err = strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("bad sdk level %q: %w", value, err)
+	}
+	d.mu.Lock()
+	d.sdk = sdk
+	d.mu.Unlock()
+	return sdk, nil
+}
+
+// Model 返回设备型号（ro.product.model），例如 "Pixel 7"。
+// 从缓存的属性表读取，见 GetAllProps。
+func (d *Device) Model() (string, error) {
+	return d.GetProp("ro.product.model")
+}
+
+// Manufacturer 返回设备厂商（ro.product.manufacturer），例如 "Google"、"Xiaomi"。
+// 从缓存的属性表读取，见 GetAllProps。
+func (d *Device) Manufacturer() (string, error) {
+	return d.GetProp("ro.product.manufacturer")
+}
+
+// AndroidVersion 返回 Android 版本号（ro.build.version.release），例如 "14"、"8.1.0"。
+// 从缓存的属性表读取，见 GetAllProps。
+func (d *Device) AndroidVersion() (string, error) {
+	return d.GetProp("ro.build.version.release")
+}
+
+// 系统属性的长度限制（bionic system_properties.h）。
+const (
+	propNameMaxLegacy = 31 // Android 8.0 以前属性名的最大长度（PROP_NAME_MAX - 1）
+	propValueMax      = 91 // 属性值的最大长度（PROP_VALUE_MAX - 1），ro.* 属性在 Android 8.0+ 不受限制
+)
+
+// SetProp 修改设备的系统属性（setprop），并读取回来确认修改生效。
+//
+// 参数：
+//   - key: 属性名，例如 "debug.layout"、"log.tag.MyApp"
+//   - value: 新的值
+//
+// 返回值：
+//   - error: 以下情况返回 error 对象
+//     属性名或值超出长度限制；
+//     ro.* 属性已经有值（只读属性只能设置一次）；
+//     没有权限（非 root 时大部分 persist.*、sys.* 属性不可写），此时错误包装了 ErrRootRequired
+//
+// 注意事项：
+//   - shell 用户通常可以修改 debug.*、log.tag.* 等属性
+//   - 部分属性（例如 debug.layout）修改后需要界面刷新才会生效
+//   - 修改成功后会记录原来的值，调用 Cleanup 时恢复（ro.* 属性除外）
+//
+// 示例：
+//
+//	// 显示布局边界
+//	if err := device.SetProp("debug.layout", "true"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	device.Shell("service call activity 1599295570") // 通知界面刷新
+func (d *Device) SetProp(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("empty property name")
+	}
+	if sdk, err := d.SDKLevel(); err == nil && sdk < 26 && len(key) > propNameMaxLegacy {
+		return fmt.Errorf("property name %q longer than %d characters", key, propNameMaxLegacy)
+	}
+	if len(value) > propValueMax && !strings.HasPrefix(key, "ro.") {
+		return fmt.Errorf("property value for %s longer than %d bytes", key, propValueMax)
+	}
+	if strings.HasPrefix(key, "ro.") {
+		if current, err := d.GetProp(key); err == nil && current != "" {
+			return fmt.Errorf("property %s is read-only (current value %q)", key, current)
+		}
+	}
+
+	prev, err := d.GetProp(key)
+	if err != nil {
+		return err
+	}
+
+	// setprop 失败时有的版本只打印错误、退出码仍为 0，因此读取回来确认
+	msg, err := d.Shellf("setprop %s %s", key, value)
+	if err == nil {
+		// 直接读取设备，不经过 ro.* 属性的缓存
+		current, err := d.Shellf("getprop %s", key)
+		if err != nil {
+			return err
+		}
+		if current == value {
+			if strings.HasPrefix(key, "ro.") {
+				d.RefreshProps()
+			} else {
+				d.pushUndo("property "+key, func() error {
+					_, err := d.Shellf("setprop %s %s", key, prev)
+					return err
+				})
+			}
+			return nil
+		}
+		if msg == "" {
+			msg = fmt.Sprintf("value is still %q", current)
+		}
+	} else {
+		msg = err.Error()
+	}
+	if root, _ := d.IsRoot(); !root {
+		return fmt.Errorf("setprop %s: %s: %w", key, msg, ErrRootRequired)
+	}
+	return fmt.Errorf("setprop %s failed: %s", key, msg)
+}
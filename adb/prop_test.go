@@ -0,0 +1,154 @@
+package adb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// propStore 模拟设备上的系统属性和 getprop / setprop 命令。
+// 非 root 时只允许修改 debug.* 和 log.tag.* 属性，与 shell 用户的 SELinux 策略类似。
+type propStore struct {
+	root  bool
+	props map[string]string
+}
+
+// respond 处理与属性相关的命令，其他命令返回空输出。
+func (s *propStore) respond(command string) (string, error) {
+	fields := strings.Fields(command)
+	for i, f := range fields {
+		fields[i] = strings.Trim(f, "'")
+	}
+	switch {
+	case command == "id -u":
+		if s.root {
+			return "0", nil
+		}
+		return "2000", nil
+	case command == "getprop":
+		keys := make([]string, 0, len(s.props))
+		for k := range s.props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		for _, k := range keys {
+			fmt.Fprintf(&b, "[%s]: [%s]\n", k, s.props[k])
+		}
+		return b.String(), nil
+	case fields[0] == "getprop" && len(fields) == 2:
+		return s.props[fields[1]], nil
+	case fields[0] == "setprop" && len(fields) >= 2:
+		key, value := fields[1], strings.Join(fields[2:], " ")
+		writable := s.root || strings.HasPrefix(key, "debug.") || strings.HasPrefix(key, "log.tag.")
+		if !writable || (strings.HasPrefix(key, "ro.") && s.props[key] != "") {
+			return "", errors.New("Failed to set property '" + key + "' to '" + value + "'")
+		}
+		s.props[key] = value
+	}
+	return "", nil
+}
+
+func newPropDevice(root bool) (*Device, *propStore) {
+	s := &propStore{root: root, props: map[string]string{
+		"ro.build.version.sdk": "34",
+		"persist.sys.locale":   "en-US",
+	}}
+	d, _ := newFakeDevice(s.respond)
+	return d, s
+}
+
+func TestGetSetPropRoundTripAsRoot(t *testing.T) {
+	d, s := newPropDevice(true)
+	if root, err := d.IsRoot(); err != nil || !root {
+		t.Fatalf("IsRoot() = %v, %v", root, err)
+	}
+
+	if err := d.SetProp("persist.sys.locale", "zh-CN"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := d.GetProp("persist.sys.locale"); err != nil || got != "zh-CN" {
+		t.Errorf("GetProp after SetProp = %q, %v; want zh-CN", got, err)
+	}
+	if err := d.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.props["persist.sys.locale"]; got != "en-US" {
+		t.Errorf("after Cleanup persist.sys.locale = %q, want en-US", got)
+	}
+}
+
+func TestSetPropWithoutRoot(t *testing.T) {
+	d, _ := newPropDevice(false)
+
+	if err := d.SetProp("debug.layout", "true"); err != nil {
+		t.Errorf("SetProp(debug.layout) = %v, want shell user to be allowed", err)
+	}
+	if got, _ := d.GetProp("debug.layout"); got != "true" {
+		t.Errorf("GetProp(debug.layout) = %q", got)
+	}
+	if err := d.SetProp("persist.sys.locale", "zh-CN"); !errors.Is(err, ErrRootRequired) {
+		t.Errorf("SetProp(persist.sys.locale) = %v, want ErrRootRequired", err)
+	}
+}
+
+func TestSetPropValidation(t *testing.T) {
+	d, _ := newPropDevice(true)
+
+	if err := d.SetProp("ro.build.version.sdk", "35"); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("SetProp(ro.*) = %v, want read-only error", err)
+	}
+	if err := d.SetProp("", "x"); err == nil {
+		t.Error("SetProp with an empty name succeeded")
+	}
+	if err := d.SetProp("debug.value", strings.Repeat("x", propValueMax+1)); err == nil {
+		t.Error("SetProp with an overlong value succeeded")
+	}
+
+	// Android 8.0 以前属性名最多 31 个字符
+	legacy, s := newPropDevice(true)
+	s.props["ro.build.version.sdk"] = "25"
+	if err := legacy.SetProp("debug."+strings.Repeat("x", propNameMaxLegacy), "1"); err == nil {
+		t.Error("SetProp with an overlong name on API 25 succeeded")
+	}
+}
+
+func TestPropGettersShareOneGetprop(t *testing.T) {
+	s := &propStore{props: map[string]string{
+		"ro.build.version.sdk":     "34",
+		"ro.build.version.release": "14",
+		"ro.product.model":         "Pixel 7",
+		"ro.product.manufacturer":  "Google",
+	}}
+	d, r := newFakeDevice(s.respond)
+
+	for i := 0; i < 2; i++ {
+		if sdk, err := d.SDKLevel(); err != nil || sdk != 34 {
+			t.Fatalf("SDKLevel() = %d, %v", sdk, err)
+		}
+		if v, err := d.Model(); err != nil || v != "Pixel 7" {
+			t.Fatalf("Model() = %q, %v", v, err)
+		}
+		if v, err := d.Manufacturer(); err != nil || v != "Google" {
+			t.Fatalf("Manufacturer() = %q, %v", v, err)
+		}
+		if v, err := d.AndroidVersion(); err != nil || v != "14" {
+			t.Fatalf("AndroidVersion() = %q, %v", v, err)
+		}
+	}
+	if got := r.shellCommands(); len(got) != 1 || got[0] != "getprop" {
+		t.Fatalf("getters ran %q, want a single getprop", got)
+	}
+
+	// 缓存清除后重新读取，能看到刷机后变化的 ro.* 属性
+	s.props["ro.product.model"] = "Pixel 8"
+	d.RefreshProps()
+	if v, err := d.Model(); err != nil || v != "Pixel 8" {
+		t.Errorf("Model() after RefreshProps = %q, %v; want Pixel 8", v, err)
+	}
+	if n := r.count(); n != 2 {
+		t.Errorf("runner called %d times, want 2", n)
+	}
+}
@@ -0,0 +1,178 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// providerRowRe 匹配 'content query' 输出中每一行的开头，例如 "Row: 0 _id=1, name=foo"
+	providerRowRe = regexp.MustCompile(`^Row: \d+ `)
+	// providerColumnRe 匹配行中 "列名=" 的位置，第一列在行首，之后的列前面是 ", "
+	providerColumnRe = regexp.MustCompile(`(?:^|, )([A-Za-z_][\w.]*)=`)
+)
+
+// QueryProvider 查询 content provider，不经过界面读取短信、联系人、设置等数据。
+//
+// 参数：
+//   - uri: provider 的 URI，例如 "content://settings/secure"、"content://sms/inbox"
+//   - projection: 要返回的列，为空时返回所有列
+//
+// 返回值：
+//   - []map[string]string: 每一行为一个 map（列名到值），没有数据时返回空切片；
+//     数据库中的 NULL 值为字符串 "NULL"
+//   - error: 如果 provider 不存在（错误包装了 ErrNotFound）、没有权限（错误包装了 ErrRootRequired）
+//     或命令执行失败，返回 error 对象
+//
+// 工作原理：
+//   - 执行 'content query --uri <uri> --projection a:b:c'，解析每一行 "Row: N a=1, b=2, c=3"
+//   - 值中可能含有 ", " 和换行，指定 projection 时只在已知列名前分割，结果最可靠；
+//     未指定时在 ", 列名=" 的位置分割，值中恰好含有 ", xxx=" 的文本可能被错误拆分
+//
+// 注意事项：
+//   - shell 用户只能读取导出且不需要特殊权限的 provider，短信、联系人等通常需要 root
+//   - 需要 Android 5.0+ 的 content 命令
+//
+// 示例：
+//
+//	rows, err := device.QueryProvider("content://settings/secure", []string{"name", "value"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, row := range rows {
+//	    fmt.Println(row["name"], "=", row["value"])
+//	}
+func (d *Device) QueryProvider(uri string, projection []string) ([]map[string]string, error) {
+	command := "content query --uri " + shellQuote(uri)
+	if len(projection) > 0 {
+		command += " --projection " + shellQuote(strings.Join(projection, ":"))
+	}
+	output, err := d.Shell(command)
+	if err := providerError("query", uri, output, err); err != nil {
+		return nil, err
+	}
+	return parseProviderRows(output, projection), nil
+}
+
+// InsertProvider 向 content provider 插入一行数据。
+//
+// 参数：
+//   - uri: provider 的 URI
+//   - values: 列名到值的映射，所有值都以字符串类型（--bind 列名:s:值）传入，由 SQLite 按列类型转换
+//
+// 返回值：
+//   - error: 如果 provider 不存在、没有权限或插入失败，返回 error 对象
+//
+// 示例：
+//
+//	err := device.InsertProvider("content://settings/system", map[string]string{
+//	    "name":  "screen_off_timeout",
+//	    "value": "600000",
+//	})
+func (d *Device) InsertProvider(uri string, values map[string]string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("insert %s: no values", uri)
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := []string{"content insert --uri", shellQuote(uri)}
+	for _, k := range keys {
+		parts = append(parts, "--bind", shellQuote(k+":s:"+values[k]))
+	}
+	output, err := d.Shell(strings.Join(parts, " "))
+	return providerError("insert", uri, output, err)
+}
+
+// DeleteProvider 删除 content provider 中满足条件的行。
+//
+// 参数：
+//   - uri: provider 的 URI
+//   - where: SQL 条件，例如 "name='my_setting'"；为空时删除该 URI 下的所有行
+//
+// 返回值：
+//   - error: 如果 provider 不存在、没有权限或删除失败，返回 error 对象
+//
+// 示例：
+//
+//	err := device.DeleteProvider("content://settings/system", "name='my_setting'")
+func (d *Device) DeleteProvider(uri, where string) error {
+	command := "content delete --uri " + shellQuote(uri)
+	if where != "" {
+		command += " --where " + shellQuote(where)
+	}
+	output, err := d.Shell(command)
+	return providerError("delete", uri, output, err)
+}
+
+// providerError 根据 content 命令的输出判断操作是否失败。
+// content 出错时通常仍以 0 退出，只在输出中打印异常。
+func providerError(op, uri, output string, err error) error {
+	text := output + errString(err)
+	switch {
+	case strings.Contains(text, "Could not find provider") || strings.Contains(text, "Unknown URI") ||
+		strings.Contains(text, "Unknown authority"):
+		return fmt.Errorf("%s %s: %w", op, uri, ErrNotFound)
+	case strings.Contains(text, "SecurityException") || strings.Contains(text, "Permission Denial"):
+		return fmt.Errorf("%s %s: permission denied: %w", op, uri, ErrRootRequired)
+	case strings.Contains(text, "Exception") || strings.Contains(text, "Error while accessing provider"):
+		return fmt.Errorf("%s %s failed: %s", op, uri, truncate(output, 200))
+	case err != nil:
+		return err
+	}
+	return nil
+}
+
+// parseProviderRows 解析 'content query' 的输出。
+// 不以 "Row: " 开头的行是上一行最后一个值中的换行。
+func parseProviderRows(output string, projection []string) []map[string]string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if providerRowRe.MatchString(line) || len(lines) == 0 {
+			lines = append(lines, line)
+		} else {
+			lines[len(lines)-1] += "\n" + line
+		}
+	}
+
+	known := make(map[string]bool, len(projection))
+	for _, col := range projection {
+		known[col] = true
+	}
+
+	rows := []map[string]string{}
+	for _, line := range lines {
+		loc := providerRowRe.FindStringIndex(line)
+		if loc == nil {
+			continue // "No result found." 等
+		}
+		rows = append(rows, parseProviderRow(line[loc[1]:], known))
+	}
+	return rows
+}
+
+// parseProviderRow 解析一行 "a=1, b=2, c=3"。known 非空时只在其中的列名前分割。
+func parseProviderRow(s string, known map[string]bool) map[string]string {
+	var starts [][]int // 每一列的匹配位置：[匹配起始, 值起始, 列名起始, 列名结束]
+	for _, m := range providerColumnRe.FindAllStringSubmatchIndex(s, -1) {
+		if len(known) > 0 && !known[s[m[2]:m[3]]] {
+			continue
+		}
+		starts = append(starts, m)
+	}
+
+	row := make(map[string]string, len(starts))
+	for i, m := range starts {
+		end := len(s)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		row[s[m[2]:m[3]]] = s[m[1]:end]
+	}
+	return row
+}
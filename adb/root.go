@@ -0,0 +1,207 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rootRestartTimeout 是 adb root / unroot 后等待 adbd 重启完成的最长时间。
+const rootRestartTimeout = 30 * time.Second
+
+// Ping 检查设备是否在线并能执行 shell 命令。
+//
+// 返回值：
+//   - error: 设备离线、未授权或 shell 无响应时返回 error 对象
+//
+// 示例：
+//
+//	if err := device.Ping(); err != nil {
+//	    log.Fatal("设备不可用:", err)
+//	}
+func (d *Device) Ping() error {
+	output, err := d.Shell("echo ok")
+	if err != nil {
+		return err
+	}
+	if output != "ok" {
+		return fmt.Errorf("unexpected ping output: %s", output)
+	}
+	return nil
+}
+
+// IsRoot 判断 adb shell 是否以 root 用户（uid 0）运行。
+//
+// 返回值：
+//   - bool: adbd 以 root 运行（执行过 Root）或 shell 默认即为 root 时返回 true
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 示例：
+//
+//	if root, _ := device.IsRoot(); !root {
+//	    device.Root()
+//	}
+func (d *Device) IsRoot() (bool, error) {
+	uid, err := d.Shell("id -u")
+	if err != nil {
+		return false, err
+	}
+	return uid == "0", nil
+}
+
+// Root 以 root 权限重启设备上的 adbd（adb root），并等待设备重新连接。
+// 许多操作（修改系统属性、访问 /data、remount 等）需要 root 权限。
+//
+// 返回值：
+//   - error: 正式版（user）系统返回的错误包装了 ErrProductionBuild；
+//     重启后设备未在 30 秒内恢复，返回超时错误
+//
+// 注意事项：
+//   - 只能在 userdebug / eng 系统（包括大部分模拟器镜像）上使用
+//   - adbd 重启期间连接会断开，该方法会等待设备重新上线并确认已是 root
+//   - 已经是 root 时直接返回
+//
+// 示例：
+//
+//	err := device.Root()
+//	if errors.Is(err, adb.ErrProductionBuild) {
+//	    log.Fatal("正式版系统不支持 adb root")
+//	}
+func (d *Device) Root() error {
+	output, err := d.execCommand("root")
+	if strings.Contains(output+errString(err), "production builds") {
+		return fmt.Errorf("adb root: %w", ErrProductionBuild)
+	}
+	if err != nil {
+		return err
+	}
+	if strings.Contains(output, "already running as root") {
+		return nil
+	}
+	return d.waitForAdbd(true)
+}
+
+// Unroot 以普通 shell 用户重新启动 adbd（adb unroot），并等待设备重新连接。
+//
+// 返回值：
+//   - error: 如果命令执行失败或设备未在 30 秒内恢复，返回 error 对象
+//
+// 示例：
+//
+//	device.Root()
+//	defer device.Unroot()
+func (d *Device) Unroot() error {
+	output, err := d.execCommand("unroot")
+	if err != nil {
+		return err
+	}
+	if strings.Contains(output, "not running as root") {
+		return nil
+	}
+	return d.waitForAdbd(false)
+}
+
+// waitForAdbd 等待 adbd 重启后设备重新上线，并确认 root 状态符合预期。
+func (d *Device) waitForAdbd(root bool) error {
+	deadline := time.Now().Add(rootRestartTimeout)
+	for time.Now().Before(deadline) {
+		// adbd 重启需要一点时间，过早检查可能连到旧的 adbd
+		time.Sleep(d.pollEvery())
+		if d.Ping() != nil {
+			continue
+		}
+		if isRoot, err := d.IsRoot(); err == nil && isRoot == root {
+			return nil
+		}
+	}
+	return fmt.Errorf("device not back after adbd restart in %s", rootRestartTimeout)
+}
+
+// errString 返回错误信息，err 为 nil 时返回空字符串。
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// RemountResult 是 Remount 的执行结果。
+type RemountResult struct {
+	RebootRequired bool   // 需要重启设备后 /system 才可写（overlayfs 首次启用或刚关闭 verity）
+	Output         string // adb remount 的原始输出
+}
+
+// Remount 将 /system、/vendor 等分区重新挂载为可写（adb remount），
+// 之后才能向系统分区推送文件，否则会报 "Read-only file system"。
+//
+// 返回值：
+//   - RemountResult: RebootRequired 为 true 时需要重启设备，重启后再次调用 Remount 才会生效
+//   - error: 正式版系统返回的错误包装了 ErrProductionBuild；其他失败返回 error 对象
+//
+// 注意事项：
+//   - 需要先调用 Root
+//   - Android 10+ 使用 overlayfs，首次 remount 或 dm-verity 仍开启时会提示
+//     "Now reboot your device for settings to take effect"，此时 RebootRequired 为 true
+//
+// 示例：
+//
+//	device.Root()
+//	res, err := device.Remount()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if res.RebootRequired {
+//	    device.Shell("reboot")
+//	    adb.WaitForDevice(device.Serial)
+//	    device.Root()
+//	    res, err = device.Remount()
+//	}
+func (d *Device) Remount() (RemountResult, error) {
+	output, err := d.execCommand("remount")
+	if strings.Contains(output+errString(err), "production builds") {
+		return RemountResult{}, fmt.Errorf("adb remount: %w", ErrProductionBuild)
+	}
+	if err != nil {
+		return RemountResult{}, err
+	}
+	res := RemountResult{Output: output, RebootRequired: rebootRequired(output)}
+	if !res.RebootRequired && strings.Contains(output, "failed") {
+		return res, fmt.Errorf("adb remount failed: %s", output)
+	}
+	return res, nil
+}
+
+// DisableVerity 关闭 dm-verity 校验（adb disable-verity），这是在部分设备上 remount 系统分区的前提。
+//
+// 返回值：
+//   - error: 正式版系统返回的错误包装了 ErrProductionBuild；其他失败返回 error 对象
+//
+// 注意事项：
+//   - 需要先调用 Root
+//   - 关闭后必须重启设备才会生效
+//   - 已经关闭时同样返回 nil
+//
+// 示例：
+//
+//	if err := device.DisableVerity(); err == nil {
+//	    device.Shell("reboot")
+//	}
+func (d *Device) DisableVerity() error {
+	output, err := d.execCommand("disable-verity")
+	if strings.Contains(output+errString(err), "production builds") {
+		return fmt.Errorf("adb disable-verity: %w", ErrProductionBuild)
+	}
+	if err != nil {
+		return err
+	}
+	if !rebootRequired(output) && !strings.Contains(output, "already disabled") {
+		return fmt.Errorf("adb disable-verity failed: %s", output)
+	}
+	return nil
+}
+
+// rebootRequired 判断 adb remount / disable-verity 的输出是否要求重启设备。
+func rebootRequired(output string) bool {
+	return strings.Contains(output, "reboot your device") ||
+		strings.Contains(output, "Reboot the device")
+}
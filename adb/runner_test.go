@@ -0,0 +1,76 @@
+package adb
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeRunner 是测试用的 Runner：记录每次调用的参数，并通过 respond 返回预设的输出。
+type fakeRunner struct {
+	mu    sync.Mutex
+	calls [][]string
+	name  string // 最近一次调用的可执行文件路径
+
+	// respond 根据参数返回标准输出和错误，为 nil 时所有命令输出为空
+	respond func(ctx context.Context, args []string) (string, error)
+}
+
+// Run 实现 Runner。
+func (r *fakeRunner) Run(ctx context.Context, name string, args []string, stdin io.Reader) ([]byte, []byte, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, append([]string(nil), args...))
+	r.name = name
+	r.mu.Unlock()
+	if r.respond == nil {
+		return nil, nil, nil
+	}
+	out, err := r.respond(ctx, args)
+	return []byte(out), nil, err
+}
+
+// shellCommands 返回所有 'adb shell <command>' 调用的命令部分，按调用顺序排列。
+func (r *fakeRunner) shellCommands() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var cmds []string
+	for _, args := range r.calls {
+		if len(args) >= 2 && args[0] == "shell" {
+			cmds = append(cmds, strings.Join(args[1:], " "))
+		}
+	}
+	return cmds
+}
+
+// count 返回调用次数。
+func (r *fakeRunner) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls)
+}
+
+// newFakeDevice 创建使用 fakeRunner 的设备，respond 接收 shell 命令（不含 "shell"）。
+// 不是 shell 的 adb 命令（例如 pull）同样交给 respond，此时参数以空格拼接。
+func newFakeDevice(respond func(command string) (string, error)) (*Device, *fakeRunner) {
+	r := &fakeRunner{}
+	if respond != nil {
+		r.respond = func(_ context.Context, args []string) (string, error) {
+			if len(args) >= 2 && args[0] == "shell" {
+				return respond(strings.Join(args[1:], " "))
+			}
+			return respond(strings.Join(args, " "))
+		}
+	}
+	return NewDeviceWithOptions(WithRunner(r), WithPollInterval(time.Millisecond)), r
+}
+
+// dumpCommand 是 UiautomatorDump 通过 newFakeDevice 传给 respond 的命令。
+const dumpCommand = "exec-out uiautomator dump /dev/tty"
+
+// hierarchy 把若干 <node> 元素包装成一份 uiautomator dump。
+func hierarchy(nodes ...string) string {
+	return `<?xml version='1.0' encoding='UTF-8' standalone='yes' ?><hierarchy rotation="0">` +
+		strings.Join(nodes, "") + `</hierarchy>`
+}
@@ -0,0 +1,196 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRecordSegment 是 screenrecord 单次录制的最长时间（系统限制为 3 分钟）。
+const maxRecordSegment = 180 * time.Second
+
+// RecordOptions 是屏幕录制的可选参数，零值表示使用 screenrecord 的默认设置。
+type RecordOptions struct {
+	BitRate int           // 码率（bit/s），对应 --bit-rate，例如 4000000
+	Size    string        // 分辨率，对应 --size，例如 "1280x720"
+	Segment time.Duration // 每段的时长，为 0 或超过 3 分钟时使用 3 分钟
+}
+
+// args 将选项转换为 screenrecord 命令行参数。
+func (o RecordOptions) args() []string {
+	segment := o.Segment
+	if segment <= 0 || segment > maxRecordSegment {
+		segment = maxRecordSegment
+	}
+	args := []string{"--time-limit", fmt.Sprint(int(segment.Seconds()))}
+	if o.BitRate > 0 {
+		args = append(args, "--bit-rate", fmt.Sprint(o.BitRate))
+	}
+	if o.Size != "" {
+		args = append(args, "--size", o.Size)
+	}
+	return args
+}
+
+// ScreenRecordLong 录制超过 3 分钟的长视频。
+// screenrecord 单次最多录制 3 分钟，该方法连续录制多段，每段结束后立即开始下一段，并把各段拉取到本地。
+//
+// 参数：
+//   - ctx: 控制录制时长，ctx 取消或超时后结束录制
+//   - localPath: 本地文件路径模板，例如 "record.mp4"，各段保存为 "record_001.mp4"、"record_002.mp4" ...
+//   - opts: 码率、分辨率、每段时长等选项
+//
+// 返回值：
+//   - []string: 按顺序排列的本地分段文件路径
+//   - error: 如果录制或拉取失败，返回 error 对象（已拉取成功的分段仍会返回）；
+//     多个分段失败时通过 errors.Join 合并所有错误
+//
+// 工作原理：
+//  1. 在设备上后台执行 screenrecord --time-limit，录制到 /sdcard 的临时文件，并记录它的进程号
+//  2. 一段结束后立刻开始下一段，同时在后台拉取上一段并删除设备上的文件
+//  3. ctx 取消时只向本次启动的 screenrecord 进程发送 SIGINT，使当前段正常结束并写完文件，然后拉取该段；
+//     10 秒内仍未结束时直接结束 adb 进程
+//
+// 注意事项：
+//   - 段与段之间会有几百毫秒的间隙
+//   - 分段文件没有合并，可使用 ffmpeg 的 concat 功能拼接
+//   - 每段都通过 Runner 执行，WithTimeout 设置的超时需要大于每段的时长
+//
+// 示例：
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+//	defer cancel()
+//	files, err := device.ScreenRecordLong(ctx, "artifacts/session.mp4", adb.RecordOptions{BitRate: 4000000})
+//	if err != nil {
+//	    log.Println("录制出错:", err)
+//	}
+//	fmt.Println("分段文件:", files)
+func (d *Device) ScreenRecordLong(ctx context.Context, localPath string, opts RecordOptions) ([]string, error) {
+	ext := filepath.Ext(localPath)
+	if ext == "" {
+		ext = ".mp4"
+	}
+	base := strings.TrimSuffix(localPath, filepath.Ext(localPath))
+	stamp := time.Now().Format("20060102150405")
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		files []string
+		errs  []error
+	)
+
+	for i := 1; ctx.Err() == nil; i++ {
+		remote := fmt.Sprintf("/sdcard/adb-record-%s-%03d.mp4", stamp, i)
+		local := fmt.Sprintf("%s_%03d%s", base, i, ext)
+		files = append(files, local)
+
+		if err := d.recordSegment(ctx, remote, opts); err != nil {
+			d.Shellf("rm -f %s", remote)
+			wg.Wait()
+			return files[:len(files)-1], errors.Join(append(errs, err)...)
+		}
+
+		// 后台拉取本段，同时开始录制下一段
+		wg.Add(1)
+		go func(remote, local string) {
+			defer wg.Done()
+			err := d.Pull(remote, local)
+			d.Shellf("rm -f %s", remote)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("pull %s: %w", remote, err))
+				mu.Unlock()
+			}
+		}(remote, local)
+	}
+
+	wg.Wait()
+	return files, errors.Join(errs...)
+}
+
+// ScreenRecordTo 录制一段屏幕视频，以原始 H.264 流的形式直接写入 w，不经过设备上的文件。
+//
+// 参数：
+//   - w: 视频数据的写入目标，例如本地文件或网络连接
+//   - opts: 码率、分辨率、时长（Segment）等选项，时长最长 3 分钟
+//
+// 返回值：
+//   - error: 如果录制失败，返回 error 对象
+//
+// 注意事项：
+//   - 输出是裸 H.264 流而不是 MP4，可使用 'ffmpeg -i record.h264 -c copy record.mp4' 封装
+//   - 需要 screenrecord 支持 --output-format（Android 10+）
+//   - 调用会阻塞到录制结束，WithTimeout 设置的超时需要大于录制时长
+//
+// 示例：
+//
+//	f, _ := os.Create("record.h264")
+//	defer f.Close()
+//	err := device.ScreenRecordTo(f, adb.RecordOptions{Segment: 30 * time.Second})
+func (d *Device) ScreenRecordTo(w io.Writer, opts RecordOptions) error {
+	args := append([]string{"screenrecord", "--output-format=h264"}, opts.args()...)
+	return d.ExecoutTo(w, strings.Join(append(args, "-"), " "))
+}
+
+// recordStopGrace 是 ctx 取消后等待 screenrecord 写完当前段的最长时间，超过后直接结束 adb 进程。
+const recordStopGrace = 10 * time.Second
+
+// recordSegment 录制一段视频，ctx 取消时让 screenrecord 正常结束当前段。
+func (d *Device) recordSegment(ctx context.Context, remote string, opts RecordOptions) error {
+	// screenrecord 在后台运行并把进程号写入 pid 文件，停止时只向这个进程发送信号
+	pidFile := remote + ".pid"
+	defer d.Shellf("rm -f %s", pidFile)
+	args := append(opts.args(), remote)
+	for i, arg := range args {
+		args[i] = shellQuote(arg)
+	}
+	script := fmt.Sprintf("screenrecord %s & echo $! > %s; wait $!", strings.Join(args, " "), shellQuote(pidFile))
+
+	// 不直接把 ctx 传给 adb，否则取消时 adb 被立即结束，视频文件来不及写完；
+	// 发送 SIGINT 后超过 recordStopGrace 仍未结束才取消 runCtx
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+		// 本段已经结束时不再发送信号，以免两个 case 同时就绪时选中了 ctx.Done
+		select {
+		case <-done:
+			return
+		default:
+		}
+		// SIGINT 使 screenrecord 停止录制并写完 MP4 文件；刚启动时 pid 文件可能还没写入，稍后重试
+		for {
+			if output, err := d.Shellf("kill -INT $(cat %s)", pidFile); err == nil && output == "" {
+				break
+			}
+			select {
+			case <-done:
+				return
+			case <-time.After(d.pollEvery()):
+			}
+		}
+		select {
+		case <-done:
+		case <-time.After(recordStopGrace):
+			cancelRun()
+		}
+	}()
+
+	stdout, stderr, err := d.runContext(runCtx, nil, "shell", script)
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("screenrecord failed: %w, output: %s", err, strings.TrimSpace(string(append(stdout, stderr...))))
+	}
+	return nil
+}
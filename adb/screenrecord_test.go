@@ -0,0 +1,107 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingDevice 返回一台伪造的设备：前 segments-1 段 screenrecord 立即结束（相当于到达时长限制），
+// 最后一段开始后取消 ctx，直到收到 kill 才结束。pull 返回 pullErr。
+func recordingDevice(segments int, cancel context.CancelFunc, pullErr error) (*Device, *fakeRunner) {
+	var (
+		mu      sync.Mutex
+		started int
+		killed  = make(chan struct{})
+		once    sync.Once
+	)
+	r := &fakeRunner{}
+	r.respond = func(ctx context.Context, args []string) (string, error) {
+		command := strings.Join(args, " ")
+		switch {
+		case args[0] == "pull":
+			return "", pullErr
+		case strings.Contains(command, "screenrecord"):
+			mu.Lock()
+			started++
+			n := started
+			mu.Unlock()
+			if n < segments {
+				return "", nil
+			}
+			cancel()
+			select {
+			case <-killed:
+				return "", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		case strings.HasPrefix(command, "shell kill -INT"):
+			once.Do(func() { close(killed) })
+		}
+		return "", nil
+	}
+	return NewDeviceWithOptions(WithRunner(r), WithPollInterval(time.Millisecond)), r
+}
+
+func TestScreenRecordLongStopsOnlyItsOwnProcess(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d, r := recordingDevice(2, cancel, nil)
+	local := filepath.Join(t.TempDir(), "session.mp4")
+
+	files, err := d.ScreenRecordLong(ctx, local, RecordOptions{BitRate: 4000000, Segment: 30 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || !strings.HasSuffix(files[1], "session_002.mp4") {
+		t.Errorf("files = %q, want two segments", files)
+	}
+
+	record := regexp.MustCompile(`^screenrecord '--time-limit' '30' '--bit-rate' '4000000' '(/sdcard/adb-record-\d+-002\.mp4)' & echo \$! > '(/sdcard/adb-record-\d+-002\.mp4\.pid)'; wait \$!$`)
+	var remote, pidFile string
+	var kills []string
+	for _, c := range r.shellCommands() {
+		if m := record.FindStringSubmatch(c); m != nil {
+			remote, pidFile = m[1], m[2]
+		}
+		if strings.Contains(c, "kill") {
+			kills = append(kills, c)
+		}
+	}
+	if remote == "" {
+		t.Fatalf("no background screenrecord for the second segment in %q", r.shellCommands())
+	}
+	if want := "kill -INT $(cat '" + pidFile + "')"; len(kills) != 1 || kills[0] != want {
+		t.Errorf("stop commands = %q, want only %q", kills, want)
+	}
+	if !slices.Contains(r.shellCommands(), "rm -f '"+remote+"'") {
+		t.Errorf("%s was not removed after the pull", remote)
+	}
+}
+
+func TestScreenRecordLongJoinsPullErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errFull := errors.New("no space left on device")
+	d, _ := recordingDevice(3, cancel, errFull)
+
+	files, err := d.ScreenRecordLong(ctx, filepath.Join(t.TempDir(), "session.mp4"), RecordOptions{})
+	if len(files) != 3 {
+		t.Errorf("files = %q, want three segments", files)
+	}
+	if !errors.Is(err, errFull) {
+		t.Fatalf("err = %v, want the pull error", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if !regexp.MustCompile(`pull /sdcard/adb-record-\d+-00` + string(rune('0'+i)) + `\.mp4`).MatchString(err.Error()) {
+			t.Errorf("error does not mention segment %d: %v", i, err)
+		}
+	}
+}
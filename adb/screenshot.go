@@ -0,0 +1,297 @@
+package adb
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// Screenshot 截取设备当前屏幕，返回 PNG 格式的图片数据。
+// 该方法通过 'exec-out screencap -p' 直接读取二进制输出，不经过设备上的临时文件。
+//
+// 返回值：
+//   - []byte: PNG 图片数据
+//   - error: 如果截图失败，返回 error 对象
+//
+// 注意事项：
+//   - 部分应用设置了 FLAG_SECURE，截图结果会是黑屏
+//   - 高分辨率设备的截图可能有数 MB，频繁调用会影响性能
+//
+// 示例：
+//
+//	data, err := device.Screenshot()
+//	if err != nil {
+//	    log.Fatal("截图失败:", err)
+//	}
+//	os.WriteFile("screen.png", data, 0644)
+func (d *Device) Screenshot() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.ExecoutTo(&buf, "screencap -p"); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, pngMagic) {
+		return nil, fmt.Errorf("screencap returned non-png data: %q", truncate(string(data), 100))
+	}
+	return data, nil
+}
+
+// pngMagic 是 PNG 文件的文件头。
+var pngMagic = []byte("\x89PNG\r\n\x1a\n")
+
+// ScreenshotRegion 截取屏幕上的一个矩形区域，返回 PNG 格式的图片数据。
+// screencap 不能在设备端裁剪，因此截取整屏后在本地解码一次并裁剪。
+//
+// 参数：
+//   - rect: 要截取的区域（当前方向下的屏幕坐标，与 UI 节点的 bounds 一致），超出屏幕的部分会被裁掉
+//
+// 返回值：
+//   - []byte: 区域的 PNG 图片数据
+//   - error: 如果区域面积为 0 或负数、与屏幕没有交集，或截图失败，返回 error 对象
+//
+// 示例：
+//
+//	data, err := device.ScreenshotRegion(uixml.Rect{X1: 0, Y1: 0, X2: 1080, Y2: 200})
+//	if err == nil {
+//	    os.WriteFile("header.png", data, 0644)
+//	}
+func (d *Device) ScreenshotRegion(rect uixml.Rect) ([]byte, error) {
+	if rect.X2 <= rect.X1 || rect.Y2 <= rect.Y1 {
+		return nil, fmt.Errorf("empty region %+v", rect)
+	}
+	data, err := d.Screenshot()
+	if err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode screenshot: %w", err)
+	}
+
+	r := image.Rect(rect.X1, rect.Y1, rect.X2, rect.Y2).Intersect(img.Bounds())
+	if r.Empty() {
+		return nil, fmt.Errorf("region %+v outside screen %dx%d", rect, img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, fmt.Errorf("unsupported screenshot image type %T", img)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sub.SubImage(r)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ScreenshotRegionByNode 截取匹配节点所在的区域，用于单个控件的截图和视觉检查。
+//
+// 参数：
+//   - fn: 定位目标节点的查找函数
+//
+// 返回值：
+//   - []byte: 节点区域的 PNG 图片数据
+//   - error: 如果找不到节点、节点边界无效或截图失败，返回 error 对象
+//
+// 注意事项：
+//   - 先 dump 再截图，两者之间界面发生变化时截到的可能不是该节点
+//
+// 示例：
+//
+//	data, err := device.ScreenshotRegionByNode(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/avatar"
+//	})
+func (d *Device) ScreenshotRegionByNode(fn FindNodeFunc) ([]byte, error) {
+	node, err := d.FindNode(fn)
+	if err != nil {
+		return nil, err
+	}
+	rect, err := uixml.ParseBounds(node.Bounds)
+	if err != nil {
+		return nil, err
+	}
+	return d.ScreenshotRegion(rect)
+}
+
+// CompareOptions 是 ScreenshotCompare 的比较选项。
+//
+// 字段说明：
+//   - Tolerance: 每个颜色通道（R/G/B/A，0-255）允许的最大差值，超过才算作不同像素
+//   - Ignore: 忽略比较的区域列表（使用基准图的坐标），例如时间、动画等动态区域
+//   - Downscale: 两张图尺寸不一致时，是否按相同比例缩放到较小的宽度后再比较（例如同款设备的不同分辨率）；
+//     缩放保持宽高比，两张图宽高比不同时仍然返回错误；为 false 时尺寸不一致直接返回错误
+type CompareOptions struct {
+	Tolerance uint8
+	Ignore    []uixml.Rect
+	Downscale bool
+}
+
+// ScreenshotCompare 截取当前屏幕并与基准图进行逐像素比较，用于视觉回归测试。
+//
+// 参数：
+//   - baseline: 基准 PNG 图片数据（通常是事先保存的 Screenshot 结果）
+//   - opts: 比较选项，见 CompareOptions
+//
+// 返回值：
+//   - diffRatio: 不同像素占参与比较像素的比例（0.0 - 1.0），忽略区域不计入
+//   - diffImage: 差异图（PNG），不同像素标记为红色，其余像素淡化显示
+//   - err: 如果截图、解码失败，或尺寸不一致（未开启 Downscale，或宽高比不同），返回 error 对象
+//
+// 注意事项：
+//   - 缩放使用最近邻采样，适合判断整体布局，不适合精确到像素的比较
+//   - 状态栏时间、光标闪烁等动态内容建议加入 Ignore 区域
+//
+// 示例：
+//
+//	baseline, _ := os.ReadFile("golden/login.png")
+//	ratio, diff, err := device.ScreenshotCompare(baseline, adb.CompareOptions{
+//	    Tolerance: 8,
+//	    Ignore:    []uixml.Rect{{X1: 0, Y1: 0, X2: 1080, Y2: 80}}, // 忽略状态栏
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if ratio > 0.01 {
+//	    os.WriteFile("login_diff.png", diff, 0644)
+//	    log.Fatalf("界面差异 %.2f%%", ratio*100)
+//	}
+func (d *Device) ScreenshotCompare(baseline []byte, opts CompareOptions) (diffRatio float64, diffImage []byte, err error) {
+	current, err := d.Screenshot()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	base, err := png.Decode(bytes.NewReader(baseline))
+	if err != nil {
+		return 0, nil, fmt.Errorf("decode baseline: %w", err)
+	}
+	cur, err := png.Decode(bytes.NewReader(current))
+	if err != nil {
+		return 0, nil, fmt.Errorf("decode screenshot: %w", err)
+	}
+
+	ratio, diff, err := compareImages(base, cur, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, diff); err != nil {
+		return 0, nil, fmt.Errorf("encode diff image: %w", err)
+	}
+	return ratio, buf.Bytes(), nil
+}
+
+// compareImages 逐像素比较两张图片，返回差异比例和差异图。
+func compareImages(base, cur image.Image, opts CompareOptions) (float64, *image.RGBA, error) {
+	bb, cb := base.Bounds(), cur.Bounds()
+	w, h := bb.Dx(), bb.Dy()
+	ignore := opts.Ignore
+
+	if bb.Dx() != cb.Dx() || bb.Dy() != cb.Dy() {
+		if !opts.Downscale {
+			return 0, nil, fmt.Errorf("size mismatch: baseline %dx%d, screenshot %dx%d", bb.Dx(), bb.Dy(), cb.Dx(), cb.Dy())
+		}
+		// 两张图各自按同一个比例缩放宽和高，缩放到较小的宽度，避免图像变形
+		w = min(bb.Dx(), cb.Dx())
+		bh, ch := bb.Dy()*w/bb.Dx(), cb.Dy()*w/cb.Dx()
+		if bh-ch > 1 || ch-bh > 1 {
+			return 0, nil, fmt.Errorf("aspect ratio mismatch: baseline %dx%d, screenshot %dx%d", bb.Dx(), bb.Dy(), cb.Dx(), cb.Dy())
+		}
+		// 取整导致的一个像素高度差异直接裁掉
+		h = min(bh, ch)
+		ignore = scaleRects(ignore, w, bb.Dx())
+		base = resizeNearest(base, w, bh)
+		cur = resizeNearest(cur, w, ch)
+		bb, cb = base.Bounds(), cur.Bounds()
+	}
+	if w == 0 || h == 0 {
+		return 0, nil, fmt.Errorf("empty image")
+	}
+
+	diff := image.NewRGBA(image.Rect(0, 0, w, h))
+	total, changed := 0, 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBAModel.Convert(cur.At(cb.Min.X+x, cb.Min.Y+y)).(color.RGBA)
+			if inRects(ignore, x, y) {
+				// 忽略区域用灰色遮罩标出
+				diff.SetRGBA(x, y, color.RGBA{R: 128, G: 128, B: 128, A: 255})
+				continue
+			}
+			total++
+			b := color.RGBAModel.Convert(base.At(bb.Min.X+x, bb.Min.Y+y)).(color.RGBA)
+			if channelDiff(b.R, c.R) > opts.Tolerance || channelDiff(b.G, c.G) > opts.Tolerance ||
+				channelDiff(b.B, c.B) > opts.Tolerance || channelDiff(b.A, c.A) > opts.Tolerance {
+				changed++
+				diff.SetRGBA(x, y, color.RGBA{R: 255, A: 255})
+				continue
+			}
+			// 相同像素淡化显示，便于看清红色差异
+			diff.SetRGBA(x, y, color.RGBA{R: 255 - (255-c.R)/3, G: 255 - (255-c.G)/3, B: 255 - (255-c.B)/3, A: 255})
+		}
+	}
+	if total == 0 {
+		return 0, diff, nil
+	}
+	return float64(changed) / float64(total), diff, nil
+}
+
+// channelDiff 返回两个颜色通道值之差的绝对值。
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// inRects 判断点 (x, y) 是否落在任意一个矩形内（包含左上边界，不包含右下边界）。
+func inRects(rects []uixml.Rect, x, y int) bool {
+	for _, r := range rects {
+		if r.Contains(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// scaleRects 将矩形的坐标按 to/from 的比例缩放，宽和高使用同一个比例。
+func scaleRects(rects []uixml.Rect, to, from int) []uixml.Rect {
+	out := make([]uixml.Rect, 0, len(rects))
+	for _, r := range rects {
+		out = append(out, uixml.Rect{
+			X1: r.X1 * to / from,
+			Y1: r.Y1 * to / from,
+			X2: r.X2 * to / from,
+			Y2: r.Y2 * to / from,
+		})
+	}
+	return out
+}
+
+// resizeNearest 使用最近邻采样将图片缩放到 w x h。
+func resizeNearest(src image.Image, w, h int) image.Image {
+	sb := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy := sb.Min.Y + y*sb.Dy()/h
+		for x := 0; x < w; x++ {
+			sx := sb.Min.X + x*sb.Dx()/w
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+// truncate 截断过长的字符串，用于错误信息中展示输出内容。
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
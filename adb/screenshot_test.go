@@ -0,0 +1,125 @@
+package adb
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// solidImage 返回 w x h 的纯色图片。
+func solidImage(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+var gray = color.RGBA{R: 100, G: 100, B: 100, A: 255}
+
+func TestCompareImagesIdentical(t *testing.T) {
+	ratio, diff, err := compareImages(solidImage(10, 20, gray), solidImage(10, 20, gray), CompareOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ratio != 0 {
+		t.Errorf("ratio = %v, want 0", ratio)
+	}
+	if got := diff.Bounds(); got != image.Rect(0, 0, 10, 20) {
+		t.Errorf("diff image bounds = %v", got)
+	}
+}
+
+func TestCompareImagesOnePixel(t *testing.T) {
+	cur := solidImage(10, 20, gray)
+	cur.SetRGBA(3, 4, color.RGBA{R: 200, G: 100, B: 100, A: 255})
+
+	ratio, diff, err := compareImages(solidImage(10, 20, gray), cur, CompareOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ratio != 1.0/200 {
+		t.Errorf("ratio = %v, want %v", ratio, 1.0/200)
+	}
+	if got := diff.RGBAAt(3, 4); got != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("changed pixel drawn as %v, want red", got)
+	}
+	if got := diff.RGBAAt(0, 0); got.G == 0 {
+		t.Errorf("unchanged pixel drawn as %v, want a faded copy", got)
+	}
+
+	// 忽略区域内的差异不计入，也不计入总像素数
+	ratio, diff, err = compareImages(solidImage(10, 20, gray), cur, CompareOptions{
+		Ignore: []uixml.Rect{{X1: 0, Y1: 0, X2: 10, Y2: 10}},
+	})
+	if err != nil || ratio != 0 {
+		t.Errorf("with the pixel ignored: ratio = %v, %v; want 0", ratio, err)
+	}
+	if got := diff.RGBAAt(3, 4); got != (color.RGBA{R: 128, G: 128, B: 128, A: 255}) {
+		t.Errorf("ignored pixel drawn as %v, want gray", got)
+	}
+}
+
+func TestCompareImagesToleranceBoundary(t *testing.T) {
+	base := solidImage(4, 4, gray)
+	cur := solidImage(4, 4, gray)
+	cur.SetRGBA(0, 0, color.RGBA{R: 108, G: 100, B: 100, A: 255}) // R 相差 8
+	cur.SetRGBA(1, 0, color.RGBA{R: 100, G: 91, B: 100, A: 255})  // G 相差 9
+
+	tests := []struct {
+		tolerance uint8
+		want      float64
+	}{
+		{7, 2.0 / 16},
+		{8, 1.0 / 16}, // 差值等于容差时不算不同
+		{9, 0},
+	}
+	for _, tt := range tests {
+		ratio, _, err := compareImages(base, cur, CompareOptions{Tolerance: tt.tolerance})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ratio != tt.want {
+			t.Errorf("tolerance %d: ratio = %v, want %v", tt.tolerance, ratio, tt.want)
+		}
+	}
+}
+
+func TestCompareImagesSizeMismatch(t *testing.T) {
+	if _, _, err := compareImages(solidImage(10, 20, gray), solidImage(5, 10, gray), CompareOptions{}); err == nil {
+		t.Error("different sizes without Downscale compared successfully")
+	}
+
+	// 同样宽高比的两张图缩放到较小的尺寸后比较
+	base := solidImage(10, 20, gray)
+	for y := 10; y < 20; y++ {
+		for x := 0; x < 10; x++ {
+			base.SetRGBA(x, y, color.RGBA{B: 255, A: 255})
+		}
+	}
+	cur := solidImage(5, 10, gray)
+	for y := 5; y < 10; y++ {
+		for x := 0; x < 5; x++ {
+			cur.SetRGBA(x, y, color.RGBA{B: 255, A: 255})
+		}
+	}
+	ratio, diff, err := compareImages(base, cur, CompareOptions{Downscale: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ratio != 0 {
+		t.Errorf("downscaled ratio = %v, want 0", ratio)
+	}
+	if got := diff.Bounds(); got != image.Rect(0, 0, 5, 10) {
+		t.Errorf("downscaled diff bounds = %v, want 5x10", got)
+	}
+
+	// 缩放保持宽高比，宽高比不同时不会把图片拉伸后比较
+	if _, _, err := compareImages(solidImage(10, 20, gray), solidImage(10, 15, gray), CompareOptions{Downscale: true}); err == nil {
+		t.Error("different aspect ratios compared successfully")
+	}
+}
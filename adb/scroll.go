@@ -0,0 +1,297 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// 滚动容器时使用的参数。
+const (
+	scrollSwipeDuration = 300                    // 每次滑动的时长（毫秒）
+	scrollSettleDelay   = 500 * time.Millisecond // 滑动后等待惯性滚动停止的时间
+)
+
+// ScrollToEnd 在可滚动容器内反复向上滑动，直到滚动到底部（例如加载列表的全部内容）。
+// 两次滑动之间容器内的内容完全没有变化时认为已经到底。
+//
+// 参数：
+//   - container: 可滚动容器（RecyclerView、ScrollView 等）的查找函数
+//   - maxSwipes: 最多滑动的次数
+//
+// 返回值：
+//   - int: 实际滑动的次数（包括最后一次没有产生变化的滑动）
+//   - error: 如果容器不存在（ErrNotFound），或滑动 maxSwipes 次后仍未到底，返回 error 对象
+//
+// 工作原理：
+//  1. 根据容器的 bounds 与 ContentBounds 的交集计算滑动坐标：在中线上，从下方 4/5 处滑到上方 1/5 处
+//  2. 每次滑动后等待滚动停止，重新获取 UI 结构
+//  3. 整个 dump 的指纹没有变化时直接认为到底，不再解析；否则比较容器内所有节点的位置和文本
+//
+// 注意事项：
+//   - 滑动坐标只落在容器内部，不会误触容器外的元素；容器延伸到状态栏、导航栏或手势区域下方时，
+//     只在内容区域内滑动，不会触发系统手势
+//   - 列表底部有持续变化的内容（例如加载动画、倒计时）时可能无法判断到底，需要限制 maxSwipes
+//
+// 示例：
+//
+//	list := func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/list"
+//	}
+//	swipes, err := device.ScrollToEnd(list, 20)
+//	if err != nil {
+//	    log.Println("未能滚动到底:", err)
+//	}
+//	fmt.Println("滑动次数:", swipes)
+func (d *Device) ScrollToEnd(container FindNodeFunc, maxSwipes int) (int, error) {
+	return d.scrollContainer(container, maxSwipes, true)
+}
+
+// ScrollToTop 在可滚动容器内反复向下滑动，直到回到顶部，规则与 ScrollToEnd 相同。
+//
+// 参数：
+//   - container: 可滚动容器的查找函数
+//   - maxSwipes: 最多滑动的次数
+//
+// 返回值：
+//   - int: 实际滑动的次数
+//   - error: 如果容器不存在，或滑动 maxSwipes 次后仍未到顶，返回 error 对象
+//
+// 示例：
+//
+//	device.ScrollToTop(list, 20)
+func (d *Device) ScrollToTop(container FindNodeFunc, maxSwipes int) (int, error) {
+	return d.scrollContainer(container, maxSwipes, false)
+}
+
+// scrollContainer 在容器内反复滑动，直到内容不再变化。toEnd 为 true 时向底部滚动。
+func (d *Device) scrollContainer(container FindNodeFunc, maxSwipes int, toEnd bool) (int, error) {
+	poll := dumpPoller{d: d}
+	xml, _, err := poll.next()
+	if err != nil {
+		return 0, err
+	}
+	node, err := xml.Find(container)
+	if err != nil {
+		return 0, fmt.Errorf("scroll container: %w", err)
+	}
+	r, err := uixml.ParseBounds(node.Bounds)
+	if err != nil {
+		return 0, err
+	}
+	if r.Height() <= 0 {
+		return 0, fmt.Errorf("bad container bounds %s", node.Bounds)
+	}
+
+	// 铺满屏幕的容器延伸到系统栏下方，只在与内容区域重叠的部分滑动
+	view := d.safeSwipeArea(r)
+	x := (view.X1 + view.X2) / 2
+	low, high := view.Y2-view.Height()/5, view.Y1+view.Height()/5
+	last := containerSignature(xml, node)
+
+	for i := 1; i <= maxSwipes; i++ {
+		if toEnd {
+			err = d.swipe(x, low, x, high, scrollSwipeDuration)
+		} else {
+			err = d.swipe(x, high, x, low, scrollSwipeDuration)
+		}
+		if err != nil {
+			return i - 1, err
+		}
+		time.Sleep(scrollSettleDelay)
+
+		xml, changed, err := poll.next()
+		if err != nil {
+			return i, err
+		}
+		// 整个界面的指纹都没有变化，容器内容自然也没有变化
+		if !changed {
+			return i, nil
+		}
+		if node, err = xml.Find(container); err != nil {
+			return i, fmt.Errorf("scroll container: %w", err)
+		}
+		sig := containerSignature(xml, node)
+		if sig == last {
+			return i, nil
+		}
+		last = sig
+	}
+	return maxSwipes, fmt.Errorf("scroll: still moving after %d swipes", maxSwipes)
+}
+
+// containerSignature 返回容器内所有后代节点位置和文本拼接成的字符串，用于判断内容是否变化。
+func containerSignature(xml *uixml.Xml, container uixml.Node) string {
+	var b strings.Builder
+	for _, n := range xml.FindIn(container, func(n, pn uixml.Node) bool { return true }) {
+		b.WriteString(n.Key())
+		b.WriteString(n.Text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// 把节点滚动到可见区域时使用的参数。
+const (
+	preciseSwipeDuration = 600 // 慢速滑动的时长（毫秒），避免惯性滚动越过目标
+	safeTapMaxSwipes     = 10  // SafeTap 最多滑动的次数
+)
+
+// ScrollToElement 把匹配的节点滚动到完全可见的位置。
+// 节点已在屏幕上但被裁切或位于导航栏下方时，在它最近的可滚动祖先内小幅滑动；
+// 节点还不在 UI 树中（例如列表中尚未加载的项）时，在第一个可滚动容器内向下翻页查找。
+//
+// 参数：
+//   - fn: 目标节点的查找函数
+//   - maxSwipes: 最多滑动的次数
+//
+// 返回值：
+//   - uixml.Node: 滚动后的节点（bounds 为新的位置）
+//   - error: 如果页面上没有可滚动容器、滚动到底仍找不到节点（错误包装了 ErrNotFound），
+//     或滑动 maxSwipes 次后节点仍不可见，返回 error 对象
+//
+// 工作原理：
+//   - 可见区域为 ContentBounds 与最近的可滚动祖先的交集
+//   - 节点碰到可见区域的边缘时视为被裁切，按超出的距离慢速滑动，把它移入可见区域
+//   - 滑动后节点位置不再变化（列表已到头）时，只要节点中心在可见区域内就认为可以操作
+//
+// 示例：
+//
+//	node, err := device.ScrollToElement(func(n, pn uixml.Node) bool {
+//	    return n.Text == "隐私政策"
+//	}, 10)
+//	if err == nil {
+//	    device.ClickNodeBy(node)
+//	}
+func (d *Device) ScrollToElement(fn FindNodeFunc, maxSwipes int) (uixml.Node, error) {
+	content, err := d.ContentBounds()
+	if err != nil {
+		return uixml.Node{}, err
+	}
+
+	var lastRect uixml.Rect
+	for swipes := 0; ; swipes++ {
+		xml, err := d.XML()
+		if err != nil {
+			return uixml.Node{}, err
+		}
+
+		node, err := xml.Find(fn)
+		if err != nil {
+			// 节点不在 UI 树中，在第一个可滚动容器内向下翻页
+			container, err := xml.Find(func(n, pn uixml.Node) bool { return n.IsScrollable() })
+			if err != nil {
+				return uixml.Node{}, fmt.Errorf("element not on screen and no scrollable container: %w", ErrNotFound)
+			}
+			if swipes >= maxSwipes {
+				return uixml.Node{}, fmt.Errorf("element not found after %d swipes: %w", swipes, ErrNotFound)
+			}
+			view := visibleArea(xml, container, content, true)
+			before := containerSignature(xml, container)
+			x, _ := view.Center()
+			if err := d.swipe(x, view.Y2-view.Height()/5, x, view.Y1+view.Height()/5, scrollSwipeDuration); err != nil {
+				return uixml.Node{}, err
+			}
+			time.Sleep(scrollSettleDelay)
+			if after, err := d.XML(); err == nil {
+				if c, err := after.Find(func(n, pn uixml.Node) bool { return n.Key() == container.Key() }); err == nil && containerSignature(after, c) == before {
+					return uixml.Node{}, fmt.Errorf("element not found, reached end of %s: %w", container.Class, ErrNotFound)
+				}
+			}
+			continue
+		}
+
+		rect, err := uixml.ParseBounds(node.Bounds)
+		if err != nil {
+			return uixml.Node{}, err
+		}
+		view := visibleArea(xml, node, content, false)
+		cx, cy := rect.Center()
+		centerVisible := view.Contains(cx, cy)
+		if rect.X1 > view.X1 && rect.X2 < view.X2 && rect.Y1 > view.Y1 && rect.Y2 < view.Y2 {
+			return node, nil
+		}
+		// 滑动后位置没变，说明已经滚到头，节点贴着边缘但可以操作
+		if (swipes > 0 && rect == lastRect) || swipes >= maxSwipes {
+			if centerVisible {
+				return node, nil
+			}
+			return uixml.Node{}, fmt.Errorf("element %s at %s cannot be scrolled into view %v", node.Key(), node.Bounds, view)
+		}
+		lastRect = rect
+		if err := d.scrollIntoView(rect, view); err != nil {
+			return uixml.Node{}, err
+		}
+		time.Sleep(scrollSettleDelay)
+	}
+}
+
+// scrollIntoView 在可见区域 view 内慢速滑动，使 rect 向可见区域内部移动。
+func (d *Device) scrollIntoView(rect, view uixml.Rect) error {
+	cx, cy := view.Center()
+	// 每次滑动的距离限制在可见区域的 1/5 到 3/5 之间
+	clamp := func(delta, size int) int {
+		return min(max(delta, size/5), size*3/5)
+	}
+	switch {
+	case rect.Y2 >= view.Y2:
+		delta := clamp(rect.Y2-view.Y2+view.Height()/10, view.Height())
+		start := view.Y2 - view.Height()/5
+		return d.swipe(cx, start, cx, start-delta, preciseSwipeDuration)
+	case rect.Y1 <= view.Y1:
+		delta := clamp(view.Y1-rect.Y1+view.Height()/10, view.Height())
+		start := view.Y1 + view.Height()/5
+		return d.swipe(cx, start, cx, start+delta, preciseSwipeDuration)
+	case rect.X2 >= view.X2:
+		delta := clamp(rect.X2-view.X2+view.Width()/10, view.Width())
+		start := view.X2 - view.Width()/5
+		return d.swipe(start, cy, start-delta, cy, preciseSwipeDuration)
+	default:
+		delta := clamp(view.X1-rect.X1+view.Width()/10, view.Width())
+		start := view.X1 + view.Width()/5
+		return d.swipe(start, cy, start+delta, cy, preciseSwipeDuration)
+	}
+}
+
+// visibleArea 返回节点的可见区域：内容区域与最近的可滚动祖先（self 为 true 时包括节点自身）的交集。
+func visibleArea(xml *uixml.Xml, node uixml.Node, content uixml.Rect, self bool) uixml.Rect {
+	view := content
+	candidates := xml.Ancestors(node)
+	if self {
+		candidates = append([]uixml.Node{node}, candidates...)
+	}
+	for _, a := range candidates {
+		if !a.IsScrollable() {
+			continue
+		}
+		if r, err := uixml.ParseBounds(a.Bounds); err == nil {
+			view = uixml.Rect{X1: max(view.X1, r.X1), Y1: max(view.Y1, r.Y1), X2: min(view.X2, r.X2), Y2: min(view.Y2, r.Y2)}
+		}
+		break
+	}
+	return view
+}
+
+// SafeTap 点击节点，节点在屏幕外或被裁切时先把它滚动到可见区域。
+// 与 ClickNode 不同，目标位于可见区域之外（"首屏以下"）时不会失败或点到错误的位置。
+//
+// 参数：
+//   - fn: 目标节点的查找函数
+//
+// 返回值：
+//   - error: 如果无法把节点滚动到可见区域（最多滑动 10 次）或点击失败，返回 error 对象
+//
+// 示例：
+//
+//	err := device.SafeTap(func(n, pn uixml.Node) bool {
+//	    return n.Text == "提交"
+//	})
+func (d *Device) SafeTap(fn FindNodeFunc) error {
+	node, err := d.ScrollToElement(fn, safeTapMaxSwipes)
+	if err != nil {
+		return fmt.Errorf("safe tap: %w", err)
+	}
+	return d.ClickNodeBy(node)
+}
@@ -0,0 +1,281 @@
+package adb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// Selector 是可序列化的节点选择器，通过属性而不是坐标定位节点，
+// 在不同分辨率的设备和轻微的布局变化下依然有效。
+//
+// 匹配规则：
+//   - 所有非空字段都必须完全相等
+//   - Index 表示取第几个匹配的节点（从 0 开始）
+//
+// 示例：
+//
+//	sel := adb.Selector{ResourceID: "com.example:id/login"}
+//	node, err := device.FindNode(sel.Func())
+type Selector struct {
+	ResourceID  string `json:"resource_id,omitempty"`
+	Text        string `json:"text,omitempty"`
+	ContentDesc string `json:"content_desc,omitempty"`
+	Class       string `json:"class,omitempty"`
+	Index       int    `json:"index,omitempty"`
+}
+
+// Func 返回匹配该选择器的查找函数（忽略 Index）。
+func (s Selector) Func() FindNodeFunc {
+	return func(n, pn uixml.Node) bool {
+		return (s.ResourceID == "" || n.ResourceID == s.ResourceID) &&
+			(s.Text == "" || n.Text == s.Text) &&
+			(s.ContentDesc == "" || n.ContentDesc == s.ContentDesc) &&
+			(s.Class == "" || n.Class == s.Class)
+	}
+}
+
+// find 在 UI 树中查找选择器对应的节点（考虑 Index）。
+func (s Selector) find(xml *uixml.Xml) (uixml.Node, error) {
+	if err := s.validate(); err != nil {
+		return uixml.Node{}, err
+	}
+	nodes := xml.FindAll(s.Func())
+	if s.Index >= len(nodes) {
+		return uixml.Node{}, fmt.Errorf("selector %+v: %w", s, ErrNotFound)
+	}
+	return nodes[s.Index], nil
+}
+
+// validate 检查选择器的 Index 是否有效，负数下标会导致越界。
+func (s Selector) validate() error {
+	if s.Index < 0 {
+		return fmt.Errorf("selector %+v: negative index %d", s, s.Index)
+	}
+	return nil
+}
+
+// SelectorFor 根据节点生成选择器。
+// 优先使用 resource-id，没有时使用 text，再没有时使用 content-desc，并始终带上类名；
+// Index 根据同一 UI 树中匹配的节点计算，保证在录制时的界面上能定位回同一个节点。
+//
+// 参数：
+//   - xml: 节点所在的 UI 树
+//   - node: 目标节点
+//
+// 返回值：
+//   - Selector: 生成的选择器
+func SelectorFor(xml *uixml.Xml, node uixml.Node) Selector {
+	s := Selector{Class: node.Class}
+	switch {
+	case node.ResourceID != "":
+		s.ResourceID = node.ResourceID
+	case node.Text != "":
+		s.Text = node.Text
+	case node.ContentDesc != "":
+		s.ContentDesc = node.ContentDesc
+	}
+	for i, n := range xml.FindAll(s.Func()) {
+		if n.Key() == node.Key() {
+			s.Index = i
+			break
+		}
+	}
+	return s
+}
+
+// RunSelector 在本地保存的 UI dump 文件上执行选择器，不需要连接设备。
+// 用于离线复现 CI 中的查找失败，或在编写选择器时快速验证。
+//
+// 参数：
+//   - xmlPath: Device.SaveDump 保存的 XML 文件路径
+//   - s: 要检查的选择器（考虑 Index）
+//
+// 返回值：
+//   - uixml.Node: 选择器定位到的节点
+//   - error: 如果文件无法解析，或选择器没有匹配（错误包装了 ErrNotFound），返回 error 对象
+//
+// 示例：
+//
+//	node, err := adb.RunSelector("artifacts/login_failed.xml", adb.Selector{ResourceID: "com.example:id/login"})
+//	if err != nil {
+//	    fmt.Println("选择器在失败现场确实找不到:", err)
+//	}
+func RunSelector(xmlPath string, s Selector) (uixml.Node, error) {
+	xml, err := uixml.LoadFile(xmlPath)
+	if err != nil {
+		return uixml.Node{}, err
+	}
+	return s.find(xml)
+}
+
+// 录制动作的类型。
+const (
+	ActionClick = "click" // 点击
+	ActionInput = "input" // 点击后输入文本
+)
+
+// SelectorAction 是选择器脚本中的一个动作。
+type SelectorAction struct {
+	Action   string   `json:"action"`
+	Selector Selector `json:"selector"`
+	Text     string   `json:"text,omitempty"`
+}
+
+// SelectorRecorder 录制基于选择器的 UI 操作，生成可在其他设备上回放的 JSON 脚本。
+// 通过 Device.RecordUIActions 创建，使用它的 ClickNode / Click / TypeInto 代替直接操作设备。
+type SelectorRecorder struct {
+	d       *Device
+	Actions []SelectorAction
+}
+
+// RecordUIActions 创建一个选择器录制器。
+// 录制器在执行操作的同时记录解析到的选择器，最后通过 Script 导出脚本，再用 ReplaySelectors 回放。
+//
+// 返回值：
+//   - *SelectorRecorder: 录制器
+//
+// 示例：
+//
+//	rec := device.RecordUIActions()
+//	rec.TypeInto(adb.ByHint("用户名"), "alice")
+//	rec.TypeInto(adb.ByHint("密码"), "secret")
+//	rec.ClickNode("android.widget.Button", "登录")
+//
+//	script, _ := rec.Script()
+//	os.WriteFile("login.json", script, 0644)
+//
+//	// 在另一台设备上回放
+//	script, _ = os.ReadFile("login.json")
+//	err := other.ReplaySelectors(script)
+func (d *Device) RecordUIActions() *SelectorRecorder {
+	return &SelectorRecorder{d: d}
+}
+
+// Click 查找并点击节点，同时记录该节点的选择器。
+func (r *SelectorRecorder) Click(fn FindNodeFunc) error {
+	sel, node, err := r.resolve(fn)
+	if err != nil {
+		return err
+	}
+	if err := r.d.ClickNodeBy(node); err != nil {
+		return err
+	}
+	r.Actions = append(r.Actions, SelectorAction{Action: ActionClick, Selector: sel})
+	return nil
+}
+
+// ClickNode 与 Device.ClickNode 的匹配规则相同，同时记录该节点的选择器。
+func (r *SelectorRecorder) ClickNode(class, desc string) error {
+	return r.Click(func(n, pn uixml.Node) bool {
+		return (class == "" || n.Class == class) &&
+			(desc == "" || n.ContentDesc == desc || n.Text == desc)
+	})
+}
+
+// TypeInto 点击输入框并输入文本，同时记录输入框的选择器和文本。
+func (r *SelectorRecorder) TypeInto(fn FindNodeFunc, text string) error {
+	sel, node, err := r.resolve(fn)
+	if err != nil {
+		return err
+	}
+	if err := r.d.typeInto(node, text); err != nil {
+		return err
+	}
+	r.Actions = append(r.Actions, SelectorAction{Action: ActionInput, Selector: sel, Text: text})
+	return nil
+}
+
+// Script 将录制的动作导出为 JSON 脚本。
+func (r *SelectorRecorder) Script() ([]byte, error) {
+	return json.MarshalIndent(r.Actions, "", "  ")
+}
+
+// resolve 查找节点并生成选择器。
+func (r *SelectorRecorder) resolve(fn FindNodeFunc) (Selector, uixml.Node, error) {
+	xml, err := r.d.XML()
+	if err != nil {
+		return Selector{}, uixml.Node{}, err
+	}
+	node, err := xml.Find(fn)
+	if err != nil {
+		return Selector{}, uixml.Node{}, err
+	}
+	return SelectorFor(xml, node), node, nil
+}
+
+// typeInto 点击输入框使其获得焦点，然后输入文本。
+func (d *Device) typeInto(node uixml.Node, text string) error {
+	if err := d.ClickNodeBy(node); err != nil {
+		return err
+	}
+	time.Sleep(300 * time.Millisecond)
+	return d.Input(text)
+}
+
+// replayTimeout 是回放时等待每个选择器出现的最长时间。
+const replayTimeout = 10 * time.Second
+
+// ReplaySelectors 回放 RecordUIActions 录制的选择器脚本。
+// 每个动作执行前都会重新查找选择器对应的节点（最多等待 10 秒），因此能适应不同的分辨率和加载速度。
+//
+// 参数：
+//   - script: SelectorRecorder.Script 导出的 JSON 脚本
+//
+// 返回值：
+//   - error: 如果脚本无法解析、节点未出现或操作失败，返回 error 对象，错误信息中包含动作序号
+//
+// 示例：
+//
+//	script, _ := os.ReadFile("login.json")
+//	if err := device.ReplaySelectors(script); err != nil {
+//	    log.Fatal("回放失败:", err) // 例如：step 3 (click): wait for selector ... timeout
+//	}
+func (d *Device) ReplaySelectors(script []byte) error {
+	var actions []SelectorAction
+	if err := json.Unmarshal(script, &actions); err != nil {
+		return fmt.Errorf("parse script: %w", err)
+	}
+
+	for i, a := range actions {
+		node, err := d.waitForSelector(a.Selector, replayTimeout)
+		if err == nil {
+			switch a.Action {
+			case ActionClick:
+				err = d.ClickNodeBy(node)
+			case ActionInput:
+				err = d.typeInto(node, a.Text)
+			default:
+				err = fmt.Errorf("unknown action")
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, a.Action, err)
+		}
+	}
+	return nil
+}
+
+// waitForSelector 轮询等待选择器对应的节点出现。
+func (d *Device) waitForSelector(s Selector, timeout time.Duration) (uixml.Node, error) {
+	// 无效的选择器不会因为等待而变得有效，直接返回
+	if err := s.validate(); err != nil {
+		return uixml.Node{}, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		xml, err := d.XML()
+		if err == nil {
+			var node uixml.Node
+			if node, err = s.find(xml); err == nil {
+				return node, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return uixml.Node{}, fmt.Errorf("wait for selector timeout after %s: %w", timeout, err)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
@@ -0,0 +1,153 @@
+package adb
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// selectorDump 是录制时的界面：两个没有 resource-id 的 "OK" 按钮和一个用户名输入框。
+var selectorDump = hierarchy(
+	`<node text="OK" class="android.widget.Button" clickable="true" bounds="[0,0][100,100]" />`,
+	`<node text="" resource-id="com.example:id/user" class="android.widget.EditText" clickable="true" bounds="[0,200][500,300]" />`,
+	`<node text="OK" class="android.widget.Button" clickable="true" bounds="[0,400][100,500]" />`,
+	`<node text="" content-desc="更多" class="android.widget.ImageButton" clickable="true" bounds="[400,0][500,100]" />`,
+)
+
+// changedDump 是回放时的界面：分辨率和布局都变了，前面还多了一个无关的按钮。
+var changedDump = hierarchy(
+	`<node text="Cancel" class="android.widget.Button" clickable="true" bounds="[0,0][200,100]" />`,
+	`<node text="OK" class="android.widget.Button" clickable="true" bounds="[0,100][200,200]" />`,
+	`<node text="" resource-id="com.example:id/user" class="android.widget.EditText" clickable="true" bounds="[0,300][1000,400]" />`,
+	`<node text="OK" class="android.widget.Button" clickable="true" bounds="[0,800][200,1000]" />`,
+)
+
+// taps 返回所有点击命令。
+func taps(r *fakeRunner) []string {
+	var cmds []string
+	for _, c := range r.shellCommands() {
+		if strings.HasPrefix(c, "input tap") {
+			cmds = append(cmds, c)
+		}
+	}
+	return cmds
+}
+
+func TestSelectorFor(t *testing.T) {
+	xml, err := uixml.NewXml(selectorDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := xml.FindAll(func(n, pn uixml.Node) bool { return n.Class != "" })
+
+	tests := []struct {
+		node uixml.Node
+		want Selector
+	}{
+		{nodes[0], Selector{Text: "OK", Class: "android.widget.Button"}},
+		{nodes[1], Selector{ResourceID: "com.example:id/user", Class: "android.widget.EditText"}},
+		{nodes[2], Selector{Text: "OK", Class: "android.widget.Button", Index: 1}},
+		{nodes[3], Selector{ContentDesc: "更多", Class: "android.widget.ImageButton"}},
+	}
+	for _, tt := range tests {
+		got := SelectorFor(xml, tt.node)
+		if got != tt.want {
+			t.Errorf("SelectorFor(%s) = %+v, want %+v", tt.node.Bounds, got, tt.want)
+		}
+		// 生成的选择器在录制时的界面上定位回同一个节点
+		if n, err := got.find(xml); err != nil || n.Key() != tt.node.Key() {
+			t.Errorf("selector %+v found %s, %v; want %s", got, n.Bounds, err, tt.node.Bounds)
+		}
+	}
+}
+
+func TestSelectorRecorderScriptRoundTrip(t *testing.T) {
+	d, r := newFakeDevice(func(command string) (string, error) {
+		if command == dumpCommand {
+			return selectorDump, nil
+		}
+		return "", nil
+	})
+	rec := d.RecordUIActions()
+	if err := rec.Click(func(n, pn uixml.Node) bool { return n.Text == "OK" && n.Bounds == "[0,400][100,500]" }); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.TypeInto(func(n, pn uixml.Node) bool { return n.Class == "android.widget.EditText" }, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.ClickNode("", "更多"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := taps(r), []string{"input tap 50 450", "input tap 250 250", "input tap 450 50"}; !slices.Equal(got, want) {
+		t.Errorf("taps = %q, want %q", got, want)
+	}
+
+	script, err := rec.Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SelectorAction{
+		{Action: ActionClick, Selector: Selector{Text: "OK", Class: "android.widget.Button", Index: 1}},
+		{Action: ActionInput, Selector: Selector{ResourceID: "com.example:id/user", Class: "android.widget.EditText"}, Text: "alice"},
+		{Action: ActionClick, Selector: Selector{ContentDesc: "更多", Class: "android.widget.ImageButton"}},
+	}
+	var got []SelectorAction
+	if err := json.Unmarshal(script, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("script decoded to %+v, want %+v", got, want)
+	}
+	if !strings.Contains(string(script), `"resource_id": "com.example:id/user"`) || strings.Contains(string(script), `"index": 0`) {
+		t.Errorf("unexpected script JSON:\n%s", script)
+	}
+}
+
+func TestReplaySelectorsOnChangedDump(t *testing.T) {
+	script, err := json.Marshal([]SelectorAction{
+		{Action: ActionClick, Selector: Selector{Text: "OK", Class: "android.widget.Button", Index: 1}},
+		{Action: ActionInput, Selector: Selector{ResourceID: "com.example:id/user", Class: "android.widget.EditText"}, Text: "alice"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, r := newFakeDevice(func(command string) (string, error) {
+		if command == dumpCommand {
+			return changedDump, nil
+		}
+		return "", nil
+	})
+	if err := d.ReplaySelectors(script); err != nil {
+		t.Fatal(err)
+	}
+	// 按新界面上的位置点击：第二个 "OK" 和输入框
+	if got, want := taps(r), []string{"input tap 100 900", "input tap 500 350"}; !slices.Equal(got, want) {
+		t.Errorf("taps = %q, want %q", got, want)
+	}
+}
+
+func TestReplaySelectorsNotFound(t *testing.T) {
+	d, _ := newFakeDevice(func(command string) (string, error) {
+		if command == dumpCommand {
+			return changedDump, nil
+		}
+		return "", nil
+	})
+
+	// 回放时的界面上只有两个 "OK"，录制时的第三个找不到
+	_, err := d.waitForSelector(Selector{Text: "OK", Index: 2}, 20*time.Millisecond)
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "wait for selector timeout") {
+		t.Errorf("waitForSelector = %v, want a timeout wrapping ErrNotFound", err)
+	}
+
+	script := []byte(`[{"action":"click","selector":{"text":"Cancel"}},{"action":"click","selector":{"text":"OK","index":-1}}]`)
+	if err := d.ReplaySelectors(script); err == nil || !strings.HasPrefix(err.Error(), "step 2 (click): ") {
+		t.Errorf("ReplaySelectors = %v, want an error naming step 2", err)
+	}
+}
@@ -0,0 +1,191 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetSetting 读取系统设置（settings get）。
+//
+// 参数：
+//   - namespace: 设置的命名空间，取值为 "system"、"secure" 或 "global"
+//   - key: 设置项名称，例如 "auto_time"、"screen_off_timeout"
+//
+// 返回值：
+//   - string: 设置值；设置项不存在时返回空字符串
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 示例：
+//
+//	timeout, err := device.GetSetting("system", "screen_off_timeout")
+//	fmt.Println("息屏时间(ms):", timeout)
+func (d *Device) GetSetting(namespace, key string) (string, error) {
+	output, err := d.Shellf("settings get %s %s", namespace, key)
+	if err != nil {
+		return "", err
+	}
+	// 不存在的设置项输出 "null"
+	if output == "null" {
+		return "", nil
+	}
+	return output, nil
+}
+
+// PutSetting 修改系统设置（settings put）。
+//
+// 参数：
+//   - namespace: 设置的命名空间，取值为 "system"、"secure" 或 "global"
+//   - key: 设置项名称
+//   - value: 新的值
+//
+// 返回值：
+//   - error: 如果命令执行失败或没有权限，返回 error 对象
+//
+// 注意事项：
+//   - shell 用户可以修改大部分 global 和 secure 设置，部分受保护的设置项需要 root
+//
+// 示例：
+//
+//	// 息屏时间改为 10 分钟
+//	err := device.PutSetting("system", "screen_off_timeout", "600000")
+func (d *Device) PutSetting(namespace, key, value string) error {
+	output, err := d.Shellf("settings put %s %s %s", namespace, key, value)
+	if err != nil {
+		return err
+	}
+	// 成功时没有输出，失败时通常输出异常信息
+	if output != "" && (strings.Contains(output, "Exception") || strings.Contains(output, "Error")) {
+		return fmt.Errorf("put setting %s/%s failed: %s", namespace, key, output)
+	}
+	return nil
+}
+
+// animationScales 是开发者选项中三个动画缩放设置项的名称（global 命名空间）。
+var animationScales = []string{"window_animation_scale", "transition_animation_scale", "animator_duration_scale"}
+
+// DisableAnimations 关闭系统动画（窗口动画、过渡动画和动画时长缩放都设为 0），
+// 减少 UI 测试中因动画未结束导致的点击落空和截图不一致。
+//
+// 返回值：
+//   - error: 如果修改设置失败，返回 error 对象
+//
+// 注意事项：
+//   - 修改成功后会记录原来的值，调用 Cleanup 时恢复
+//   - 已经在运行的应用可能需要重启 Activity 才会读取新的动画时长
+//
+// 示例：
+//
+//	if err := device.DisableAnimations(); err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer device.Cleanup()
+func (d *Device) DisableAnimations() error {
+	prev := make([]string, len(animationScales))
+	for i, key := range animationScales {
+		value, err := d.GetSetting("global", key)
+		if err != nil {
+			return err
+		}
+		prev[i] = value
+	}
+	for _, key := range animationScales {
+		if err := d.PutSetting("global", key, "0"); err != nil {
+			return err
+		}
+	}
+	d.pushUndo("animations", func() error {
+		for i, key := range animationScales {
+			if err := d.restoreSetting("global", key, prev[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+// SettingsPage 表示系统设置中的一个页面，配合 OpenSettings 使用。
+// 通过预定义的 Settings* 变量或 SettingsAppDetails 等函数获取。
+type SettingsPage struct {
+	action string // android.settings.* Intent action
+	data   string // Intent data URI，可以为空
+	extra  string // 附加的 "-e key value" 参数，可以为空
+	minSDK int    // 支持该页面的最低 API 级别
+}
+
+// 常用的系统设置页面。
+var (
+	SettingsMain          = SettingsPage{action: "android.settings.SETTINGS"}
+	SettingsWifi          = SettingsPage{action: "android.settings.WIFI_SETTINGS"}
+	SettingsBluetooth     = SettingsPage{action: "android.settings.BLUETOOTH_SETTINGS"}
+	SettingsApps          = SettingsPage{action: "android.settings.APPLICATION_SETTINGS"}
+	SettingsAccessibility = SettingsPage{action: "android.settings.ACCESSIBILITY_SETTINGS"}
+	SettingsDeveloper     = SettingsPage{action: "android.settings.APPLICATION_DEVELOPMENT_SETTINGS"}
+	SettingsDate          = SettingsPage{action: "android.settings.DATE_SETTINGS"}
+	SettingsLocale        = SettingsPage{action: "android.settings.LOCALE_SETTINGS"}
+	SettingsLocation      = SettingsPage{action: "android.settings.LOCATION_SOURCE_SETTINGS"}
+	SettingsDisplay       = SettingsPage{action: "android.settings.DISPLAY_SETTINGS"}
+)
+
+// SettingsAppDetails 返回应用详情页（可强行停止、清除数据、管理权限）。
+func SettingsAppDetails(pkg string) SettingsPage {
+	return SettingsPage{action: "android.settings.APPLICATION_DETAILS_SETTINGS", data: "package:" + pkg}
+}
+
+// SettingsAppNotifications 返回应用的通知设置页，需要 Android 8.0+。
+func SettingsAppNotifications(pkg string) SettingsPage {
+	return SettingsPage{action: "android.settings.APP_NOTIFICATION_SETTINGS", extra: "android.provider.extra.APP_PACKAGE " + pkg, minSDK: 26}
+}
+
+// OpenSettings 打开系统设置中的指定页面，不需要记住各个 Intent action。
+//
+// 参数：
+//   - page: 要打开的页面，例如 SettingsWifi、SettingsAppDetails("com.example.app")
+//
+// 返回值：
+//   - error: 如果当前系统版本不支持该页面（错误包装了 ErrUnsupported）或启动失败，返回 error 对象
+//
+// 工作原理：
+//   - 执行 'am start -a <action> [-d <data>] [-e <key> <value>]'
+//   - 设备上没有处理该 action 的界面时（部分厂商裁剪了设置页面），am 输出 "unable to resolve Intent"，返回 ErrUnsupported
+//
+// 示例：
+//
+//	// 打开应用详情页，准备手动授予权限
+//	if err := device.OpenSettings(adb.SettingsAppDetails("com.example.app")); err != nil {
+//	    log.Fatal(err)
+//	}
+func (d *Device) OpenSettings(page SettingsPage) error {
+	if page.action == "" {
+		return fmt.Errorf("empty settings page")
+	}
+	if page.minSDK > 0 {
+		sdk, err := d.SDKLevel()
+		if err != nil {
+			return err
+		}
+		if sdk < page.minSDK {
+			return fmt.Errorf("settings page %s requires API %d, device is API %d: %w", page.action, page.minSDK, sdk, ErrUnsupported)
+		}
+	}
+
+	command := "am start -a " + page.action
+	if page.data != "" {
+		command += " -d " + shellQuote(page.data)
+	}
+	if key, value, ok := strings.Cut(page.extra, " "); ok {
+		command += " -e " + key + " " + shellQuote(value)
+	}
+	output, err := d.Shell(command)
+	text := output + errString(err)
+	if strings.Contains(text, "unable to resolve Intent") {
+		return fmt.Errorf("settings page %s: %w", page.action, ErrUnsupported)
+	}
+	if err != nil {
+		return err
+	}
+	if strings.Contains(output, "Error") {
+		return fmt.Errorf("open settings %s failed: %s", page.action, output)
+	}
+	return nil
+}
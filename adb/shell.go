@@ -2,7 +2,9 @@ package adb
 
 import (
 	"fmt"
+	"io"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -29,7 +31,8 @@ import (
 //   - 某些命令可能需要 root 权限才能执行
 //   - 命令中的特殊字符（如引号、反斜杠）需要正确转义
 //   - 长时间运行的命令可能会导致超时
-//   - 返回值会自动去除首尾空白字符
+//   - 返回值会自动去除首尾空白字符，这是便于处理文本输出的便捷形式
+//   - 如果输出的首尾空白有意义（例如需要逐字节比较文件内容），请使用 ShellRaw
 //
 // 示例：
 //
@@ -64,6 +67,194 @@ func (d *Device) Shell(command string) (string, error) {
 	return d.execCommand("shell", command)
 }
 
+// ShellTimeout 执行 shell 命令，只对这一次调用使用指定的超时时间，不修改设备的默认设置。
+//
+// 参数：
+//   - command: 要执行的 shell 命令
+//   - timeout: 本次调用的超时时间
+//
+// 返回值：
+//   - string: 命令输出（与 Shell 相同，已去除首尾空白）
+//   - error: 如果命令执行失败或超时，返回 error 对象
+//
+// 注意事项：
+//   - 超时的优先级：本次调用的 timeout > WithTimeout 设置的默认超时 > 不限制；
+//     timeout 不大于 0 时使用默认超时
+//   - timeout 可以比默认超时更长或更短，例如默认 30 秒时为一条耗时的命令单独放宽到 5 分钟
+//
+// 示例：
+//
+//	// 设备默认 30 秒超时，这条命令允许运行 10 分钟
+//	output, err := device.ShellTimeout("dumpsys -t 600 meminfo", 10*time.Minute)
+func (d *Device) ShellTimeout(command string, timeout time.Duration) (string, error) {
+	return d.withTimeout(timeout).Shell(command)
+}
+
+// Shellf 按格式构造 shell 命令并执行，所有字符串参数在代入前都会用单引号转义。
+// 用它代替 Shell(fmt.Sprintf(...))，路径、文本等参数中含有空格、引号、分号、$() 等字符时
+// 也只会被当作一个普通参数，不会被 shell 解释。
+//
+// 参数：
+//   - format: fmt 格式字符串，字符串参数使用 %s
+//   - args: 格式参数，string、[]byte 和 fmt.Stringer 会被转义，数字等其他类型原样格式化
+//
+// 返回值：
+//   - string: 命令输出（与 Shell 相同，已去除首尾空白）
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - 转义后的参数带有单引号，不要再在 format 中给 %s 加引号，也不要使用 %q
+//   - 需要把参数作为 shell 语法原样插入（例如拼接管道或通配符）时，请自行构造命令并使用 Shell
+//
+// 示例：
+//
+//	// 路径中的空格和特殊字符是安全的
+//	content, err := device.Shellf("cat %s", "/sdcard/my file; rm -rf /sdcard")
+//	// 实际执行：cat '/sdcard/my file; rm -rf /sdcard'
+//
+//	device.Shellf("input swipe %d %d %d %d", 100, 800, 100, 200)
+func (d *Device) Shellf(format string, args ...interface{}) (string, error) {
+	quoted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			quoted[i] = shellQuote(v)
+		case []byte:
+			quoted[i] = shellQuote(string(v))
+		case fmt.Stringer:
+			quoted[i] = shellQuote(v.String())
+		default:
+			quoted[i] = arg
+		}
+	}
+	return d.Shell(fmt.Sprintf(format, quoted...))
+}
+
+// ShellRaw 在设备上执行 shell 命令，并原样返回标准输出。
+// 与 Shell 不同，该方法不会去除首尾空白，也不会混入标准错误的内容。
+//
+// 参数：
+//   - command: 要在设备上执行的 shell 命令字符串
+//
+// 返回值：
+//   - string: 命令的原始标准输出（保留所有空白和换行）
+//   - error: 如果命令执行失败，返回包含标准错误内容的 error 对象
+//
+// 使用场景：
+//   - 读取需要逐字节一致的文件内容（cat）
+//   - 输出首尾空白有意义的命令（printf、echo -n）
+//   - 需要把标准错误和标准输出分开处理的场景
+//
+// 注意事项：
+//   - 较新的 adb 会通过 shell 协议保持换行符不变；非常旧的设备可能会把 \n 转换为 \r\n
+//
+// 示例：
+//
+//	// 空格会被完整保留
+//	output, _ := device.ShellRaw("printf '  x  '")
+//	fmt.Printf("%q\n", output) // "  x  "
+//
+//	// 读取文件原始内容
+//	content, err := device.ShellRaw("cat /sdcard/config.json")
+func (d *Device) ShellRaw(command string) (string, error) {
+	output, err := d.execRaw("shell", command)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// Result 是 ShellResult 的执行结果。
+type Result struct {
+	Stdout   string // 标准输出（原样保留）
+	Stderr   string // 标准错误；设备不支持 shell 协议（Android 7.0 以前）时混在 Stdout 中，此处为空
+	ExitCode int    // 命令的退出码
+}
+
+// exitCodeMarker 标记 ShellResult 追加在输出末尾的退出码。
+const exitCodeMarker = "__adb_exit_code:"
+
+// ShellResult 在设备上执行 shell 命令，分别返回标准输出、标准错误和退出码。
+// Shell 把退出码非 0 的命令都当作失败，而 test、grep 等命令用退出码 1 表示 "否"，
+// 需要根据退出码分支时使用该方法。
+//
+// 参数：
+//   - command: 要在设备上执行的 shell 命令字符串
+//
+// 返回值：
+//   - Result: 命令的输出和退出码；退出码非 0 不视为错误
+//   - error: 只在 adb 本身失败（设备断开、超时等）或无法读取退出码时返回 error 对象
+//
+// 工作原理：
+//   - 命令在子 shell 中执行，之后追加 'echo $?' 输出退出码，因此不依赖 adb 的 shell 协议，
+//     旧设备上也能得到正确的退出码
+//   - 命令中调用 exit 只会结束子 shell
+//
+// 示例：
+//
+//	r, err := device.ShellResult("grep -q ERROR /sdcard/app.log")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	switch r.ExitCode {
+//	case 0:
+//	    fmt.Println("日志中有错误")
+//	case 1:
+//	    fmt.Println("日志中没有错误")
+//	default:
+//	    fmt.Println("grep 失败:", r.Stderr)
+//	}
+func (d *Device) ShellResult(command string) (Result, error) {
+	// 退出码单独输出在新的一行，无论命令的输出是否以换行结尾都能准确切分
+	script := fmt.Sprintf("(\n%s\n); printf '\\n%s%%d\\n' $?", command, exitCodeMarker)
+	stdout, stderr, err := d.run(nil, "shell", script)
+	if err != nil {
+		return Result{}, fmt.Errorf("adb command failed: %w, output: %s", err, string(stderr))
+	}
+	out := strings.ReplaceAll(string(stdout), "\r\n", "\n")
+	i := strings.LastIndex(out, "\n"+exitCodeMarker)
+	if i < 0 {
+		return Result{}, fmt.Errorf("exit code not found in output: %s", truncate(out, 200))
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(out[i+1+len(exitCodeMarker):]))
+	if err != nil {
+		return Result{}, fmt.Errorf("bad exit code in output: %s", truncate(out[i:], 100))
+	}
+	return Result{Stdout: out[:i], Stderr: string(stderr), ExitCode: code}, nil
+}
+
+// ShellStdin 在设备上执行 shell 命令，并把 stdin 中的数据作为命令的标准输入。
+// 适用于需要从标准输入读取数据的命令，例如 sqlite3、sh、cat > file 等。
+//
+// 参数：
+//   - command: 要在设备上执行的 shell 命令字符串
+//   - stdin: 提供标准输入数据的 io.Reader，读到 EOF 后设备端命令的标准输入随之关闭
+//
+// 返回值：
+//   - string: 命令的输出（已去除首尾空白字符）
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - 需要设备和 adb 支持 shell 协议（Android 7.0+），旧版本可能不会把 EOF 传递给设备端命令
+//   - 二进制数据可以安全传输，但输出按文本处理
+//
+// 示例：
+//
+//	// 通过 cat 回显输入
+//	output, err := device.ShellStdin("cat", strings.NewReader("hello"))
+//	fmt.Println(output) // hello
+//
+//	// 对应用数据库执行 SQL 脚本
+//	script, _ := os.Open("migrate.sql")
+//	defer script.Close()
+//	output, err = device.ShellStdin("run-as com.example.app sqlite3 databases/app.db", script)
+//
+//	// 把本地内容写入设备文件
+//	_, err = device.ShellStdin("cat > /sdcard/config.json", bytes.NewReader(data))
+func (d *Device) ShellStdin(command string, stdin io.Reader) (string, error) {
+	return d.execStdin(stdin, "shell", command)
+}
+
 // Tap 在设备屏幕的指定坐标处模拟点击操作。
 // 该方法通过 'input tap' 命令实现屏幕点击，可用于自动化测试和UI交互。
 //
@@ -91,6 +282,7 @@ func (d *Device) Shell(command string) (string, error) {
 //   - 某些应用可能会检测并阻止模拟点击
 //   - 如果屏幕被锁定，点击可能无效
 //   - 建议在点击后添加适当的延时，等待UI响应
+//   - 开启 RotateCoords 后，坐标按竖屏理解并自动转换到当前旋转方向
 //
 // 示例：
 //
@@ -114,6 +306,18 @@ func (d *Device) Shell(command string) (string, error) {
 //	    time.Sleep(500 * time.Millisecond) // 每次点击后等待
 //	}
 func (d *Device) Tap(x, y int) error {
+	if d.RotateCoords {
+		var err error
+		if x, y, err = d.RotatePoint(x, y); err != nil {
+			return err
+		}
+	}
+	return d.tap(x, y)
+}
+
+// tap 在当前屏幕坐标系下点击，不做旋转转换。
+// 从 UI 节点得到的坐标已经处于当前旋转方向，内部点击节点时使用该方法。
+func (d *Device) tap(x, y int) error {
 	// 构建 input tap 命令
 	command := fmt.Sprintf("input tap %d %d", x, y)
 	_, err := d.Shell(command)
@@ -156,6 +360,7 @@ func (d *Device) Tap(x, y int) error {
 //   - duration 为 0 时表示瞬间完成滑动
 //   - 滑动距离太短可能被识别为点击
 //   - 某些应用可能检测并限制模拟滑动
+//   - 开启 RotateCoords 后，起止坐标按竖屏理解并自动转换到当前旋转方向
 //
 // 示例：
 //
@@ -181,12 +386,99 @@ func (d *Device) Tap(x, y int) error {
 //	// 慢速滑动（精确控制）
 //	err = device.Swipe(500, 1500, 500, 500, 1000)
 func (d *Device) Swipe(x1, y1, x2, y2, duration int32) error {
+	if d.RotateCoords {
+		rotation, w, h, err := d.rotationAndSize()
+		if err != nil {
+			return err
+		}
+		a, b := rotatePoint(int(x1), int(y1), rotation, w, h)
+		c, e := rotatePoint(int(x2), int(y2), rotation, w, h)
+		x1, y1, x2, y2 = int32(a), int32(b), int32(c), int32(e)
+	}
+	return d.swipe(int(x1), int(y1), int(x2), int(y2), int(duration))
+}
+
+// swipe 按屏幕当前方向的坐标执行滑动，不做旋转转换，duration 单位为毫秒。
+// 从 UI 节点计算出的坐标已经是当前方向的坐标，应直接使用该方法。
+func (d *Device) swipe(x1, y1, x2, y2, duration int) error {
 	// 构建 input swipe 命令
 	command := fmt.Sprintf("input swipe %d %d %d %d %d", x1, y1, x2, y2, duration)
 	_, err := d.Shell(command)
 	return err
 }
 
+// defaultLongPressDuration 是 LongPress 在 duration 为 0 时的按住时间，
+// 略长于系统默认的长按判定时间（ViewConfiguration 的 500 毫秒）。
+const defaultLongPressDuration = 800 * time.Millisecond
+
+// LongPress 在指定坐标长按。
+//
+// 参数：
+//   - x, y: 长按坐标（像素）
+//   - duration: 按住的时间，为 0 时使用 800 毫秒
+//
+// 返回值：
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - 通过起点和终点相同的 'input swipe' 实现，所有 Android 版本可用
+//   - 开启 RotateCoords 后，坐标按竖屏理解并自动转换到当前旋转方向
+//
+// 示例：
+//
+//	// 长按桌面图标弹出快捷菜单
+//	err := device.LongPress(200, 1600, 0)
+func (d *Device) LongPress(x, y int, duration time.Duration) error {
+	if d.RotateCoords {
+		var err error
+		if x, y, err = d.RotatePoint(x, y); err != nil {
+			return err
+		}
+	}
+	return d.longPress(x, y, duration)
+}
+
+// longPress 在当前屏幕坐标系下长按，不做旋转转换。
+func (d *Device) longPress(x, y int, duration time.Duration) error {
+	if duration <= 0 {
+		duration = defaultLongPressDuration
+	}
+	return d.swipe(x, y, x, y, int(duration.Milliseconds()))
+}
+
+// DoubleTap 在指定坐标双击。
+//
+// 参数：
+//   - x, y: 双击坐标（像素）
+//
+// 返回值：
+//   - error: 如果命令执行失败，返回 error 对象
+//
+// 注意事项：
+//   - 两次 'input tap' 在同一条 shell 命令中执行，避免两次 adb 往返的延迟；
+//     系统的双击判定间隔为 300 毫秒，在非常慢的设备上仍可能被识别为两次单击
+//   - 开启 RotateCoords 后，坐标按竖屏理解并自动转换到当前旋转方向
+//
+// 示例：
+//
+//	// 双击图片放大
+//	err := device.DoubleTap(540, 960)
+func (d *Device) DoubleTap(x, y int) error {
+	if d.RotateCoords {
+		var err error
+		if x, y, err = d.RotatePoint(x, y); err != nil {
+			return err
+		}
+	}
+	return d.doubleTap(x, y)
+}
+
+// doubleTap 在当前屏幕坐标系下双击，不做旋转转换。
+func (d *Device) doubleTap(x, y int) error {
+	_, err := d.Shell(fmt.Sprintf("input tap %d %d; input tap %d %d", x, y, x, y))
+	return err
+}
+
 // Input 向当前焦点的输入框发送文本内容。
 // 该方法通过广播机制实现文本输入，支持包含空格和特殊字符的文本。
 //
@@ -245,15 +537,20 @@ func (d *Device) Swipe(x1, y1, x2, y2, duration int32) error {
 //	// 5. 点击登录按钮
 //	device.Tap(500, 1000)
 func (d *Device) Input(text string) error {
+	_, err := d.Shell(inputCommand(text))
+	return err
+}
+
+// inputCommand 构造通过 ADB Keyboard 输入文本的广播命令。
+func inputCommand(text string) string {
 	// 将空格替换为 %s 以适配 ADB input 命令格式
 	escapedText := strings.ReplaceAll(text, " ", "%s")
 	// 构建广播命令发送文本
 	// am broadcast: 发送广播
 	// -a: 指定 action（ADB_INPUT_TEXT）
 	// --es: 附加字符串数据（msg 为 key，escapedText 为 value）
-	command := fmt.Sprintf("am broadcast -a ADB_INPUT_TEXT --es msg '%s'", escapedText)
-	_, err := d.Shell(command)
-	return err
+	// 文本用 shellQuote 转义，其中的单引号、分号等不会破坏命令（SendSequence 会把多条命令用 "; " 拼接）
+	return "am broadcast -a ADB_INPUT_TEXT --es msg " + shellQuote(escapedText)
 }
 
 // KeyEvent 向设备发送指定的按键事件。
@@ -420,7 +717,7 @@ func (d *Device) PressEnter() error {
 // 该方法通过 'am start' 命令实现，可以直接启动应用的特定页面。
 //
 // 参数：
-//   - packageName: 应用的包名（例如："com.android.settings"）
+//   - packageName: 应用的包名（例如："com.android.settings"），为空时使用 WithDefaultPackage 设置的默认包名
 //   - activityName: Activity 的名称（例如：".Settings" 或完整类名）
 //     如果以 "." 开头，会自动拼接包名
 //     也可以使用完整的类名（包含包路径）
@@ -464,17 +761,17 @@ func (d *Device) PressEnter() error {
 //	device.Shell("am start -a android.intent.action.VIEW -d https://www.example.com")
 func (d *Device) StartActivity(packageName, activityName string) error {
 	// 构建 am start 命令
-	// -n: 指定组件名称（packageName/activityName）
-	command := fmt.Sprintf("am start -n %s/%s", packageName, activityName)
+	// -n: 指定组件名称（packageName/activityName），包名为空时使用默认包名
+	command := fmt.Sprintf("am start -n %s/%s", d.packageOr(packageName), activityName)
 	_, err := d.Shell(command)
 	return err
 }
 
 // ForceStopApp 强制停止指定应用的所有进程。
-// 该方法通过 'am force-stop' 命令实现，会完全终止应用。
+// 该方法通过 'am force-stop' 命令实现（Android 7.0+ 使用更快的 'cmd activity force-stop'），会完全终止应用。
 //
 // 参数：
-//   - packageName: 要停止的应用包名（例如："com.example.app"）
+//   - packageName: 要停止的应用包名（例如："com.example.app"），为空时使用默认包名
 //
 // 返回值：
 //   - error: 如果停止操作失败，返回 error 对象
@@ -518,7 +815,13 @@ func (d *Device) StartActivity(packageName, activityName string) error {
 //	    device.ForceStopApp(pkg)
 //	}
 func (d *Device) ForceStopApp(packageName string) error {
-	// 构建 am force-stop 命令
+	packageName = d.packageOr(packageName)
+	// 支持 cmd 的设备直接调用 activity 服务，避免启动 am 进程
+	if d.useCmd() {
+		_, err := d.Cmd("activity", "force-stop", packageName)
+		return err
+	}
+	// 构建 am force-stop 命令，包名为空时使用默认包名
 	command := fmt.Sprintf("am force-stop %s", packageName)
 	_, err := d.Shell(command)
 	return err
@@ -591,7 +894,7 @@ func (d *Device) Pull(devicePath, localPath string) error {
 //   - 需要有写入设备路径的权限
 //   - 某些系统目录可能需要 root 权限
 //   - 本地文件必须存在
-//   - 设备存储空间必须充足
+//   - 设备存储空间必须充足，设置 WithStorageCheck 后空间不足时会在传输前返回 ErrInsufficientStorage
 //   - 文件已存在时会被覆盖
 //   - /sdcard/ 通常是普通应用可写的位置
 //
@@ -612,11 +915,21 @@ func (d *Device) Pull(devicePath, localPath string) error {
 //	// 推送整个目录
 //	err = device.Push("./test_files/", "/sdcard/test_files/")
 func (d *Device) Push(localPath, devicePath string) error {
+	// 设置了 WithStorageCheck 时先检查可用空间
+	if err := d.checkStorage(devicePath, localPath); err != nil {
+		return err
+	}
 	// 执行 adb push 命令
 	_, err := d.execCommand("push", localPath, devicePath)
 	return err
 }
 
+// PullTimeout 与 Pull 相同，但只对这一次调用使用指定的超时时间，适用于拉取大文件。
+// 超时的优先级与 ShellTimeout 相同：本次调用的 timeout > 默认超时 > 不限制。
+func (d *Device) PullTimeout(devicePath, localPath string, timeout time.Duration) error {
+	return d.withTimeout(timeout).Pull(devicePath, localPath)
+}
+
 // Connect 通过 TCP/IP 网络连接到指定地址的 Android 设备。
 // 该方法通过 'adb connect' 命令实现无线 ADB 连接。
 //
@@ -750,3 +1063,41 @@ func (d *Device) GetClipper() (string, error) {
 	clipText := strings.TrimSpace(parts[1])
 	return clipText, nil
 }
+
+// SetClipboard 设置设备剪贴板的文本内容。
+// 与 GetClipper 一样，该方法依赖 Clipper 应用（ca.zgrs.clipper）。
+//
+// 参数：
+//   - text: 要写入剪贴板的文本，支持中文、emoji 等任意 Unicode 字符
+//
+// 返回值：
+//   - error: 如果写入失败，返回 error 对象
+//
+// 注意事项：
+//   - 需要先安装 Clipper 应用（init.sh 会自动安装）
+//   - Android 10+ 限制后台应用访问剪贴板，因此会先启动 Clipper 到前台
+//   - 文本会经过 shell 转义，可以包含引号、空格等特殊字符
+//
+// 示例：
+//
+//	if err := device.SetClipboard("你好，世界"); err != nil {
+//	    log.Fatal("设置剪贴板失败:", err)
+//	}
+func (d *Device) SetClipboard(text string) error {
+	// 启动 Clipper 应用，使其处于前台以便访问剪贴板
+	err := d.StartActivity("ca.zgrs.clipper", "ca.zgrs.clipper.Main")
+	if err != nil {
+		return err
+	}
+	time.Sleep(1 * time.Second)
+
+	output, err := d.Shellf("am broadcast -a clipper.set -e text %s", text)
+	if err != nil {
+		return err
+	}
+	// Clipper 成功时返回 RESULT_OK（-1）
+	if !strings.Contains(output, "result=-1") {
+		return fmt.Errorf("unexpected output: %s", output)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package adb
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAdb 在 PATH 最前面放一个伪造的 adb 脚本：shell 和 exec-out 命令直接交给本机的 sh 执行，
+// 不连接设备也能检查命令经过设备 shell 之后的实际效果。
+func fakeAdb(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs /bin/sh")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncase \"$1\" in shell|exec-out) shift; exec sh -c \"$*\";; esac\n"
+	if err := os.WriteFile(filepath.Join(dir, "adb"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestShellRawPreservesWhitespace(t *testing.T) {
+	fakeAdb(t)
+	d := NewDevice()
+
+	raw, err := d.ShellRaw("printf '  x  \\n'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw != "  x  \n" {
+		t.Errorf("ShellRaw = %q, want %q", raw, "  x  \n")
+	}
+	trimmed, err := d.Shell("printf '  x  \\n'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trimmed != "x" {
+		t.Errorf("Shell = %q, want %q", trimmed, "x")
+	}
+}
+
+func TestShellStdinPipesThroughCat(t *testing.T) {
+	fakeAdb(t)
+	d := NewDevice()
+	input := "line 1\nline 2 with 'quotes'\n" + strings.Repeat("x", 100000)
+
+	// cat 只有在标准输入关闭后才会退出，命令返回说明管道已正确关闭
+	output, err := d.ShellStdin("cat", strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != strings.TrimSpace(input) {
+		t.Errorf("output has %d bytes, want the %d input bytes back", len(output), len(input))
+	}
+
+	output, err = d.ShellStdin("wc -l", strings.NewReader("a\nb\nc\n"))
+	if err != nil || output != "3" {
+		t.Errorf("ShellStdin(wc -l) = %q, %v; want 3", output, err)
+	}
+}
+
+func TestShellfQuotesArguments(t *testing.T) {
+	d, r := newFakeDevice(nil)
+	d.Shellf("input swipe %d %d %d %d %s", 1, 2, 3, 4, "a b")
+	d.Shellf("cat %s %s", []byte("x y"), Above)
+	want := []string{"input swipe 1 2 3 4 'a b'", "cat 'x y' 'above'"}
+	if got := r.shellCommands(); !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
+
+func TestShellfAdversarialInput(t *testing.T) {
+	fakeAdb(t)
+	d := NewDeviceWithOptions(WithTimeout(10 * time.Second))
+	pwned := filepath.Join(t.TempDir(), "pwned")
+
+	inputs := []string{
+		"my file; rm -rf /sdcard",
+		"it's",
+		"'; touch " + pwned + "; '",
+		`"double" and \backslash\`,
+		"$(touch " + pwned + ")",
+		"`touch " + pwned + "`",
+		"${HOME} $0 $$",
+		"a && touch " + pwned + " || b",
+		"> " + pwned,
+		"*",
+		"-n",
+		"%s %d %%",
+		"line 1\nline 2",
+		"中文 ✓",
+		"",
+	}
+	for _, in := range inputs {
+		// 方括号用于确认参数是一个整体，首尾空白也不会被 Shell 去掉
+		output, err := d.Shellf("printf '[%%s]' %s", in)
+		if err != nil {
+			t.Errorf("Shellf(%q) error: %v", in, err)
+			continue
+		}
+		if output != "["+in+"]" {
+			t.Errorf("Shellf(%q) printed %q", in, output)
+		}
+	}
+	if _, err := os.Stat(pwned); err == nil {
+		t.Error("an argument was interpreted by the shell")
+	}
+}
+
+func TestShellResultExitCodes(t *testing.T) {
+	fakeAdb(t)
+	d := NewDeviceWithOptions(WithTimeout(10 * time.Second))
+	tests := []struct {
+		command string
+		want    Result
+	}{
+		{"echo found", Result{Stdout: "found\n", ExitCode: 0}},
+		{"printf 'no newline'; false", Result{Stdout: "no newline", ExitCode: 1}},
+		{"echo usage >&2; exit 2", Result{Stderr: "usage\n", ExitCode: 2}},
+	}
+	for _, tt := range tests {
+		r, err := d.ShellResult(tt.command)
+		if err != nil {
+			t.Errorf("ShellResult(%q) error: %v", tt.command, err)
+			continue
+		}
+		if r != tt.want {
+			t.Errorf("ShellResult(%q) = %+v, want %+v", tt.command, r, tt.want)
+		}
+	}
+}
+
+func TestFileExistsUsesExitCode(t *testing.T) {
+	fakeAdb(t)
+	d := NewDeviceWithOptions(WithTimeout(10 * time.Second))
+	dir := t.TempDir()
+	if ok, err := d.FileExists(dir); err != nil || !ok {
+		t.Errorf("FileExists(existing) = %v, %v; want true", ok, err)
+	}
+	if ok, err := d.FileExists(filepath.Join(dir, "missing")); err != nil || ok {
+		t.Errorf("FileExists(missing) = %v, %v; want false without error", ok, err)
+	}
+}
@@ -0,0 +1,327 @@
+package adb
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// Direction 表示屏幕上的方向，用于空间关系查找等操作。
+type Direction int
+
+const (
+	Above   Direction = iota // 上方
+	Below                    // 下方
+	LeftOf                   // 左侧
+	RightOf                  // 右侧
+)
+
+// String 返回方向的名称。
+func (dir Direction) String() string {
+	switch dir {
+	case Above:
+		return "above"
+	case Below:
+		return "below"
+	case LeftOf:
+		return "left-of"
+	case RightOf:
+		return "right-of"
+	}
+	return fmt.Sprintf("Direction(%d)", int(dir))
+}
+
+// FindNodeNear 以锚点节点为参照，在指定方向上查找距离最近的目标节点。
+// 适用于目标元素本身没有唯一属性、但旁边有可唯一定位元素的场景，
+// 例如表格中某个标签右侧的复选框。
+//
+// 参数：
+//   - anchor: 定位锚点节点的查找函数（取第一个匹配）
+//   - direction: 目标相对锚点的方向（Above / Below / LeftOf / RightOf）
+//   - target: 候选目标节点的查找函数
+//
+// 返回值：
+//   - uixml.Node: 指定方向上中心点距离锚点中心最近的目标节点
+//   - error: 如果锚点不存在、方向非法或该方向上没有候选节点，返回 error 对象
+//
+// 判定规则：
+//   - Below: 目标中心位于锚点下边界之下
+//   - Above: 目标中心位于锚点上边界之上
+//   - RightOf: 目标中心位于锚点右边界之右
+//   - LeftOf: 目标中心位于锚点左边界之左
+//   - 满足条件的候选中，按两个中心点的直线距离取最近的一个
+//
+// 注意事项：
+//   - 锚点和目标在同一次 dump 中查找，结果一致
+//   - 锚点自身即使匹配 target 也会被排除
+//
+// 示例：
+//
+//	// 找到 "记住密码" 标签左侧的复选框
+//	checkbox, err := device.FindNodeNear(
+//	    func(n, pn uixml.Node) bool { return n.Text == "记住密码" },
+//	    adb.LeftOf,
+//	    func(n, pn uixml.Node) bool { return n.Class == "android.widget.CheckBox" },
+//	)
+//	if err == nil {
+//	    device.ClickNodeBy(checkbox)
+//	}
+func (d *Device) FindNodeNear(anchor FindNodeFunc, direction Direction, target FindNodeFunc) (uixml.Node, error) {
+	xml, err := d.XML()
+	if err != nil {
+		return uixml.Node{}, err
+	}
+	return nearestNode(xml, anchor, direction, target)
+}
+
+// FindBelow 查找锚点下方最近的目标节点，等同于 FindNodeNear(anchor, Below, target)。
+func (d *Device) FindBelow(anchor, target FindNodeFunc) (uixml.Node, error) {
+	return d.FindNodeNear(anchor, Below, target)
+}
+
+// FindAbove 查找锚点上方最近的目标节点，等同于 FindNodeNear(anchor, Above, target)。
+func (d *Device) FindAbove(anchor, target FindNodeFunc) (uixml.Node, error) {
+	return d.FindNodeNear(anchor, Above, target)
+}
+
+// FindLeftOf 查找锚点左侧最近的目标节点，等同于 FindNodeNear(anchor, LeftOf, target)。
+func (d *Device) FindLeftOf(anchor, target FindNodeFunc) (uixml.Node, error) {
+	return d.FindNodeNear(anchor, LeftOf, target)
+}
+
+// FindRightOf 查找锚点右侧最近的目标节点，等同于 FindNodeNear(anchor, RightOf, target)。
+func (d *Device) FindRightOf(anchor, target FindNodeFunc) (uixml.Node, error) {
+	return d.FindNodeNear(anchor, RightOf, target)
+}
+
+// nearestNode 在已解析的 UI 树中执行空间关系查找。
+func nearestNode(xml *uixml.Xml, anchor FindNodeFunc, direction Direction, target FindNodeFunc) (uixml.Node, error) {
+	a, err := xml.Find(anchor)
+	if err != nil {
+		return uixml.Node{}, fmt.Errorf("anchor: %w", err)
+	}
+	ar, err := uixml.ParseBounds(a.Bounds)
+	if err != nil {
+		return uixml.Node{}, fmt.Errorf("anchor: %w", err)
+	}
+	ax, ay := ar.Center()
+
+	var best uixml.Node
+	bestDist := math.MaxFloat64
+	for _, n := range xml.FindAll(target) {
+		if n.Key() == a.Key() {
+			continue
+		}
+		r, err := uixml.ParseBounds(n.Bounds)
+		if err != nil {
+			continue
+		}
+		x, y := r.Center()
+
+		var ok bool
+		switch direction {
+		case Above:
+			ok = y <= ar.Y1
+		case Below:
+			ok = y >= ar.Y2
+		case LeftOf:
+			ok = x <= ar.X1
+		case RightOf:
+			ok = x >= ar.X2
+		default:
+			return uixml.Node{}, fmt.Errorf("bad direction %v", direction)
+		}
+		if !ok {
+			continue
+		}
+
+		if dist := math.Hypot(float64(x-ax), float64(y-ay)); dist < bestDist {
+			best, bestDist = n, dist
+		}
+	}
+
+	if bestDist == math.MaxFloat64 {
+		return uixml.Node{}, fmt.Errorf("no node %s anchor: %w", direction, ErrNotFound)
+	}
+	return best, nil
+}
+
+// swipeAwayMargin 是 SwipeAway 在节点宽度/高度之外额外滑动的距离（像素）。
+const swipeAwayMargin = 100
+
+// SwipeAway 把节点向指定方向滑出，用于滑动删除卡片、清除单条通知等可滑动关闭的界面。
+// 从节点中心出发，沿指定方向滑动 "节点宽度（或高度）+ 边距" 的距离，
+// 终点不超过内容区域（ContentBounds）的边缘，避免滑到屏幕边缘时触发返回手势；无法获取内容区域时不超过屏幕边缘。
+//
+// 参数：
+//   - node: 要滑走的节点
+//   - direction: 滑动方向（LeftOf 向左、RightOf 向右、Above 向上、Below 向下）
+//   - duration: 滑动时长，过短可能被识别为点击，通常 200-500 毫秒
+//
+// 返回值：
+//   - error: 如果方向非法、节点边界无效或超出屏幕，返回 error 对象
+//
+// 注意事项：
+//   - 屏幕尺寸在首次调用后缓存
+//   - 节点坐标处于当前方向，因此总是读取当前旋转角度，横屏时交换宽高（与 RotateCoords 无关）
+//
+// 示例：
+//
+//	// 向右滑动删除第一条通知
+//	node, err := device.FindNode(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.android.systemui:id/expandableNotificationRow"
+//	})
+//	if err == nil {
+//	    device.SwipeAway(node, adb.RightOf, 300*time.Millisecond)
+//	}
+func (d *Device) SwipeAway(node uixml.Node, direction Direction, duration time.Duration) error {
+	r, err := uixml.ParseBounds(node.Bounds)
+	if err != nil {
+		return err
+	}
+	if r.Width() <= 0 || r.Height() <= 0 {
+		return fmt.Errorf("bad node bounds %s", node.Bounds)
+	}
+
+	// 节点 bounds 总是处于当前方向，与 RotateCoords 无关
+	w, h, err := d.orientedScreenSize()
+	if err != nil {
+		return err
+	}
+
+	x, y := r.Center()
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return fmt.Errorf("node center (%d,%d) out of screen %dx%d", x, y, w, h)
+	}
+
+	// 终点限制在内容区域内，起点已经在区域外时不再向外滑动
+	area := d.safeSwipeArea(uixml.Rect{X2: w, Y2: h})
+	tx, ty := x, y
+	switch direction {
+	case LeftOf:
+		tx = max(x-r.Width()-swipeAwayMargin, min(area.X1, x))
+	case RightOf:
+		tx = min(x+r.Width()+swipeAwayMargin, max(area.X2-1, x))
+	case Above:
+		ty = max(y-r.Height()-swipeAwayMargin, min(area.Y1, y))
+	case Below:
+		ty = min(y+r.Height()+swipeAwayMargin, max(area.Y2-1, y))
+	default:
+		return fmt.Errorf("bad direction %v", direction)
+	}
+	return d.swipe(x, y, tx, ty, int(duration.Milliseconds()))
+}
+
+// SortOrder 表示节点按屏幕位置排序的方式。
+type SortOrder int
+
+const (
+	TopToBottom  SortOrder = iota // 按上边界从上到下，相同时从左到右
+	LeftToRight                   // 按左边界从左到右，相同时从上到下
+	ReadingOrder                  // 阅读顺序：先按行从上到下，同一行内从左到右
+)
+
+// FindNodesSorted 查找所有匹配的节点，并按屏幕位置排序。
+// FindNodes 返回的是 UI 树的遍历顺序，不一定与视觉顺序一致，
+// 需要 "点击第 3 行" 这类按位置操作时应使用该方法。
+//
+// 参数：
+//   - fn: 节点查找函数
+//   - order: 排序方式（TopToBottom / LeftToRight / ReadingOrder）
+//
+// 返回值：
+//   - []uixml.Node: 排序后的节点，边界无法解析的节点排在最后
+//   - error: 没有匹配的节点时返回 ErrNotFound
+//
+// 注意事项：
+//   - ReadingOrder 先按上边界排序，相邻两个节点的上边界差距小于两者中较小高度的一半时视为同一行，
+//     同一行内再按左边界排序
+//
+// 示例：
+//
+//	rows, err := device.FindNodesSorted(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/item"
+//	}, adb.TopToBottom)
+//	if err == nil && len(rows) >= 3 {
+//	    device.ClickNodeBy(rows[2]) // 第 3 行
+//	}
+func (d *Device) FindNodesSorted(fn FindNodeFunc, order SortOrder) ([]uixml.Node, error) {
+	nodes, err := d.FindNodes(fn)
+	if err != nil {
+		return nil, err
+	}
+	if err := sortNodes(nodes, order); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// sortNodes 按屏幕位置对节点原地排序。
+func sortNodes(nodes []uixml.Node, order SortOrder) error {
+	type item struct {
+		node uixml.Node
+		rect uixml.Rect
+		ok   bool
+	}
+	items := make([]item, len(nodes))
+	for i, n := range nodes {
+		r, err := uixml.ParseBounds(n.Bounds)
+		items[i] = item{n, r, err == nil}
+	}
+
+	var less func(a, b uixml.Rect) bool
+	switch order {
+	case TopToBottom:
+		less = func(a, b uixml.Rect) bool {
+			if a.Y1 != b.Y1 {
+				return a.Y1 < b.Y1
+			}
+			return a.X1 < b.X1
+		}
+	case LeftToRight:
+		less = func(a, b uixml.Rect) bool {
+			if a.X1 != b.X1 {
+				return a.X1 < b.X1
+			}
+			return a.Y1 < b.Y1
+		}
+	case ReadingOrder:
+		// 先按上边界排序，排序后再分行，见下文
+		less = func(a, b uixml.Rect) bool { return a.Y1 < b.Y1 }
+	default:
+		return fmt.Errorf("bad sort order %d", int(order))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ok != items[j].ok {
+			return items[i].ok
+		}
+		return items[i].ok && less(items[i].rect, items[j].rect)
+	})
+
+	// 阅读顺序不能直接用 "上边界接近时按左边界比较" 排序，这种比较不满足传递性。
+	// 按上边界排好后，相邻两个节点的上边界差距达到两者中较小高度的一半时开始新的一行，
+	// 再把每一行按左边界排序
+	if order == ReadingOrder {
+		for start := 0; start < len(items) && items[start].ok; {
+			end := start + 1
+			for end < len(items) && items[end].ok {
+				a, b := items[end-1].rect, items[end].rect
+				if b.Y1-a.Y1 >= min(a.Height(), b.Height())/2 {
+					break
+				}
+				end++
+			}
+			row := items[start:end]
+			sort.SliceStable(row, func(i, j int) bool { return row[i].rect.X1 < row[j].rect.X1 })
+			start = end
+		}
+	}
+	for i := range items {
+		nodes[i] = items[i].node
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package adb
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// gridDump 返回 3x3 的表格，每个单元格 100x100，文本为 "r<行>c<列>"。
+func gridDump() string {
+	var nodes []string
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			nodes = append(nodes, fmt.Sprintf(`<node text="r%dc%d" class="android.widget.TextView" bounds="[%d,%d][%d,%d]" />`,
+				r, c, c*100, r*100, c*100+100, r*100+100))
+		}
+	}
+	return hierarchy(nodes...)
+}
+
+func byText(text string) FindNodeFunc {
+	return func(n, pn uixml.Node) bool { return n.Text == text }
+}
+
+func anyCell(n, pn uixml.Node) bool { return n.Class == "android.widget.TextView" }
+
+func TestFindNodeNear(t *testing.T) {
+	x, err := uixml.NewXml(gridDump())
+	if err != nil {
+		t.Fatal(err)
+	}
+	center := byText("r1c1")
+
+	tests := []struct {
+		direction Direction
+		want      string
+	}{
+		{Below, "r2c1"},
+		{Above, "r0c1"},
+		{LeftOf, "r1c0"},
+		{RightOf, "r1c2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.direction.String(), func(t *testing.T) {
+			node, err := nearestNode(x, center, tt.direction, anyCell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if node.Text != tt.want {
+				t.Errorf("got %s, want %s", node.Text, tt.want)
+			}
+		})
+	}
+
+	// 目标条件缩小到第一列时，右侧没有匹配
+	firstColumn := func(n, pn uixml.Node) bool { return strings.HasSuffix(n.Text, "c0") }
+	if node, err := nearestNode(x, byText("r0c0"), Below, firstColumn); err != nil || node.Text != "r1c0" {
+		t.Errorf("nearestNode(r0c0, Below, first column) = %s, %v; want r1c0", node.Text, err)
+	}
+	if _, err := nearestNode(x, center, RightOf, firstColumn); !errors.Is(err, ErrNotFound) {
+		t.Errorf("nearestNode(r1c1, RightOf, first column) error = %v, want ErrNotFound", err)
+	}
+	if _, err := nearestNode(x, byText("r2c2"), Below, anyCell); !errors.Is(err, ErrNotFound) {
+		t.Errorf("nearestNode(r2c2, Below) error = %v, want ErrNotFound", err)
+	}
+	if _, err := nearestNode(x, byText("missing"), Below, anyCell); !errors.Is(err, ErrNotFound) {
+		t.Errorf("nearestNode(missing anchor) error = %v, want ErrNotFound", err)
+	}
+}
+
+// shuffledDump 是两行三列的列表，XML 中的顺序被打乱；第一行的 r0c0 比同一行的其他节点低 10 像素，
+// 最后还有一个 bounds 无效的节点。
+func shuffledDump() string {
+	node := func(text, bounds string) string {
+		return `<node text="` + text + `" class="android.widget.TextView" bounds="` + bounds + `" />`
+	}
+	return hierarchy(
+		node("r1c2", "[200,100][300,150]"),
+		node("r0c2", "[200,0][300,50]"),
+		node("bad", ""),
+		node("r1c0", "[0,100][100,150]"),
+		node("r0c0", "[0,10][100,60]"),
+		node("r1c1", "[100,100][200,150]"),
+		node("r0c1", "[100,0][200,50]"),
+	)
+}
+
+func TestFindNodesSorted(t *testing.T) {
+	d, _ := newFakeDevice(func(command string) (string, error) { return shuffledDump(), nil })
+
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{TopToBottom, []string{"r0c1", "r0c2", "r0c0", "r1c0", "r1c1", "r1c2", "bad"}},
+		{LeftToRight, []string{"r0c0", "r1c0", "r0c1", "r1c1", "r0c2", "r1c2", "bad"}},
+		{ReadingOrder, []string{"r0c0", "r0c1", "r0c2", "r1c0", "r1c1", "r1c2", "bad"}},
+	}
+	for _, tt := range tests {
+		nodes, err := d.FindNodesSorted(anyCell, tt.order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, n := range nodes {
+			got = append(got, n.Text)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("order %d: got %q, want %q", tt.order, got, tt.want)
+		}
+	}
+
+	if _, err := d.FindNodesSorted(anyCell, SortOrder(99)); err == nil {
+		t.Error("FindNodesSorted with a bad order succeeded")
+	}
+	if _, err := d.FindNodesSorted(byText("missing"), TopToBottom); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindNodesSorted(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSortNodesReadingOrderToleranceChain(t *testing.T) {
+	// 高度都是 50，同一行的容差为 25：a 与 b、b 与 c 都在容差内，a 与 c 不在。
+	// 逐对比较时 b<a、c<b 却 a<c，排序结果取决于输入顺序；分行后三者都在同一行
+	node := func(text, bounds string) uixml.Node {
+		return uixml.Node{Text: text, Bounds: bounds}
+	}
+	a := node("a", "[200,0][300,50]")
+	b := node("b", "[100,20][200,70]")
+	c := node("c", "[0,40][100,90]")
+	below := node("below", "[0,100][100,150]")
+
+	inputs := [][]uixml.Node{{a, b, c, below}, {c, b, a, below}, {below, b, a, c}, {a, c, below, b}}
+	for i, in := range inputs {
+		nodes := slices.Clone(in)
+		if err := sortNodes(nodes, ReadingOrder); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, n := range nodes {
+			got = append(got, n.Text)
+		}
+		if want := []string{"c", "b", "a", "below"}; !slices.Equal(got, want) {
+			t.Errorf("input %d sorted to %q, want %q", i, got, want)
+		}
+	}
+}
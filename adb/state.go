@@ -0,0 +1,85 @@
+package adb
+
+// NodeState 是节点的状态标志，由 ElementState 一次性读取。
+type NodeState struct {
+	Enabled    bool
+	Checked    bool
+	Selected   bool
+	Focused    bool
+	Clickable  bool
+	Checkable  bool
+	Scrollable bool
+}
+
+// ElementState 查找节点并返回它的所有状态标志，只获取一次 UI 结构。
+//
+// 参数：
+//   - fn: 节点查找函数
+//
+// 返回值：
+//   - NodeState: 节点的状态
+//   - error: 没有匹配的节点时返回 ErrNotFound
+//
+// 示例：
+//
+//	state, err := device.ElementState(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/agree"
+//	})
+//	if err == nil && state.Enabled && !state.Checked {
+//	    device.ClickNode("", "同意协议")
+//	}
+func (d *Device) ElementState(fn FindNodeFunc) (NodeState, error) {
+	node, err := d.FindNode(fn)
+	if err != nil {
+		return NodeState{}, err
+	}
+	return NodeState{
+		Enabled:    node.IsEnabled(),
+		Checked:    node.IsChecked(),
+		Selected:   node.IsSelected(),
+		Focused:    node.IsFocused(),
+		Clickable:  node.IsClickable(),
+		Checkable:  node.IsCheckable(),
+		Scrollable: node.IsScrollable(),
+	}, nil
+}
+
+// IsEnabled 判断匹配的节点是否处于启用状态，可在点击前检查按钮是否可用，避免无效的点击。
+//
+// 参数：
+//   - fn: 节点查找函数
+//
+// 返回值：
+//   - bool: 节点的 enabled 属性为 true 时返回 true
+//   - error: 没有匹配的节点时返回 ErrNotFound
+//
+// 示例：
+//
+//	submit := func(n, pn uixml.Node) bool { return n.ResourceID == "com.example:id/submit" }
+//	if ok, _ := device.IsEnabled(submit); ok {
+//	    device.ClickNode("", "提交")
+//	}
+func (d *Device) IsEnabled(fn FindNodeFunc) (bool, error) {
+	state, err := d.ElementState(fn)
+	return state.Enabled, err
+}
+
+// IsChecked 判断匹配的复选框、开关等节点是否已勾选。
+//
+// 返回值：
+//   - bool: 节点的 checked 属性为 true 时返回 true
+//   - error: 没有匹配的节点时返回 ErrNotFound
+func (d *Device) IsChecked(fn FindNodeFunc) (bool, error) {
+	state, err := d.ElementState(fn)
+	return state.Checked, err
+}
+
+// IsSelected 判断匹配的标签页、列表项等节点是否处于选中状态。
+//
+// 返回值：
+//   - bool: 节点的 selected 属性为 true 时返回 true
+//   - error: 没有匹配的节点时返回 ErrNotFound
+func (d *Device) IsSelected(fn FindNodeFunc) (bool, error) {
+	state, err := d.ElementState(fn)
+	return state.Selected, err
+}
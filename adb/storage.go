@@ -0,0 +1,182 @@
+package adb
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DiskUsage 表示一个分区的空间使用情况，数值单位均为字节。
+type DiskUsage struct {
+	Filesystem string // 文件系统（设备节点），例如 /dev/block/dm-5
+	MountedOn  string // 挂载点，例如 /data；旧版 toolbox df 没有该列时为空
+	Total      int64  // 总空间
+	Used       int64  // 已用空间
+	Available  int64  // 可用空间
+}
+
+// DiskUsage 获取设备上 path 所在分区的空间使用情况。
+//
+// 参数：
+//   - path: 设备上的路径，例如 "/data"、"/sdcard"
+//
+// 返回值：
+//   - DiskUsage: 总空间、已用空间和可用空间（字节）
+//   - error: 如果路径不存在或输出无法解析，返回 error 对象
+//
+// 兼容性：
+//   - 优先使用 'df -B1'，直接以字节为单位（busybox / GNU df 支持）
+//   - toybox 的 df 不支持 -B，回退到 'df -k'，以 KB 为单位
+//   - 旧版 toolbox 的 df 两者都不支持，回退到 'df'，解析 "12.5G" 这类带单位的数值
+//   - busybox 在文件系统名过长时会把数值换到下一行，也能正确解析
+//
+// 示例：
+//
+//	usage, err := device.DiskUsage("/sdcard")
+//	if err == nil {
+//	    fmt.Printf("可用 %d MB / 共 %d MB\n", usage.Available>>20, usage.Total>>20)
+//	}
+func (d *Device) DiskUsage(path string) (DiskUsage, error) {
+	for _, flag := range []string{"-B1", "-k"} {
+		output, err := d.Shellf("df "+flag+" %s", path)
+		if err == nil {
+			if usage, err := parseDf(output); err == nil {
+				return usage, nil
+			}
+		}
+	}
+	output, err := d.Shellf("df %s", path)
+	if err != nil {
+		return DiskUsage{}, err
+	}
+	return parseDf(output)
+}
+
+// parseDf 解析 df 的输出。支持的表头：
+//
+//	Filesystem     1B-blocks    Used Available Use% Mounted on   (busybox df -B1)
+//	Filesystem     1K-blocks    Used Available Use% Mounted on   (toybox / busybox df -k)
+//	Filesystem      Size  Used Avail Use% Mounted on             (toybox df -h)
+//	Filesystem  Size   Used   Free   Blksize                     (toolbox df)
+func parseDf(output string) (DiskUsage, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	header := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "Filesystem") {
+			header = i
+		}
+	}
+	if header < 0 || header == len(lines)-1 {
+		return DiskUsage{}, fmt.Errorf("unexpected df output: %s", truncate(output, 200))
+	}
+
+	// busybox 会把过长的文件系统名单独放一行，合并后再按空白分割
+	fields := strings.Fields(strings.Join(lines[header+1:], " "))
+	if len(fields) < 4 {
+		return DiskUsage{}, fmt.Errorf("unexpected df output: %s", truncate(output, 200))
+	}
+
+	parse := parseHumanSize
+	switch {
+	case strings.Contains(lines[header], "1B-blocks"):
+		parse = func(s string) (int64, error) {
+			return strconv.ParseInt(s, 10, 64)
+		}
+	case strings.Contains(lines[header], "1K-blocks") || strings.Contains(lines[header], "1024-blocks"):
+		parse = func(s string) (int64, error) {
+			kb, err := strconv.ParseInt(s, 10, 64)
+			return kb << 10, err
+		}
+	}
+
+	usage := DiskUsage{Filesystem: fields[0]}
+	if len(fields) >= 6 {
+		usage.MountedOn = fields[5]
+	}
+	for i, dst := range []*int64{&usage.Total, &usage.Used, &usage.Available} {
+		v, err := parse(fields[i+1])
+		if err != nil {
+			return DiskUsage{}, fmt.Errorf("unexpected df value %q: %w", fields[i+1], err)
+		}
+		*dst = v
+	}
+	return usage, nil
+}
+
+// parseHumanSize 解析带单位的大小，例如 "12.5G"、"512K"、"0"，单位按 1024 进制换算。
+func parseHumanSize(s string) (int64, error) {
+	mult := 1.0
+	if n := len(s); n > 0 {
+		if i := strings.IndexByte("KMGTP", s[n-1]); i >= 0 {
+			for j := 0; j <= i; j++ {
+				mult *= 1024
+			}
+			s = s[:n-1]
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(v * mult), nil
+}
+
+// checkStorage 在设置了 WithStorageCheck 时检查 devicePath 所在分区的可用空间是否能容纳
+// 所有本地文件（目录递归计算），空间不足时返回包装了 ErrInsufficientStorage 的错误。
+// 没有设置 WithStorageCheck 时直接返回 nil，调用方不需要再判断。
+// devicePath 不存在时（例如推送的目标文件）向上查找最近的已存在目录。
+func (d *Device) checkStorage(devicePath string, localPaths ...string) error {
+	if !d.storageCheck {
+		return nil
+	}
+	var size int64
+	for _, name := range localPaths {
+		n, err := localSize(name)
+		if err != nil {
+			return err
+		}
+		size += n
+	}
+
+	p := devicePath
+	for {
+		usage, err := d.DiskUsage(p)
+		if err == nil {
+			if usage.Available < size {
+				return fmt.Errorf("%s: need %d bytes, %d available: %w", devicePath, size, usage.Available, ErrInsufficientStorage)
+			}
+			return nil
+		}
+		parent := path.Dir(p)
+		if parent == p {
+			// 无法确定可用空间时不阻止操作，交给实际的传输报错
+			return nil
+		}
+		p = parent
+	}
+}
+
+// localSize 返回本地文件或目录（递归）的总大小。
+func localSize(name string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(name, func(_ string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !e.IsDir() {
+			info, err := e.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
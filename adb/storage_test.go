@@ -0,0 +1,56 @@
+package adb
+
+import "testing"
+
+func TestParseDf(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   DiskUsage
+	}{
+		{
+			name: "busybox -B1",
+			output: `Filesystem           1B-blocks      Used Available Use% Mounted on
+/dev/block/dm-5      10737418240 5368709120 5368709120  50% /data`,
+			want: DiskUsage{Filesystem: "/dev/block/dm-5", MountedOn: "/data", Total: 10737418240, Used: 5368709120, Available: 5368709120},
+		},
+		{
+			name: "toybox -k",
+			output: `Filesystem     1K-blocks    Used Available Use% Mounted on
+/dev/block/dm-5  10485760 5242880   5242880  50% /data`,
+			want: DiskUsage{Filesystem: "/dev/block/dm-5", MountedOn: "/data", Total: 10 << 30, Used: 5 << 30, Available: 5 << 30},
+		},
+		{
+			name: "busybox wrapped line",
+			output: `Filesystem           1K-blocks      Used Available Use% Mounted on
+/dev/block/platform/soc/by-name/userdata
+                      1024       512       512  50% /data`,
+			want: DiskUsage{Filesystem: "/dev/block/platform/soc/by-name/userdata", MountedOn: "/data", Total: 1 << 20, Used: 512 << 10, Available: 512 << 10},
+		},
+		{
+			name: "toolbox",
+			output: `Filesystem               Size     Used     Free   Blksize
+/data                    2.0G   512.0M     1.5G   4096`,
+			want: DiskUsage{Filesystem: "/data", Total: 2 << 30, Used: 512 << 20, Available: 3 << 29},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDf(tt.output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDfBadOutput(t *testing.T) {
+	for _, output := range []string{"", "df: /nope: No such file or directory", "Filesystem 1K-blocks Used"} {
+		if _, err := parseDf(output); err == nil {
+			t.Errorf("parseDf(%q) succeeded", output)
+		}
+	}
+}
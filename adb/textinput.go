@@ -0,0 +1,391 @@
+package adb
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// 输入法与剪贴板工具的包名。
+const (
+	adbKeyboardIME = "com.android.adbkeyboard/.AdbIME"
+	clipperPackage = "ca.zgrs.clipper"
+)
+
+// InputTextBase64 通过 ADB Keyboard 的 base64 广播输入文本。
+// 文本的 UTF-8 字节先编码为 base64，广播内容只包含 [A-Za-z0-9+/=]，
+// 因此引号、换行、emoji、从右到左书写的文字都不需要任何转义。
+//
+// 参数：
+//   - text: 要输入的任意文本
+//
+// 返回值：
+//   - error: 如果当前输入法不是 ADB Keyboard（错误包装了 ErrUnsupported）或广播失败，返回 error 对象
+//
+// 示例：
+//
+//	err := device.InputTextBase64("他说：\"It's 100% 👍\"\n第二行")
+func (d *Device) InputTextBase64(text string) error {
+	if ok, err := d.adbKeyboardActive(); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("adb keyboard is not the current input method: %w", ErrUnsupported)
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte(text))
+	_, err := d.Shell("am broadcast -a ADB_INPUT_B64 --es msg " + encoded)
+	return err
+}
+
+// InputTextViaClipboard 把文本写入剪贴板，再粘贴到当前获得焦点的输入框。
+// 与 Paste 不同，它不需要目标节点，也不检查粘贴结果。
+//
+// 参数：
+//   - text: 要输入的任意文本
+//
+// 返回值：
+//   - error: 如果写入剪贴板或粘贴失败，返回 error 对象
+//
+// 注意事项：
+//   - 依赖 Clipper 应用，写入剪贴板时 Clipper 会短暂切到前台，之后按返回键回到原界面
+//   - 会覆盖剪贴板原有的内容
+//
+// 示例：
+//
+//	device.ClickNodeBy(commentBox)
+//	err := device.InputTextViaClipboard("很棒 👍")
+func (d *Device) InputTextViaClipboard(text string) error {
+	if err := d.SetClipboard(text); err != nil {
+		return err
+	}
+	// SetClipboard 会把 Clipper 切到前台，返回原界面后焦点回到原来的输入框
+	if err := d.PressBack(); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+	return d.SendKeys(KeyPaste)
+}
+
+// InputTextSafe 自动选择最可靠的方式输入任意文本。
+//
+// 参数：
+//   - text: 要输入的文本，可以包含引号、换行、emoji 等任意字符
+//
+// 返回值：
+//   - error: 如果输入失败，返回 error 对象，错误信息中包含所使用的方式
+//     （例如 "input text via clipboard: ..."）
+//
+// 选择顺序：
+//  1. 当前输入法是 ADB Keyboard：使用 InputTextBase64，完全不需要转义
+//  2. 安装了 Clipper：使用 InputTextViaClipboard
+//  3. 都没有时使用系统的 'input text'，只支持 ASCII 可打印字符，
+//     包含其他字符时返回包装了 ErrUnsupported 的错误
+//
+// 示例：
+//
+//	if err := device.InputTextSafe(`O'Brien "Jr." 🚀`); err != nil {
+//	    log.Fatal(err)
+//	}
+func (d *Device) InputTextSafe(text string) error {
+	strategy, input := "input text", d.inputTextEscaped
+	if ok, _ := d.adbKeyboardActive(); ok {
+		strategy, input = "adb keyboard base64", d.InputTextBase64
+	} else if path, _ := d.Shellf("pm path %s", clipperPackage); strings.HasPrefix(path, "package:") {
+		strategy, input = "clipboard", d.InputTextViaClipboard
+	}
+	if err := input(text); err != nil {
+		return fmt.Errorf("input text via %s: %w", strategy, err)
+	}
+	return nil
+}
+
+// TypingOptions 是 TypeHuman 的字符间隔设置，每个字符之后等待 [MinDelay, MaxDelay] 之间的随机时间。
+// 零值使用 80ms ~ 250ms，接近普通人的打字速度。
+type TypingOptions struct {
+	MinDelay time.Duration
+	MaxDelay time.Duration
+}
+
+// 默认的打字间隔范围。
+const (
+	defaultTypeMinDelay = 80 * time.Millisecond
+	defaultTypeMaxDelay = 250 * time.Millisecond
+)
+
+// TypeHuman 点击输入框后逐个字符输入文本，字符之间随机停顿，模拟真人打字。
+// 部分应用会把瞬间出现的整段文字识别为机器人输入并拒绝，此时才需要使用该方法。
+//
+// 参数：
+//   - node: 输入框节点
+//   - text: 要输入的文本
+//   - opts: 字符间隔的范围，零值使用默认值
+//
+// 返回值：
+//   - error: 如果点击或输入失败，返回 error 对象
+//
+// 工作原理：
+//   - 小写字母、数字和不需要 Shift 的标点通过 'input keyevent' 发送按键，与真实键盘一致
+//   - 其他字符（大写字母、中文、emoji 等）通过 Input 逐个发送
+//
+// 注意事项：
+//   - 每个字符都要执行一次 adb 命令，再加上随机停顿，输入速度远慢于 Input，只在必要时使用
+//   - MaxDelay 小于 MinDelay 时使用固定的 MinDelay
+//
+// 示例：
+//
+//	node, _ := device.FindNode(adb.ByHint("用户名"))
+//	err := device.TypeHuman(node, "alice", adb.TypingOptions{MinDelay: 100 * time.Millisecond, MaxDelay: 400 * time.Millisecond})
+func (d *Device) TypeHuman(node uixml.Node, text string, opts TypingOptions) error {
+	if opts.MinDelay == 0 && opts.MaxDelay == 0 {
+		opts = TypingOptions{MinDelay: defaultTypeMinDelay, MaxDelay: defaultTypeMaxDelay}
+	}
+	if err := d.ClickNodeBy(node); err != nil {
+		return err
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	for _, r := range text {
+		var err error
+		if k, ok := lookupCharKey(r); ok && !k.shift {
+			err = d.SendKeys(k.code)
+		} else {
+			err = d.Input(string(r))
+		}
+		if err != nil {
+			return fmt.Errorf("type %q: %w", r, err)
+		}
+		delay := opts.MinDelay
+		if opts.MaxDelay > opts.MinDelay {
+			delay += time.Duration(rand.Int63n(int64(opts.MaxDelay - opts.MinDelay)))
+		}
+		time.Sleep(delay)
+	}
+	return nil
+}
+
+// inputTextEscaped 使用系统的 'input text' 输入文本，空格替换为 %s，其余字符由单引号转义。
+func (d *Device) inputTextEscaped(text string) error {
+	for _, r := range text {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return fmt.Errorf("character %q: %w", r, ErrUnsupported)
+		}
+	}
+	_, err := d.Shellf("input text %s", strings.ReplaceAll(text, " ", "%s"))
+	return err
+}
+
+// adbKeyboardActive 判断当前输入法是否为 ADB Keyboard。
+func (d *Device) adbKeyboardActive() (bool, error) {
+	ime, err := d.GetSetting("secure", "default_input_method")
+	if err != nil {
+		return false, err
+	}
+	return ime == adbKeyboardIME, nil
+}
+
+// GetFieldValue 读取输入框当前的内容，并标记是否为密码框。
+//
+// 参数：
+//   - fn: 输入框的查找函数
+//
+// 返回值：
+//   - value: 输入框的文本；空输入框显示的提示文本（hint）不算作内容，返回空字符串
+//   - isPassword: 是否为密码框；为 true 时 value 是掩码（例如 "••••"），不是真实内容
+//   - err: 如果找不到输入框，返回 error 对象
+//
+// 注意事项：
+//   - 密码框的 text 由系统替换为掩码字符，刚输入的最后一个字符可能短暂显示明文，
+//     不要拿 value 与期望的密码比较，最多只能比较长度
+//   - Android 8.0 以前空输入框的 text 就是 hint，此时根据 hint 属性识别并返回空字符串
+//
+// 示例：
+//
+//	value, isPassword, err := device.GetFieldValue(adb.ByHint("密码"))
+//	if err == nil && !isPassword {
+//	    fmt.Println("当前内容:", value)
+//	}
+func (d *Device) GetFieldValue(fn FindNodeFunc) (value string, isPassword bool, err error) {
+	node, err := d.FindNode(fn)
+	if err != nil {
+		return "", false, err
+	}
+	value, isPassword = fieldValue(node)
+	return value, isPassword, nil
+}
+
+// fieldValue 返回输入框节点的内容和是否为密码框。
+func fieldValue(node uixml.Node) (string, bool) {
+	if node.Hint != "" && node.Text == node.Hint {
+		return "", node.IsPassword()
+	}
+	return node.Text, node.IsPassword()
+}
+
+// InputAndVerify 点击输入框并输入文本，然后重新读取输入框确认内容已写入。
+//
+// 参数：
+//   - fn: 输入框的查找函数
+//   - text: 要输入的文本，追加在输入框已有内容之后
+//
+// 返回值：
+//   - error: 如果找不到输入框、输入失败或输入后的内容不一致，返回 error 对象
+//
+// 工作原理：
+//  1. 查找并点击输入框，通过 Input 输入文本
+//  2. 按 class、resource-id、content-desc 和位置重新找到该输入框（文本变化后 fn 可能不再匹配）
+//  3. 普通输入框要求内容以 text 结尾；密码框的内容是掩码，只检查长度不小于 text 的字符数
+//
+// 示例：
+//
+//	if err := device.InputAndVerify(adb.ByHint("用户名"), "alice"); err != nil {
+//	    log.Fatal(err) // 例如：field text "alic", expected to end with "alice"
+//	}
+func (d *Device) InputAndVerify(fn FindNodeFunc, text string) error {
+	node, err := d.FindNode(fn)
+	if err != nil {
+		return err
+	}
+	if err := d.typeInto(node, text); err != nil {
+		return err
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	after, err := d.findNode(func(n, pn uixml.Node) bool { return n.Key() == node.Key() })
+	if err != nil {
+		return fmt.Errorf("find field after input: %w", err)
+	}
+	value, isPassword := fieldValue(after)
+	if isPassword {
+		if utf8.RuneCountInString(value) < utf8.RuneCountInString(text) {
+			return fmt.Errorf("password field has %d chars, expected at least %d", utf8.RuneCountInString(value), utf8.RuneCountInString(text))
+		}
+		return nil
+	}
+	if !strings.HasSuffix(value, text) {
+		return fmt.Errorf("field text %q, expected to end with %q", value, text)
+	}
+	return nil
+}
+
+// focusMoveTimeout 是 NextField / PrevField 等待焦点移动到另一个控件的最长时间。
+const focusMoveTimeout = 2 * time.Second
+
+// NextField 按 Tab 键（KEYCODE_TAB）把焦点移动到下一个输入控件，并确认焦点确实移动了。
+//
+// 返回值：
+//   - error: 如果按键发送失败，或 2 秒内获得焦点的节点没有变化，返回 error 对象
+//
+// 注意事项：
+//   - 焦点顺序由应用决定（nextFocusForward 或布局顺序），没有正确设置焦点顺序的应用可能跳到意外的控件
+//   - 焦点已经在最后一个控件上时 Tab 通常不会移动焦点，此时返回 error
+//
+// 示例：
+//
+//	device.ClickNodeBy(usernameField)
+//	device.Input("alice")
+//	if err := device.NextField(); err != nil {
+//	    log.Fatal(err)
+//	}
+//	device.Input("secret")
+func (d *Device) NextField() error {
+	return d.moveFocus("next field", func() error {
+		return d.SendKeys(KeyTab)
+	})
+}
+
+// PrevField 按 Shift+Tab 把焦点移动到上一个输入控件，并确认焦点确实移动了。
+//
+// 返回值：
+//   - error: 如果系统不支持组合键（错误包装了 ErrUnsupported）、按键发送失败，
+//     或 2 秒内获得焦点的节点没有变化，返回 error 对象
+//
+// 兼容性：
+//   - 通过 'input keycombination 59 61' 发送，需要 Android 13+
+func (d *Device) PrevField() error {
+	sdk, err := d.SDKLevel()
+	if err != nil {
+		return err
+	}
+	if sdk < 33 {
+		return fmt.Errorf("shift+tab on API %d: %w", sdk, ErrUnsupported)
+	}
+	return d.moveFocus("previous field", func() error {
+		_, err := d.Shellf("input keycombination %d %d", int(keyShiftLeft), int(KeyTab))
+		return err
+	})
+}
+
+// FillForm 从当前获得焦点的输入框开始，依次输入每个值并按 Tab 切换到下一个输入框，
+// 最后一个值输入后按回车提交表单。
+// 与逐个点击输入框相比不依赖坐标，适用于焦点顺序正确的表单。
+//
+// 参数：
+//   - values: 按焦点顺序排列的各输入框的值，空字符串表示跳过该输入框（不输入内容）
+//
+// 返回值：
+//   - error: 如果输入失败或焦点没有移动，返回 error 对象，错误信息中包含出错的字段序号（从 1 开始）
+//
+// 注意事项：
+//   - 调用前需要先让第一个输入框获得焦点，例如点击它
+//   - 文本通过 Input 输入，需要 ADB Keyboard
+//
+// 示例：
+//
+//	device.ClickNode("android.widget.EditText", "用户名")
+//	err := device.FillForm([]string{"alice", "secret"})
+//	// 输入 alice，Tab，输入 secret，回车
+func (d *Device) FillForm(values []string) error {
+	for i, value := range values {
+		if value != "" {
+			if err := d.Input(value); err != nil {
+				return fmt.Errorf("field %d: %w", i+1, err)
+			}
+		}
+		if i < len(values)-1 {
+			if err := d.NextField(); err != nil {
+				return fmt.Errorf("field %d: %w", i+1, err)
+			}
+		}
+	}
+	return d.SendKeys(KeyEnter)
+}
+
+// moveFocus 发送切换焦点的按键，然后轮询等待获得焦点的节点发生变化。
+func (d *Device) moveFocus(name string, press func() error) error {
+	before, err := d.focusedKey()
+	if err != nil {
+		return err
+	}
+	if err := press(); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(focusMoveTimeout)
+	for {
+		time.Sleep(d.pollEvery())
+		after, err := d.focusedKey()
+		if err == nil && after != before {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s: focus did not move", name)
+		}
+	}
+}
+
+// focusedKey 返回当前获得焦点的节点的 Key，没有节点获得焦点时返回空字符串。
+func (d *Device) focusedKey() (string, error) {
+	node, err := d.findNode(func(n, pn uixml.Node) bool { return n.IsFocused() })
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return node.Key(), nil
+}
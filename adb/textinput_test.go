@@ -0,0 +1,41 @@
+package adb
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+func TestTypeHumanQuotes(t *testing.T) {
+	d, r := newFakeDevice(nil)
+	node := uixml.Node{Bounds: "[0,0][100,50]"}
+	if err := d.TypeHuman(node, `a'"b`, TypingOptions{MinDelay: time.Nanosecond}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"input tap 50 25",
+		"input keyevent 29",
+		"input keyevent 75", // ' 不需要 Shift，按键输入
+		// " 需要 Shift，通过 ADB Keyboard 广播输入，必须转义后才能作为一个参数
+		`am broadcast -a ADB_INPUT_TEXT --es msg '"'`,
+		"input keyevent 30",
+	}
+	if got := r.shellCommands(); !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
+
+func TestInputCommandQuotes(t *testing.T) {
+	tests := map[string]string{
+		"'":         `am broadcast -a ADB_INPUT_TEXT --es msg ''\'''`,
+		`"`:         `am broadcast -a ADB_INPUT_TEXT --es msg '"'`,
+		"it's a $x": `am broadcast -a ADB_INPUT_TEXT --es msg 'it'\''s%sa%s$x'`,
+	}
+	for text, want := range tests {
+		if got := inputCommand(text); got != want {
+			t.Errorf("inputCommand(%q) = %s, want %s", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// trackPollInterval 是无法连接 adb 服务端时轮询 'adb devices -l' 的间隔。
+const trackPollInterval = time.Second
+
+// TrackDevices 监听设备的插拔和状态变化，每当设备列表变化时通过通道发送完整的设备列表。
+// 适合设备农场看板等需要实时感知设备上下线的工具，比定时执行 'adb devices' 更及时、开销更小。
+//
+// 参数：
+//   - ctx: 控制监听时长，ctx 取消后停止监听并关闭通道
+//
+// 返回值：
+//   - <-chan []DeviceInfo: 设备列表通道；开始监听后立即发送一次当前列表，之后每次变化发送一次
+//   - error: 目前总是返回 nil，保留用于以后的启动检查
+//
+// 工作原理：
+//   - 直接连接 adb 服务端（127.0.0.1:5037，可通过 ANDROID_ADB_SERVER_PORT 修改端口），
+//     发送 "host:track-devices-l" 请求，服务端在设备变化时主动推送 "4 位十六进制长度 + 设备列表"
+//   - 旧版 adb 不支持 track-devices-l 时使用 "host:track-devices"，此时只有序列号和状态
+//   - 无法连接服务端（例如服务未启动）或连接中断时退化为每秒轮询 'adb devices -l'，只在列表变化时发送
+//
+// 注意事项：
+//   - 调用方需要持续读取通道，否则监听会被阻塞
+//
+// 示例：
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	updates, _ := adb.TrackDevices(ctx)
+//	for devices := range updates {
+//	    fmt.Printf("当前 %d 台设备\n", len(devices))
+//	    for _, dev := range devices {
+//	        fmt.Println(" ", dev.Serial, dev.State)
+//	    }
+//	}
+func TrackDevices(ctx context.Context) (<-chan []DeviceInfo, error) {
+	updates := make(chan []DeviceInfo)
+	go func() {
+		defer close(updates)
+		var last []DeviceInfo
+		first := true
+		send := func(devices []DeviceInfo) bool {
+			if !first && slices.Equal(devices, last) {
+				return true
+			}
+			first, last = false, devices
+			select {
+			case updates <- devices:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// 服务端推送，连接失败或中断后转为轮询
+		if conn, err := dialTrackDevices(ctx); err == nil {
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			for {
+				payload, err := readHexLengthPrefixed(conn)
+				if err != nil {
+					break
+				}
+				if !send(parseDeviceList(payload)) {
+					break
+				}
+			}
+			stop()
+			conn.Close()
+		}
+
+		for ctx.Err() == nil {
+			if devices, err := GetDeviceList(); err == nil && !send(devices) {
+				return
+			}
+			select {
+			case <-time.After(trackPollInterval):
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return updates, nil
+}
+
+// dialTrackDevices 连接 adb 服务端并发送 track-devices 请求，成功时返回已进入推送模式的连接。
+func dialTrackDevices(ctx context.Context) (net.Conn, error) {
+	port := os.Getenv("ANDROID_ADB_SERVER_PORT")
+	if port == "" {
+		port = "5037"
+	}
+	var dialer net.Dialer
+	for _, service := range []string{"host:track-devices-l", "host:track-devices"} {
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort("127.0.0.1", port))
+		if err != nil {
+			return nil, err
+		}
+		if err := adbRequest(conn, service); err != nil {
+			conn.Close()
+			continue
+		}
+		return conn, nil
+	}
+	return nil, fmt.Errorf("track devices not supported by adb server")
+}
+
+// adbRequest 按 adb 服务端协议发送请求（4 位十六进制长度 + 内容）并读取 OKAY / FAIL 响应。
+func adbRequest(conn net.Conn, service string) error {
+	if _, err := fmt.Fprintf(conn, "%04x%s", len(service), service); err != nil {
+		return err
+	}
+	status := make([]byte, 4)
+	if _, err := io.ReadFull(conn, status); err != nil {
+		return err
+	}
+	if string(status) == "OKAY" {
+		return nil
+	}
+	msg, _ := readHexLengthPrefixed(conn)
+	return fmt.Errorf("adb server: %s: %s", service, msg)
+}
+
+// readHexLengthPrefixed 读取一条 "4 位十六进制长度 + 内容" 格式的消息。
+func readHexLengthPrefixed(r io.Reader) (string, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return "", err
+	}
+	n, err := strconv.ParseUint(string(header), 16, 16)
+	if err != nil {
+		return "", fmt.Errorf("bad length %q", header)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
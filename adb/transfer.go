@@ -0,0 +1,363 @@
+package adb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// findMarker 分隔 PullDir 中两次 find 的输出。
+const findMarker = "--adb-pulldir--"
+
+// PullDir 把设备上的目录完整拉取到本地，保留目录结构。
+// 不依赖 'adb pull' 对目录的递归处理（不同 adb 版本可能把文件拉平或遗漏），
+// 而是先列出所有目录和文件，在本地重建目录后逐个拉取文件，结果在各版本上一致。
+//
+// 参数：
+//   - deviceDir: 设备上的目录，例如 "/sdcard/DCIM"
+//   - localDir: 本地目标目录，deviceDir 下的内容直接放在其中，不存在时自动创建
+//
+// 返回值：
+//   - error: 单个文件失败时继续拉取其他文件，最后通过 errors.Join 返回所有失败；
+//     全部成功时返回 nil
+//
+// 工作原理：
+//  1. 'find -L <dir> -type d' 列出所有目录并在本地创建（包括空目录）
+//  2. 'find -L <dir> -type f' 列出所有文件，逐个执行 adb pull
+//
+// 注意事项：
+//   - 符号链接会被跟随：指向文件的链接拉取为普通文件，指向目录的链接作为目录遍历，
+//     悬空的链接被忽略，循环链接由 find 报错并包含在返回的错误中
+//   - 没有读权限的目录和文件会以 "permission denied" 错误报告，不会中断其他文件
+//   - 文件名中不能包含换行符
+//
+// 示例：
+//
+//	err := device.PullDir("/sdcard/Android/data/com.example.app/files/logs", "artifacts/logs")
+//	if err != nil {
+//	    log.Println("部分文件拉取失败:", err)
+//	}
+func (d *Device) PullDir(deviceDir, localDir string) error {
+	// path.Clean 去掉末尾的 "/"，但根目录保持为 "/"
+	deviceDir = path.Clean(deviceDir)
+	output, err := d.Shellf("find -L %s -type d 2>&1; echo "+findMarker+"; find -L %s -type f 2>&1; true", deviceDir, deviceDir)
+	if err != nil {
+		return err
+	}
+	dirs, files, ok := strings.Cut(output, findMarker)
+	if !ok {
+		return fmt.Errorf("unexpected find output: %s", truncate(output, 200))
+	}
+
+	var errs []error
+	for _, dir := range strings.Split(dirs, "\n") {
+		rel, ok := relPath(deviceDir, dir)
+		if !ok {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(localDir, filepath.FromSlash(rel)), 0755); err != nil {
+			return err
+		}
+	}
+
+	for _, line := range strings.Split(files, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		// find 的错误信息，例如 "find: /sdcard/x: Permission denied"
+		if strings.HasPrefix(line, "find:") {
+			errs = append(errs, errors.New(line))
+			continue
+		}
+		rel, ok := relPath(deviceDir, line)
+		if !ok {
+			continue
+		}
+		local := filepath.Join(localDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+			return err
+		}
+		if err := d.Pull(line, local); err != nil {
+			errs = append(errs, fmt.Errorf("pull %s: %w", line, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PullArchive 把设备上的目录打包为 tar 流直接写入 w，不在设备或本地产生临时文件。
+// 比 PullDir 逐个拉取文件快得多，适合包含大量小文件的目录。
+//
+// 参数：
+//   - deviceDir: 设备上的目录，例如 "/sdcard/DCIM"
+//   - w: tar 数据的写入目标，例如本地文件或 gzip.Writer
+//
+// 返回值：
+//   - error: 如果目录不存在或打包失败，返回 error 对象
+//
+// 工作原理：
+//   - 执行 'exec-out tar -cf - -C <父目录> <目录名>'，输出通过 ExecoutTo 流式写入 w
+//   - 归档中的路径以目录名开头，例如 "DCIM/Camera/IMG_0001.jpg"
+//
+// 注意事项：
+//   - exec-out 不传递设备端命令的退出状态，因此先检查目录是否存在；
+//     打包过程中无权读取的文件会被 tar 跳过，不会报错
+//   - 需要设备上有 tar 命令（Android 6.0+ 的 toybox 自带）
+//
+// 示例：
+//
+//	f, _ := os.Create("artifacts/logs.tar")
+//	defer f.Close()
+//	err := device.PullArchive("/sdcard/Android/data/com.example.app/files/logs", f)
+func (d *Device) PullArchive(deviceDir string, w io.Writer) error {
+	deviceDir = strings.TrimSuffix(deviceDir, "/")
+	output, err := d.Shellf("test -d %s && echo ok", deviceDir)
+	if err != nil || output != "ok" {
+		return fmt.Errorf("pull archive %s: %w", deviceDir, ErrNotFound)
+	}
+	command := fmt.Sprintf("tar -cf - -C %s %s 2>/dev/null", shellQuote(path.Dir(deviceDir)), shellQuote(path.Base(deviceDir)))
+	return d.ExecoutTo(w, command)
+}
+
+// PullGlob 拉取设备上与通配符匹配的所有文件到本地目录，本地文件名与设备上的文件名相同。
+//
+// 参数：
+//   - pattern: 设备 shell 的通配符，例如 "/sdcard/Download/*.log"；由设备 shell 展开，不会被转义，
+//     因此路径中不能包含空格等特殊字符
+//   - localDir: 本地目录，不存在时自动创建
+//
+// 返回值：
+//   - []string: 拉取成功的本地文件路径；没有匹配的文件时返回空切片
+//   - error: 单个文件失败时继续拉取其他文件，最后通过 errors.Join 返回所有失败
+//
+// 注意事项：
+//   - 只匹配普通文件，目录会被忽略（需要时使用 PullDir）
+//   - 不同目录中的同名文件会互相覆盖
+//
+// 示例：
+//
+//	files, err := device.PullGlob("/sdcard/Android/data/com.example.app/files/*.trace", "artifacts/traces")
+func (d *Device) PullGlob(pattern, localDir string) ([]string, error) {
+	output, err := d.Shell("for f in " + pattern + `; do [ -f "$f" ] && echo "$f"; done; true`)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return nil, err
+	}
+
+	pulled := []string{}
+	var errs []error
+	for _, line := range strings.Split(output, "\n") {
+		remote := strings.TrimSpace(line)
+		if remote == "" {
+			continue
+		}
+		local := filepath.Join(localDir, path.Base(remote))
+		if err := d.Pull(remote, local); err != nil {
+			errs = append(errs, fmt.Errorf("pull %s: %w", remote, err))
+			continue
+		}
+		pulled = append(pulled, local)
+	}
+	return pulled, errors.Join(errs...)
+}
+
+// PushExecutable 推送可执行文件到设备并赋予执行权限，用于部署自带的辅助程序（例如 frida-server、探针工具）。
+//
+// 参数：
+//   - localPath: 本地可执行文件路径
+//   - devicePath: 设备上的目标路径，通常位于 /data/local/tmp 下，例如 "/data/local/tmp/probe"
+//
+// 返回值：
+//   - error: 如果推送失败、推送后文件不存在或 chmod 失败，返回 error 对象
+//
+// 注意事项：
+//   - /sdcard 等外部存储挂载为 noexec，放在那里的文件无法执行，应使用 /data/local/tmp
+//   - 推送后会确认文件确实存在再执行 'chmod 755'
+//
+// 示例：
+//
+//	err := device.PushExecutable("build/probe-arm64", "/data/local/tmp/probe")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	out, err := device.RunBinary("/data/local/tmp/probe", "--json")
+func (d *Device) PushExecutable(localPath, devicePath string) error {
+	if err := d.Push(localPath, devicePath); err != nil {
+		return err
+	}
+	if output, _ := d.Shellf("test -f %s && echo ok", devicePath); output != "ok" {
+		return fmt.Errorf("push %s: file missing after push", devicePath)
+	}
+	output, err := d.Shellf("chmod 755 %s", devicePath)
+	if err != nil {
+		return fmt.Errorf("chmod %s: %w", devicePath, err)
+	}
+	if output != "" {
+		return fmt.Errorf("chmod %s: %s", devicePath, output)
+	}
+	return nil
+}
+
+// PushOption 是 PushWithMode 的可选参数。
+type PushOption func(*pushOptions)
+
+// pushOptions 保存 PushWithMode 的参数。
+type pushOptions struct {
+	mtime time.Time // 推送后设置的修改时间，零值表示不修改
+}
+
+// PushMtime 推送后把设备上文件的修改时间设置为 t，对应 'touch -d'。
+func PushMtime(t time.Time) PushOption {
+	return func(o *pushOptions) { o.mtime = t }
+}
+
+// PushWithMode 推送文件到设备并设置权限，可选同时设置修改时间。
+// adb push 不会保留本地文件的权限位，推送的脚本可能无法执行；
+// 应用根据修改时间判断文件是否变化时，推送还会让它误以为文件已更新。
+//
+// 参数：
+//   - localPath: 本地文件路径
+//   - devicePath: 设备上的目标路径
+//   - mode: 推送后设置的权限，只使用权限位（mode.Perm()），例如 0755
+//   - opts: 可选参数，例如 PushMtime(t)
+//
+// 返回值：
+//   - error: 如果推送、chmod 或 touch 失败，返回 error 对象
+//
+// 注意事项：
+//   - 推送目录时只修改目录本身的权限和时间，不递归处理其中的文件
+//   - /sdcard 等外部存储由 FUSE / sdcardfs 管理，权限位固定，chmod 不会生效
+//   - 设置修改时间需要 toybox 的 touch（Android 6.0+）
+//
+// 示例：
+//
+//	err := device.PushWithMode("scripts/setup.sh", "/data/local/tmp/setup.sh", 0755)
+//
+//	// 同时保留本地文件的修改时间
+//	info, _ := os.Stat("config.json")
+//	err = device.PushWithMode("config.json", "/data/local/tmp/config.json", 0644, adb.PushMtime(info.ModTime()))
+func (d *Device) PushWithMode(localPath, devicePath string, mode os.FileMode, opts ...PushOption) error {
+	var o pushOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := d.Push(localPath, devicePath); err != nil {
+		return err
+	}
+	output, err := d.Shellf("chmod %s %s", fmt.Sprintf("%04o", mode.Perm()), devicePath)
+	if err != nil {
+		return fmt.Errorf("chmod %s: %w", devicePath, err)
+	}
+	if output != "" {
+		return fmt.Errorf("chmod %s: %s", devicePath, output)
+	}
+
+	if !o.mtime.IsZero() {
+		stamp := o.mtime.UTC().Format("2006-01-02T15:04:05Z")
+		output, err := d.Shellf("touch -m -d %s %s", stamp, devicePath)
+		if err != nil {
+			return fmt.Errorf("touch %s: %w", devicePath, err)
+		}
+		if output != "" {
+			return fmt.Errorf("touch %s: %s", devicePath, output)
+		}
+	}
+	return nil
+}
+
+// PushPreserve 推送文件到设备，并让设备上的文件保持与本地文件相同的权限和修改时间。
+//
+// 参数：
+//   - localPath: 本地文件路径
+//   - devicePath: 设备上的目标路径
+//
+// 返回值：
+//   - error: 如果读取本地文件信息或推送失败，返回 error 对象
+//
+// 注意事项：
+//   - 与 PushWithMode 相同，推送目录时只处理目录本身
+//   - Windows 上的本地文件没有 Unix 权限位，可执行文件需要使用 PushWithMode 明确指定权限
+//
+// 示例：
+//
+//	err := device.PushPreserve("build/probe", "/data/local/tmp/probe")
+func (d *Device) PushPreserve(localPath, devicePath string) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+	return d.PushWithMode(localPath, devicePath, info.Mode(), PushMtime(info.ModTime()))
+}
+
+// RunBinary 在设备上执行可执行文件并返回它的输出。
+//
+// 参数：
+//   - devicePath: 设备上可执行文件的路径
+//   - args: 命令行参数，每个参数都会被转义，可以包含空格和引号
+//
+// 返回值：
+//   - string: 程序的输出（标准输出和标准错误，去除首尾空白）
+//   - error: 程序以非 0 状态退出或无法执行时返回 error 对象，常见情况会给出明确的原因：
+//     文件不存在（包装了 ErrNotFound）、没有执行权限、CPU 架构不匹配
+//
+// 注意事项：
+//   - 非 0 退出状态需要 Android 7.0+ 才能被识别，更早的版本只能通过输出判断
+//   - 架构不匹配时不同系统的提示不同（"Exec format error"、"not executable" 或 sh 的 "syntax error"）
+//
+// 示例：
+//
+//	out, err := device.RunBinary("/data/local/tmp/probe", "--pkg", "com.example.app")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(out)
+func (d *Device) RunBinary(devicePath string, args ...string) (string, error) {
+	parts := []string{shellQuote(devicePath)}
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	output, err := d.Shell(strings.Join(parts, " "))
+
+	// 只在命令失败或 shell 报告了与该文件相关的错误时判断原因，避免误判程序自身的输出
+	text := output + errString(err)
+	failed := err != nil || strings.Contains(output, devicePath+":")
+	switch {
+	case !failed:
+		return output, nil
+	case strings.Contains(text, "No such file") || strings.Contains(text, "not found"):
+		return output, fmt.Errorf("run %s: %w", devicePath, ErrNotFound)
+	case strings.Contains(text, "Permission denied"):
+		return output, fmt.Errorf("run %s: permission denied (missing chmod or noexec mount)", devicePath)
+	case strings.Contains(text, "Exec format error") || strings.Contains(text, "not executable") ||
+		strings.Contains(text, "syntax error"):
+		return output, fmt.Errorf("run %s: binary is not built for this device ABI", devicePath)
+	case err != nil:
+		return output, fmt.Errorf("run %s: %w", devicePath, err)
+	}
+	return output, fmt.Errorf("run %s: %s", devicePath, output)
+}
+
+// relPath 返回设备路径 p 相对于 dir 的路径，p 不在 dir 之下时返回 false。
+func relPath(dir, p string) (string, bool) {
+	p = strings.TrimSpace(p)
+	if p == dir {
+		return ".", true
+	}
+	prefix := dir
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	rel := strings.TrimPrefix(p, prefix)
+	if rel == p || rel == "" {
+		return "", false
+	}
+	return path.Clean(rel), true
+}
@@ -0,0 +1,24 @@
+package adb
+
+import "testing"
+
+func TestRelPath(t *testing.T) {
+	tests := []struct {
+		dir, p string
+		want   string
+		ok     bool
+	}{
+		{"/sdcard/DCIM", "/sdcard/DCIM", ".", true},
+		{"/sdcard/DCIM", "/sdcard/DCIM/a/b.jpg", "a/b.jpg", true},
+		{"/sdcard/DCIM", "/sdcard/DCIMX/b.jpg", "", false},
+		{"/", "/", ".", true},
+		{"/", "/system/build.prop", "system/build.prop", true},
+		{"/", "find: /proc/1: Permission denied", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := relPath(tt.dir, tt.p)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("relPath(%q, %q) = %q, %v; want %q, %v", tt.dir, tt.p, got, ok, tt.want, tt.ok)
+		}
+	}
+}
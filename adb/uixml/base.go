@@ -5,6 +5,7 @@ package uixml
 import (
 	"encoding/xml"
 	"io"
+	"os"
 	"strings"
 )
 
@@ -60,6 +61,33 @@ func NewXml(data string) (*Xml, error) {
 	return &Xml{xmlData}, nil
 }
 
+// LoadFile 读取保存到本地的 UI dump 文件（例如 Device.SaveDump 生成的文件）并解析，
+// 之后可以像在线获取的 Xml 一样使用 Find / FindAll 离线分析。
+//
+// 参数：
+//   - path: 本地 XML 文件路径
+//
+// 返回值：
+//   - *Xml: 解析后的 Xml 对象
+//   - error: 如果文件读取或解析失败，返回 error 对象
+//
+// 示例：
+//
+//	xml, err := uixml.LoadFile("artifacts/login_failed.xml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	node, err := xml.Find(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/login"
+//	})
+func LoadFile(path string) (*Xml, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewXml(string(data))
+}
+
 // Walk 递归遍历 UI 节点树，对每个节点执行指定的函数。
 // 该函数实现深度优先遍历，先处理当前节点，再递归处理子节点。
 //
@@ -152,6 +180,9 @@ type Hierarchy struct {
 //   - Password: 元素是否是密码输入框
 //   - Selected: 元素是否被选中
 //   - Bounds: 元素的边界坐标，格式为 "[x1,y1][x2,y2]"
+//   - Hint: 输入框的提示文本（Android 8.0+，空 EditText 常用它定位）
+//   - DisplayID: 元素所在的显示屏 ID（多屏设备上区分不同屏幕）
+//   - DrawingOrder: 元素在父节点中的绘制顺序
 //   - Children: 该节点的所有子节点数组
 //
 // XML 示例：
@@ -179,6 +210,9 @@ type Node struct {
 	Password      string `xml:"password,attr"`
 	Selected      string `xml:"selected,attr"`
 	Bounds        string `xml:"bounds,attr"`
+	Hint          string `xml:"hint,attr"`
+	DisplayID     string `xml:"display-id,attr"`
+	DrawingOrder  string `xml:"drawing-order,attr"`
 
 	Children []Node `xml:"node"`
 }
@@ -297,3 +331,175 @@ func ParseHierarchyFromString(s string) (*Hierarchy, error) {
 	// 将字符串转换为 Reader 并调用 ParseHierarchy
 	return ParseHierarchy(strings.NewReader(s))
 }
+
+// Key 返回节点的稳定标识字符串。
+// 标识由 class、resource-id、content-desc 和 bounds 组成，不包含 text 等易变属性，
+// 因此同一个元素在两次 dump 之间即使文本变化，Key 也保持不变。
+//
+// 返回值：
+//   - string: 形如 "class|resource-id|content-desc|bounds" 的标识字符串
+//
+// 注意事项：
+//   - 元素移动位置（bounds 变化）后会被视为不同的节点
+//   - 完全相同的兄弟节点会得到相同的 Key，Diff 会按出现次数区分
+//
+// 示例：
+//
+//	before, _ := xml.FindButton("确定")
+//	after, _ := newXml.FindButton("确定")
+//	if before.Key() == after.Key() {
+//	    fmt.Println("按钮没有移动")
+//	}
+func (n Node) Key() string {
+	return strings.Join([]string{n.Class, n.ResourceID, n.ContentDesc, n.Bounds}, "|")
+}
+
+// Equal 判断两个节点的属性是否完全相同。
+// 比较所有 XML 属性，忽略 Children（子节点不参与比较）。
+//
+// 参数：
+//   - other: 要比较的另一个节点
+//
+// 返回值：
+//   - bool: 所有属性都相同时返回 true
+//
+// 示例：
+//
+//	// 判断点击后复选框的状态是否变化
+//	if !before.Equal(after) {
+//	    fmt.Println("节点属性发生了变化")
+//	}
+func (n Node) Equal(other Node) bool {
+	return n.NAF == other.NAF &&
+		n.Index == other.Index &&
+		n.Text == other.Text &&
+		n.ResourceID == other.ResourceID &&
+		n.Class == other.Class &&
+		n.Package == other.Package &&
+		n.ContentDesc == other.ContentDesc &&
+		n.Checkable == other.Checkable &&
+		n.Checked == other.Checked &&
+		n.Clickable == other.Clickable &&
+		n.Enabled == other.Enabled &&
+		n.Focusable == other.Focusable &&
+		n.Focused == other.Focused &&
+		n.Scrollable == other.Scrollable &&
+		n.LongClickable == other.LongClickable &&
+		n.Password == other.Password &&
+		n.Selected == other.Selected &&
+		n.Bounds == other.Bounds &&
+		n.Hint == other.Hint &&
+		n.DisplayID == other.DisplayID &&
+		n.DrawingOrder == other.DrawingOrder
+}
+
+// Attr 按 XML 属性名返回节点的属性值。
+// 属性名与 UIAutomator dump 中的写法一致，例如 "resource-id"、"content-desc"、"hint"。
+//
+// 参数：
+//   - name: XML 属性名
+//
+// 返回值：
+//   - string: 属性值；未知属性名或属性不存在时返回空字符串
+//
+// 示例：
+//
+//	// 按配置中的属性名动态读取
+//	for _, name := range []string{"text", "hint", "resource-id"} {
+//	    fmt.Printf("%s = %s\n", name, node.Attr(name))
+//	}
+func (n Node) Attr(name string) string {
+	switch name {
+	case "NAF":
+		return n.NAF
+	case "index":
+		return n.Index
+	case "text":
+		return n.Text
+	case "resource-id":
+		return n.ResourceID
+	case "class":
+		return n.Class
+	case "package":
+		return n.Package
+	case "content-desc":
+		return n.ContentDesc
+	case "checkable":
+		return n.Checkable
+	case "checked":
+		return n.Checked
+	case "clickable":
+		return n.Clickable
+	case "enabled":
+		return n.Enabled
+	case "focusable":
+		return n.Focusable
+	case "focused":
+		return n.Focused
+	case "scrollable":
+		return n.Scrollable
+	case "long-clickable":
+		return n.LongClickable
+	case "password":
+		return n.Password
+	case "selected":
+		return n.Selected
+	case "bounds":
+		return n.Bounds
+	case "hint":
+		return n.Hint
+	case "display-id":
+		return n.DisplayID
+	case "drawing-order":
+		return n.DrawingOrder
+	}
+	return ""
+}
+
+// ShortID 返回 resource-id 中 "id/" 之后的部分。
+// 例如 "com.example:id/login_button" 返回 "login_button"。
+//
+// 返回值：
+//   - string: 简短的资源 ID；resource-id 中不包含 "id/" 时原样返回
+//
+// 示例：
+//
+//	if node.ShortID() == "login_button" {
+//	    device.ClickNodeBy(node)
+//	}
+func (n Node) ShortID() string {
+	if i := strings.LastIndex(n.ResourceID, "id/"); i >= 0 {
+		return n.ResourceID[i+len("id/"):]
+	}
+	return n.ResourceID
+}
+
+// IsCheckable 返回 checkable 属性是否为 "true"。
+func (n Node) IsCheckable() bool { return n.Checkable == "true" }
+
+// IsChecked 返回 checked 属性是否为 "true"（复选框、开关已勾选）。
+func (n Node) IsChecked() bool { return n.Checked == "true" }
+
+// IsClickable 返回 clickable 属性是否为 "true"。
+func (n Node) IsClickable() bool { return n.Clickable == "true" }
+
+// IsEnabled 返回 enabled 属性是否为 "true"（禁用的按钮点击无效）。
+func (n Node) IsEnabled() bool { return n.Enabled == "true" }
+
+// IsFocusable 返回 focusable 属性是否为 "true"。
+func (n Node) IsFocusable() bool { return n.Focusable == "true" }
+
+// IsFocused 返回 focused 属性是否为 "true"。
+func (n Node) IsFocused() bool { return n.Focused == "true" }
+
+// IsScrollable 返回 scrollable 属性是否为 "true"。
+func (n Node) IsScrollable() bool { return n.Scrollable == "true" }
+
+// IsLongClickable 返回 long-clickable 属性是否为 "true"。
+func (n Node) IsLongClickable() bool { return n.LongClickable == "true" }
+
+// IsPassword 返回 password 属性是否为 "true"。
+func (n Node) IsPassword() bool { return n.Password == "true" }
+
+// IsSelected 返回 selected 属性是否为 "true"（选中的标签页、列表项）。
+func (n Node) IsSelected() bool { return n.Selected == "true" }
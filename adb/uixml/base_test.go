@@ -0,0 +1,87 @@
+package uixml
+
+import "testing"
+
+// android14Login 是 Android 14 设备上登录页的 dump，包含 hint、display-id 和 drawing-order 属性。
+func android14Login(t *testing.T) *Xml {
+	t.Helper()
+	x, err := LoadFile("testdata/android14_login.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func byResourceID(id string) func(n, pn Node) bool {
+	return func(n, pn Node) bool { return n.ResourceID == id }
+}
+
+func TestParseAndroid14Attributes(t *testing.T) {
+	x := android14Login(t)
+	phone, err := x.Find(byResourceID("com.example.app:id/phone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if phone.Hint != "请输入手机号" || phone.DisplayID != "0" || phone.DrawingOrder != "1" {
+		t.Errorf("phone hint/display-id/drawing-order = %q/%q/%q", phone.Hint, phone.DisplayID, phone.DrawingOrder)
+	}
+	password, err := x.Find(byResourceID("com.example.app:id/password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password.Hint != "Password" || password.DrawingOrder != "2" || password.Password != "true" {
+		t.Errorf("password hint/drawing-order/password = %q/%q/%q", password.Hint, password.DrawingOrder, password.Password)
+	}
+	if n := x.Count(func(n, pn Node) bool { return n.Hint != "" }); n != 2 {
+		t.Errorf("%d nodes with a hint, want 2", n)
+	}
+}
+
+func TestShortID(t *testing.T) {
+	x := android14Login(t)
+	tests := []struct {
+		resourceID string
+		want       string
+	}{
+		{"com.example.app:id/login", "login"},
+		{"android:id/progress", "progress"},
+		{"version", "version"}, // 没有 "id/" 时原样返回
+	}
+	for _, tt := range tests {
+		n, err := x.Find(byResourceID(tt.resourceID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := n.ShortID(); got != tt.want {
+			t.Errorf("ShortID(%q) = %q, want %q", tt.resourceID, got, tt.want)
+		}
+	}
+	if got := (Node{}).ShortID(); got != "" {
+		t.Errorf("ShortID of an empty resource-id = %q", got)
+	}
+}
+
+func TestAttr(t *testing.T) {
+	x := android14Login(t)
+	n, err := x.Find(byResourceID("com.example.app:id/password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"hint":          "Password",
+		"display-id":    "0",
+		"drawing-order": "2",
+		"password":      "true",
+		"bounds":        "[64,776][1016,920]",
+		"index":         "1",
+		// 未知属性名返回空字符串
+		"Hint":       "",
+		"input-type": "",
+		"":           "",
+	}
+	for name, want := range tests {
+		if got := n.Attr(name); got != want {
+			t.Errorf("Attr(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package uixml
+
+// Diff 比较两次 dump 的 UI 树，返回新增和消失的节点。
+// 节点通过 Key() 进行匹配，同一个 Key 出现多次时按出现次数计算差异。
+//
+// 参数：
+//   - other: 较新的一次 dump（通常是操作之后获取的 Xml）
+//
+// 返回值：
+//   - added: 在 other 中出现、但在 x 中不存在的节点
+//   - removed: 在 x 中存在、但在 other 中消失的节点
+//
+// 使用场景：
+//   - 调试"点击之后界面发生了什么变化"
+//   - 判断界面是否已经稳定（added 和 removed 都为空）
+//
+// 注意事项：
+//   - Key 不包含 text，只有文本变化的节点不会出现在结果中，可结合 Equal 进一步比较
+//   - 结果顺序与各自 UI 树的遍历顺序（深度优先）一致
+//
+// 示例：
+//
+//	before, _ := device.XML()
+//	device.Tap(540, 960)
+//	after, _ := device.XML()
+//	added, removed := before.Diff(after)
+//	fmt.Printf("新增 %d 个节点，消失 %d 个节点\n", len(added), len(removed))
+func (x *Xml) Diff(other *Xml) (added, removed []Node) {
+	oldNodes := x.FindAll(matchAll)
+	newNodes := other.FindAll(matchAll)
+
+	// 统计旧树中每个 Key 出现的次数
+	counts := make(map[string]int, len(oldNodes))
+	for _, n := range oldNodes {
+		counts[n.Key()]++
+	}
+
+	// 新树中多出来的节点即为新增节点，剩余计数即为消失的节点
+	for _, n := range newNodes {
+		key := n.Key()
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		added = append(added, n)
+	}
+	for _, n := range oldNodes {
+		key := n.Key()
+		if counts[key] > 0 {
+			counts[key]--
+			removed = append(removed, n)
+		}
+	}
+	return added, removed
+}
+
+// matchAll 是匹配所有节点的条件函数。
+func matchAll(n, pn Node) bool {
+	return true
+}
@@ -0,0 +1,81 @@
+package uixml
+
+import "testing"
+
+// 点击"登录"前后的两次 dump：按钮文本变化、提示消失、出现加载动画，列表中有两个完全相同的分隔线。
+const (
+	beforeDump = `<?xml version='1.0' encoding='UTF-8' standalone='yes' ?>
+<hierarchy rotation="0">
+  <node index="0" text="" resource-id="" class="android.widget.FrameLayout" package="com.example" content-desc="" bounds="[0,0][1080,2400]">
+    <node index="0" text="登录" resource-id="com.example:id/login" class="android.widget.Button" package="com.example" content-desc="" clickable="true" bounds="[100,1000][980,1150]" />
+    <node index="1" text="请输入手机号" resource-id="com.example:id/tip" class="android.widget.TextView" package="com.example" content-desc="" bounds="[100,900][980,960]" />
+    <node index="2" text="" resource-id="" class="android.view.View" package="com.example" content-desc="divider" bounds="[0,1200][1080,1202]" />
+    <node index="3" text="" resource-id="" class="android.view.View" package="com.example" content-desc="divider" bounds="[0,1200][1080,1202]" />
+  </node>
+</hierarchy>`
+	afterDump = `<?xml version='1.0' encoding='UTF-8' standalone='yes' ?>
+<hierarchy rotation="0">
+  <node index="0" text="" resource-id="" class="android.widget.FrameLayout" package="com.example" content-desc="" bounds="[0,0][1080,2400]">
+    <node index="0" text="登录中..." resource-id="com.example:id/login" class="android.widget.Button" package="com.example" content-desc="" clickable="false" bounds="[100,1000][980,1150]" />
+    <node index="1" text="" resource-id="com.example:id/progress" class="android.widget.ProgressBar" package="com.example" content-desc="" bounds="[490,1300][590,1400]" />
+    <node index="2" text="" resource-id="" class="android.view.View" package="com.example" content-desc="divider" bounds="[0,1200][1080,1202]" />
+  </node>
+</hierarchy>`
+)
+
+func mustParse(t *testing.T, data string) *Xml {
+	t.Helper()
+	x, err := NewXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func TestNodeKeyIgnoresText(t *testing.T) {
+	before := mustParse(t, beforeDump)
+	after := mustParse(t, afterDump)
+	byID := func(n, pn Node) bool { return n.ResourceID == "com.example:id/login" }
+	b, _ := before.Find(byID)
+	a, _ := after.Find(byID)
+
+	if b.Key() != a.Key() {
+		t.Errorf("Key changed with text: %q != %q", b.Key(), a.Key())
+	}
+	if want := "android.widget.Button|com.example:id/login||[100,1000][980,1150]"; b.Key() != want {
+		t.Errorf("Key() = %q, want %q", b.Key(), want)
+	}
+	if b.Equal(a) {
+		t.Error("Equal reports nodes with different text and clickable as equal")
+	}
+	if !b.Equal(b) {
+		t.Error("node is not Equal to itself")
+	}
+}
+
+func TestNodeEqualIgnoresChildren(t *testing.T) {
+	root := mustParse(t, beforeDump).Nodes[0]
+	bare := root
+	bare.Children = nil
+	if !root.Equal(bare) {
+		t.Error("Equal compares Children")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := mustParse(t, beforeDump)
+	after := mustParse(t, afterDump)
+
+	added, removed := before.Diff(after)
+	if len(added) != 1 || added[0].ResourceID != "com.example:id/progress" {
+		t.Errorf("added = %+v, want only the progress bar", added)
+	}
+	// 提示消失；两个相同的分隔线少了一个，按出现次数计算
+	if len(removed) != 2 || removed[0].ResourceID != "com.example:id/tip" || removed[1].ContentDesc != "divider" {
+		t.Errorf("removed = %+v, want the tip and one divider", removed)
+	}
+
+	if added, removed := before.Diff(before); len(added) != 0 || len(removed) != 0 {
+		t.Errorf("Diff with itself = %d added, %d removed", len(added), len(removed))
+	}
+}
@@ -1,6 +1,10 @@
 package uixml
 
-import "fmt"
+import "errors"
+
+// ErrNotFound 表示没有找到匹配的节点。
+// 可以通过 errors.Is(err, uixml.ErrNotFound) 判断，adb 包中的 adb.ErrNotFound 与它是同一个值。
+var ErrNotFound = errors.New("not found")
 
 // FindButton 根据 content-desc 查找可点击的按钮节点。
 // 该方法是 Find 方法的便捷封装，专门用于查找按钮元素。
@@ -10,7 +14,7 @@ import "fmt"
 //
 // 返回值：
 //   - Node: 第一个匹配的按钮节点
-//   - error: 如果没有找到按钮，返回 "not found" 错误
+//   - error: 如果没有找到按钮，返回 ErrNotFound
 //
 // 查找条件：
 //   - 元素的 ContentDesc 必须等于 name
@@ -53,7 +57,7 @@ func (x *Xml) FindButton(name string) (Node, error) {
 //
 // 返回值：
 //   - Node: 第一个匹配的节点对象
-//   - error: 如果没有找到匹配的节点，返回 "not found" 错误
+//   - error: 如果没有找到匹配的节点，返回 ErrNotFound
 //
 // 查找过程：
 //  1. 遍历根节点下的所有直接子节点
@@ -98,7 +102,7 @@ func (x *Xml) Find(fn func(n, pn Node) bool) (Node, error) {
 		}
 	}
 	// 没有找到匹配的节点
-	return Node{}, fmt.Errorf("not found")
+	return Node{}, ErrNotFound
 }
 
 // FindAll 使用自定义条件函数查找所有匹配的 UI 节点。
@@ -158,6 +162,145 @@ func (x *Xml) FindAll(fn func(n, pn Node) bool) []Node {
 	return out
 }
 
+// Count 统计满足条件的节点数量。
+// 与 len(x.FindAll(fn)) 结果相同，但遍历时只计数，不会把匹配的节点复制到切片中。
+//
+// 参数：
+//   - fn: 自定义的节点匹配函数
+//
+// 返回值：
+//   - int: 匹配的节点数量
+//
+// 示例：
+//
+//	items := xml.Count(func(n, pn Node) bool {
+//	    return pn.ResourceID == "com.example:id/list"
+//	})
+func (x *Xml) Count(fn func(n, pn Node) bool) int {
+	count := 0
+	for _, node := range x.Nodes {
+		Walk(node, Node{}, func(n, pn Node) {
+			if fn(n, pn) {
+				count++
+			}
+		})
+	}
+	return count
+}
+
+// FindIn 在 root 的子树中查找所有满足条件的后代节点（不包括 root 本身）。
+// 与 FindNodeFunc 的 pn 参数只能看到直接父节点不同，它可以把整个查询限定在某个容器内。
+//
+// 参数：
+//   - root: 作为查找范围的节点，通常先用 Find 找到
+//   - fn: 自定义的节点匹配函数
+//
+// 返回值：
+//   - []Node: root 的后代中所有匹配的节点，按深度优先顺序排列
+//
+// 示例：
+//
+//	list, _ := xml.Find(func(n, pn Node) bool {
+//	    return n.ResourceID == "com.example:id/list"
+//	})
+//	titles := xml.FindIn(list, func(n, pn Node) bool {
+//	    return n.ResourceID == "com.example:id/title"
+//	})
+func (x *Xml) FindIn(root Node, fn func(n, pn Node) bool) []Node {
+	var out []Node
+	for _, c := range root.Children {
+		out = append(out, FindAll(c, root, fn)...)
+	}
+	return out
+}
+
+// Ancestors 返回节点的所有祖先节点，从直接父节点到最外层依次排列。
+// FindNodeFunc 的 pn 参数只能看到直接父节点，需要向上查找多层时（例如找到文字所在的可点击行）使用该方法。
+//
+// 参数：
+//   - target: 要查找祖先的节点，按 Equal 比较（通常来自同一个 Xml 的 Find）
+//
+// 返回值：
+//   - []Node: 祖先节点列表；target 不在树中或是最外层节点时返回空切片
+//
+// 示例：
+//
+//	label, _ := xml.Find(func(n, pn Node) bool { return n.Text == "通知" })
+//	for _, a := range xml.Ancestors(label) {
+//	    if a.IsClickable() {
+//	        fmt.Println("可点击的行:", a.Bounds)
+//	        break
+//	    }
+//	}
+func (x *Xml) Ancestors(target Node) []Node {
+	var path []Node
+	var found []Node
+	var visit func(n Node) bool
+	visit = func(n Node) bool {
+		if n.Equal(target) {
+			found = make([]Node, 0, len(path))
+			for i := len(path) - 1; i >= 0; i-- {
+				found = append(found, path[i])
+			}
+			return true
+		}
+		path = append(path, n)
+		for _, c := range n.Children {
+			if visit(c) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	for _, node := range x.Nodes {
+		if visit(node) {
+			return found
+		}
+	}
+	return []Node{}
+}
+
+// NodeAt 返回覆盖屏幕坐标 (px, py) 的最内层节点，是 Node.Middle 的逆操作。
+// 用于根据 OCR、图像识别等外部工具给出的坐标找到对应的 UI 元素，或排查"刚才点到了什么"。
+//
+// 参数：
+//   - px, py: 当前屏幕方向下的坐标（与 bounds 使用同一坐标系）
+//
+// 返回值：
+//   - Node: 包含该点的节点中面积最小的一个；面积相同时取树中更深的节点
+//   - bool: 没有节点包含该点时返回 false
+//
+// 注意事项：
+//   - 只按 bounds 判断，不考虑可见性：被弹窗遮住的节点、透明的覆盖层同样可能被返回
+//   - bounds 为空或无法解析的节点会被跳过
+//
+// 示例：
+//
+//	if node, ok := xml.NodeAt(540, 1200); ok {
+//	    fmt.Println(node.Class, node.ResourceID, node.Text)
+//	}
+func (x *Xml) NodeAt(px, py int) (Node, bool) {
+	var best Node
+	bestArea, bestDepth := -1, -1
+	var visit func(n Node, depth int)
+	visit = func(n Node, depth int) {
+		if r, err := ParseBounds(n.Bounds); err == nil && r.Contains(px, py) {
+			area := r.Area()
+			if bestArea < 0 || area < bestArea || (area == bestArea && depth > bestDepth) {
+				best, bestArea, bestDepth = n, area, depth
+			}
+		}
+		for _, c := range n.Children {
+			visit(c, depth+1)
+		}
+	}
+	for _, node := range x.Nodes {
+		visit(node, 0)
+	}
+	return best, bestArea >= 0
+}
+
 // FindAll 在指定的节点树中查找所有满足条件的节点。
 // 该函数递归遍历节点树，收集所有匹配的节点。
 //
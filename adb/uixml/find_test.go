@@ -0,0 +1,98 @@
+package uixml
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// listDump 返回一个包含 n 行的列表，每行有一个标题和一个按钮。
+func listDump(n int) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version='1.0' encoding='UTF-8' standalone='yes' ?><hierarchy rotation="0">`)
+	b.WriteString(`<node class="androidx.recyclerview.widget.RecyclerView" resource-id="com.example:id/list" bounds="[0,0][1080,2400]">`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `<node class="android.widget.LinearLayout" bounds="[0,%d][1080,%d]">`, i*100, i*100+100)
+		fmt.Fprintf(&b, `<node class="android.widget.TextView" resource-id="com.example:id/title" text="item %d" bounds="[0,%d][800,%d]" />`, i, i*100, i*100+100)
+		fmt.Fprintf(&b, `<node class="android.widget.Button" text="删除" bounds="[800,%d][1080,%d]" />`, i*100, i*100+100)
+		b.WriteString(`</node>`)
+	}
+	b.WriteString(`</node></hierarchy>`)
+	return b.String()
+}
+
+func isTitle(n, pn Node) bool { return n.ResourceID == "com.example:id/title" }
+
+func TestCount(t *testing.T) {
+	x := mustParse(t, listDump(25))
+	if got, want := x.Count(isTitle), len(x.FindAll(isTitle)); got != want || got != 25 {
+		t.Errorf("Count = %d, len(FindAll) = %d, want 25", got, want)
+	}
+	if got := x.Count(func(n, pn Node) bool { return false }); got != 0 {
+		t.Errorf("Count(none) = %d", got)
+	}
+	if got := x.Count(matchAll); got != 1+25*3 {
+		t.Errorf("Count(all) = %d, want %d", got, 1+25*3)
+	}
+}
+
+// BenchmarkCount 比较 Count 与 len(FindAll(...)) 的内存分配：Count 不构建结果切片。
+func BenchmarkCount(b *testing.B) {
+	x, err := NewXml(listDump(200))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("Count", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			x.Count(isTitle)
+		}
+	})
+	b.Run("len(FindAll)", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = len(x.FindAll(isTitle))
+		}
+	})
+}
+
+// overlapDump 是一个列表上方弹出对话框的界面：对话框与列表行重叠，
+// 确认按钮外还包着一层同样大小的点击区域。
+const overlapDump = `<?xml version='1.0' encoding='UTF-8' standalone='yes' ?><hierarchy rotation="0">` +
+	`<node class="android.widget.FrameLayout" text="root" bounds="[0,0][1080,2400]">` +
+	`<node class="androidx.recyclerview.widget.RecyclerView" text="list" bounds="[0,0][1080,2400]">` +
+	`<node class="android.widget.TextView" text="row 0" bounds="[0,0][1080,1200]" />` +
+	`<node class="android.widget.TextView" text="row 1" bounds="[0,1200][1080,2400]" />` +
+	`</node>` +
+	`<node class="android.widget.LinearLayout" text="dialog" bounds="[100,500][980,1500]">` +
+	`<node class="android.widget.FrameLayout" text="ok area" bounds="[600,1300][900,1450]">` +
+	`<node class="android.widget.Button" text="ok" bounds="[600,1300][900,1450]" />` +
+	`</node>` +
+	`</node>` +
+	`</node></hierarchy>`
+
+func TestNodeAtPicksInnermost(t *testing.T) {
+	x := mustParse(t, overlapDump)
+	tests := []struct {
+		x, y int
+		want string
+	}{
+		{50, 50, "row 0"},     // 只被列表行覆盖
+		{50, 1300, "row 1"},   // 对话框左侧，行比列表小
+		{500, 700, "dialog"},  // 对话框覆盖在 row 0 上方，面积更小
+		{500, 1250, "dialog"}, // 对话框同时与 row 1 重叠
+		{700, 1400, "ok"},     // 按钮与外层区域面积相同，取更深的节点
+		{500, 2000, "row 1"},  // 对话框下方
+		{600, 1300, "ok"},     // 左上边界属于矩形
+		{900, 1450, "dialog"}, // 右下边界不属于按钮
+	}
+	for _, tt := range tests {
+		n, ok := x.NodeAt(tt.x, tt.y)
+		if !ok || n.Text != tt.want {
+			t.Errorf("NodeAt(%d, %d) = %q, %v; want %q", tt.x, tt.y, n.Text, ok, tt.want)
+		}
+	}
+	if n, ok := x.NodeAt(1080, 2400); ok {
+		t.Errorf("NodeAt(outside) = %q, want no node", n.Text)
+	}
+}
@@ -151,3 +151,42 @@ func ParseBounds(b string) (Rect, error) {
 	// 返回解析后的矩形结构
 	return Rect{X1: x1, Y1: y1, X2: x2, Y2: y2}, nil
 }
+
+// Width 返回矩形的宽度（X2 - X1）。
+func (r Rect) Width() int {
+	return r.X2 - r.X1
+}
+
+// Height 返回矩形的高度（Y2 - Y1）。
+func (r Rect) Height() int {
+	return r.Y2 - r.Y1
+}
+
+// Center 返回矩形的中心点坐标。
+//
+// 示例：
+//
+//	rect, _ := uixml.ParseBounds("[100,200][300,400]")
+//	x, y := rect.Center() // 200, 300
+func (r Rect) Center() (x, y int) {
+	return (r.X1 + r.X2) / 2, (r.Y1 + r.Y2) / 2
+}
+
+// Contains 判断点 (x, y) 是否落在矩形内（包含左上边界，不包含右下边界，与 Android 的 Rect.contains 一致）。
+//
+// 示例：
+//
+//	rect, _ := uixml.ParseBounds("[100,200][300,400]")
+//	rect.Contains(100, 200) // true
+//	rect.Contains(300, 400) // false
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X1 && x < r.X2 && y >= r.Y1 && y < r.Y2
+}
+
+// Area 返回矩形的面积，宽或高不为正数时返回 0。
+func (r Rect) Area() int {
+	if r.Width() <= 0 || r.Height() <= 0 {
+		return 0
+	}
+	return r.Width() * r.Height()
+}
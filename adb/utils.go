@@ -158,3 +158,14 @@ func WaitForDevice(serial string) error {
 	cmd := exec.Command("adb", args...)
 	return cmd.Run()
 }
+
+// shellQuote 将字符串转义为可以安全拼接到设备 shell 命令中的单引号字符串。
+// 字符串用单引号包裹，内部的单引号会被转义，因此空格、$、; 等特殊字符都不会被 shell 解释。
+//
+// 示例：
+//
+//	shellQuote("hello world") // 'hello world'
+//	shellQuote("it's")        // 'it'\''s'
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,319 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LucaHhx/adb/adb/uixml"
+)
+
+// defaultPollInterval 是轮询等待类方法两次检查之间的默认间隔，可通过 WithPollInterval 修改。
+// 每次检查都需要重新 dump 屏幕，间隔过短会明显增加设备负担。
+const defaultPollInterval = 500 * time.Millisecond
+
+// pollEvery 返回轮询等待的间隔。
+func (d *Device) pollEvery() time.Duration {
+	if d.pollInterval > 0 {
+		return d.pollInterval
+	}
+	return defaultPollInterval
+}
+
+// WaitForText 轮询等待匹配节点的文本变为期望值。
+// 适用于先显示 "Loading..." 再显示真实内容的界面，比只判断元素是否存在更精确。
+//
+// 参数：
+//   - fn: 定位目标节点的查找函数
+//   - expected: 期望的文本；节点的 Text 或 ContentDesc 包含该字符串即视为满足（相等自然也满足）
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - uixml.Node: 文本满足条件时的节点
+//   - error: 超时返回 error 对象，错误信息中包含最后一次看到的文本
+//
+// 注意事项：
+//   - 每隔轮询间隔（默认 500 毫秒，见 WithPollInterval）dump 一次屏幕，dump 的指纹与上一次相同时不再解析 XML
+//   - 轮询期间 dump 失败或节点暂时不存在都会继续等待，直到超时
+//   - expected 为空字符串时，只要节点出现即返回
+//
+// 示例：
+//
+//	// 等待余额加载完成
+//	node, err := device.WaitForText(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/balance"
+//	}, "元", 10*time.Second)
+//	if err != nil {
+//	    log.Fatal(err) // 例如：wait for text "元" timeout after 10s, last seen: "Loading..."
+//	}
+//	fmt.Println("余额:", node.Text)
+func (d *Device) WaitForText(fn FindNodeFunc, expected string, timeout time.Duration) (uixml.Node, error) {
+	deadline := time.Now().Add(timeout)
+	lastSeen := "<not found>"
+	poll := dumpPoller{d: d}
+	for {
+		// 界面没有变化时复用上一次的解析结果
+		if node, err := poll.find(fn); err == nil {
+			if strings.Contains(node.Text, expected) || strings.Contains(node.ContentDesc, expected) {
+				return node, nil
+			}
+			lastSeen = node.Text
+			if lastSeen == "" {
+				lastSeen = node.ContentDesc
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return uixml.Node{}, fmt.Errorf("wait for text %q timeout after %s, last seen: %q", expected, timeout, lastSeen)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// WaitForTextChange 记录节点当前的文本，然后轮询等待文本发生变化。
+// 用于确认某个操作触发了界面更新（例如交易后余额减少、倒计时跳动），不需要预先知道新的值。
+//
+// 参数：
+//   - fn: 定位目标节点的查找函数
+//   - timeout: 从记录初始文本开始的最长等待时间
+//
+// 返回值：
+//   - old: 开始时的文本
+//   - new: 变化后的文本
+//   - err: 如果开始时找不到节点，或超时仍未变化（错误信息中包含未变化的文本），返回 error 对象
+//
+// 注意事项：
+//   - 节点的 Text 为空时使用 ContentDesc
+//   - 轮询期间节点暂时消失会继续等待，不视为变化
+//   - 初始文本在调用时记录，如果操作很快就完成了更新，调用时看到的已经是新值，此时应改用 WaitForText 等待期望值
+//
+// 示例：
+//
+//	// 确认倒计时在走
+//	countdown := func(n, pn uixml.Node) bool { return n.ResourceID == "com.example:id/countdown" }
+//	old, new, err := device.WaitForTextChange(countdown, 3*time.Second)
+//	if err == nil {
+//	    fmt.Printf("倒计时从 %s 变为 %s\n", old, new)
+//	}
+func (d *Device) WaitForTextChange(fn FindNodeFunc, timeout time.Duration) (old, new string, err error) {
+	deadline := time.Now().Add(timeout)
+	node, err := d.FindNode(fn)
+	if err != nil {
+		return "", "", err
+	}
+	old = nodeText(node)
+	poll := dumpPoller{d: d}
+	for {
+		time.Sleep(d.pollEvery())
+		if node, err := poll.find(fn); err == nil {
+			if text := nodeText(node); text != old {
+				return old, text, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return old, old, fmt.Errorf("wait for text change timeout after %s, still %q", timeout, old)
+		}
+	}
+}
+
+// nodeText 返回节点的 Text，为空时返回 ContentDesc。
+func nodeText(node uixml.Node) string {
+	if node.Text != "" {
+		return node.Text
+	}
+	return node.ContentDesc
+}
+
+// Comparison 表示数量比较方式，用于 WaitForElementCount。
+type Comparison string
+
+const (
+	EqualTo     Comparison = "=="
+	NotEqualTo  Comparison = "!="
+	GreaterThan Comparison = ">"
+	AtLeast     Comparison = ">="
+	LessThan    Comparison = "<"
+	AtMost      Comparison = "<="
+)
+
+// compare 判断 actual 与 expected 是否满足比较关系。
+func (c Comparison) compare(actual, expected int) (bool, error) {
+	switch c {
+	case EqualTo:
+		return actual == expected, nil
+	case NotEqualTo:
+		return actual != expected, nil
+	case GreaterThan:
+		return actual > expected, nil
+	case AtLeast:
+		return actual >= expected, nil
+	case LessThan:
+		return actual < expected, nil
+	case AtMost:
+		return actual <= expected, nil
+	}
+	return false, fmt.Errorf("bad comparison %q", string(c))
+}
+
+// WaitForElementCount 轮询等待匹配节点的数量满足比较条件。
+// 适用于等待列表加载出指定数量的条目、等待加载指示器全部消失等场景。
+//
+// 参数：
+//   - fn: 节点查找函数
+//   - count: 期望的数量
+//   - cmp: 比较方式，例如 AtLeast 表示匹配数量 >= count
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - []uixml.Node: 满足条件时匹配到的所有节点（数量可以为 0）
+//   - error: 超时返回 error 对象，错误信息中包含最后一次的匹配数量
+//
+// 注意事项：
+//   - 每隔轮询间隔（默认 500 毫秒，见 WithPollInterval）dump 一次屏幕，dump 的指纹与上一次相同时不再解析 XML
+//   - 只统计当前屏幕上可见的节点，列表中未渲染的条目不计入
+//
+// 示例：
+//
+//	// 等待列表至少加载 20 行
+//	rows, err := device.WaitForElementCount(func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/item_title"
+//	}, 20, adb.AtLeast, 15*time.Second)
+//
+//	// 等待所有加载指示器消失
+//	_, err = device.WaitForElementCount(func(n, pn uixml.Node) bool {
+//	    return n.Class == "android.widget.ProgressBar"
+//	}, 0, adb.EqualTo, 10*time.Second)
+func (d *Device) WaitForElementCount(fn FindNodeFunc, count int, cmp Comparison, timeout time.Duration) ([]uixml.Node, error) {
+	// 提前校验比较方式，避免等到超时才发现参数错误
+	if _, err := cmp.compare(0, count); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	lastCount := -1
+	poll := dumpPoller{d: d}
+	for {
+		if xml, changed, err := poll.next(); err == nil && changed {
+			nodes := xml.FindAll(fn)
+			lastCount = len(nodes)
+			if ok, _ := cmp.compare(lastCount, count); ok {
+				return nodes, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("wait for element count %s %d timeout after %s, last count: %d", cmp, count, timeout, lastCount)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// WaitForElement 轮询等待匹配的节点出现。
+//
+// 参数：
+//   - fn: 节点查找函数
+//   - timeout: 最长等待时间
+//
+// 返回值：
+//   - uixml.Node: 第一个匹配的节点
+//   - error: 超时返回 error 对象
+//
+// 注意事项：
+//   - 每隔轮询间隔（默认 500 毫秒，见 WithPollInterval）dump 一次屏幕，轮询期间 dump 失败会继续等待；
+//     dump 的指纹与上一次相同时不再解析 XML
+//
+// 示例：
+//
+//	node, err := device.WaitForElement(adb.ByHint("手机号"), 10*time.Second)
+//	if err == nil {
+//	    device.ClickNodeBy(node)
+//	}
+func (d *Device) WaitForElement(fn FindNodeFunc, timeout time.Duration) (uixml.Node, error) {
+	deadline := time.Now().Add(timeout)
+	poll := dumpPoller{d: d}
+	for {
+		node, err := poll.find(fn)
+		if err == nil {
+			return node, nil
+		}
+		if time.Now().After(deadline) {
+			return uixml.Node{}, fmt.Errorf("wait for element timeout after %s: %w", timeout, err)
+		}
+		time.Sleep(d.pollEvery())
+	}
+}
+
+// TapAndWaitFor 点击一个节点，然后等待目标节点出现，是 "点击后进入新页面" 这一常见步骤的封装。
+//
+// 参数：
+//   - tapSel: 要点击的节点的查找函数
+//   - waitSel: 点击后期望出现的节点的查找函数
+//   - timeout: 整个过程的最长等待时间（等待点击目标出现 + 等待目标页面出现）
+//
+// 返回值：
+//   - uixml.Node: 点击后出现的目标节点
+//   - error: 点击目标始终未出现时错误以 "tap:" 开头；
+//     点击成功但目标节点未出现时错误以 "wait:" 开头
+//
+// 注意事项：
+//   - 点击目标不存在时同样会在 timeout 内等待它出现
+//   - 如果 waitSel 在点击前就已经存在（例如两个页面有相同的元素），会立即返回，
+//     这种情况下应选择只在目标页面出现的元素
+//
+// 示例：
+//
+//	node, err := device.TapAndWaitFor(
+//	    func(n, pn uixml.Node) bool { return n.Text == "设置" },
+//	    func(n, pn uixml.Node) bool { return n.Text == "通用" },
+//	    10*time.Second,
+//	)
+//	if err != nil {
+//	    log.Fatal(err) // 例如：wait: wait for element timeout after 7.5s: not found
+//	}
+func (d *Device) TapAndWaitFor(tapSel, waitSel FindNodeFunc, timeout time.Duration) (uixml.Node, error) {
+	start := time.Now()
+	node, err := d.WaitForElement(tapSel, timeout)
+	if err != nil {
+		return uixml.Node{}, fmt.Errorf("tap: %w", err)
+	}
+	if err := d.ClickNodeBy(node); err != nil {
+		return uixml.Node{}, fmt.Errorf("tap: %w", err)
+	}
+	return d.waitAfterTap(waitSel, timeout-time.Since(start))
+}
+
+// ClickNodeAndWait 按类名和描述/文本点击节点（匹配规则与 ClickNode 相同），然后等待目标节点出现。
+//
+// 参数：
+//   - class: UI 元素的类名，为空则不限制
+//   - desc: UI 元素的 content-desc 或 text，为空则不限制
+//   - waitSel: 点击后期望出现的节点的查找函数
+//   - timeout: 整个过程的最长等待时间
+//
+// 返回值：
+//   - uixml.Node: 点击后出现的目标节点
+//   - error: 错误区分方式与 TapAndWaitFor 相同
+//
+// 示例：
+//
+//	_, err := device.ClickNodeAndWait("android.widget.Button", "登录", func(n, pn uixml.Node) bool {
+//	    return n.ResourceID == "com.example:id/home_tab"
+//	}, 15*time.Second)
+func (d *Device) ClickNodeAndWait(class, desc string, waitSel FindNodeFunc, timeout time.Duration) (uixml.Node, error) {
+	return d.TapAndWaitFor(func(n, pn uixml.Node) bool {
+		return (class == "" || n.Class == class) &&
+			(desc == "" || n.ContentDesc == desc || n.Text == desc)
+	}, waitSel, timeout)
+}
+
+// waitAfterTap 在点击后等待目标节点，剩余时间不足时至少检查一次。
+func (d *Device) waitAfterTap(waitSel FindNodeFunc, remaining time.Duration) (uixml.Node, error) {
+	if remaining < 0 {
+		remaining = 0
+	}
+	node, err := d.WaitForElement(waitSel, remaining)
+	if err != nil {
+		return uixml.Node{}, fmt.Errorf("wait: %w", err)
+	}
+	return node, nil
+}